@@ -0,0 +1,14 @@
+provider gcore {
+  permanent_api_token = "251$d3361.............1b35f26d8"
+}
+
+data "gcore_cdn_rules" "cdn_example_com" {
+  resource_id = gcore_cdn_resource.cdn_example_com.id
+}
+
+// the import IDs of the rules created in the portal
+output "rule_import_ids" {
+  value = {
+    for r in data.gcore_cdn_rules.cdn_example_com.rules : r.name => "${data.gcore_cdn_rules.cdn_example_com.resource_id}:${r.id}"
+  }
+}
@@ -0,0 +1,11 @@
+provider gcore {
+  permanent_api_token = "251$d3361.............1b35f26d8"
+}
+
+data "gcore_dns_zone" "shared" {
+  name = "examplezone.com"
+}
+
+output "zone_file" {
+  value = data.gcore_dns_zone.shared.zone_file
+}
@@ -0,0 +1,36 @@
+provider gcore {
+  permanent_api_token = "251$d3361.............1b35f26d8"
+}
+
+data "gcore_dns_zone_file" "migrated" {
+  content = file("${path.module}/examplezone.com.zone")
+  origin  = "examplezone.com"
+}
+
+resource "gcore_dns_zone" "migrated" {
+  name = data.gcore_dns_zone_file.migrated.zone
+}
+
+resource "gcore_dns_zone_record" "migrated" {
+  for_each = {
+    for r in data.gcore_dns_zone_file.migrated.records : "${r.domain} ${r.type}" => r
+  }
+
+  zone   = gcore_dns_zone.migrated.name
+  domain = each.value.domain
+  type   = each.value.type
+  ttl    = each.value.ttl
+
+  dynamic "resource_record" {
+    for_each = each.value.resource_record
+    content {
+      content = resource_record.value.content
+      enabled = resource_record.value.enabled
+    }
+  }
+}
+
+// the zone in BIND format, e.g. to move it to another DNS host
+output "zone_file" {
+  value = gcore_dns_zone.migrated.zone_file
+}
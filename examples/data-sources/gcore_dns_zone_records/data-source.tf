@@ -0,0 +1,18 @@
+provider gcore {
+  permanent_api_token = "251$d3361.............1b35f26d8"
+}
+
+data "gcore_dns_zone_records" "www" {
+  zone   = "examplezone.com"
+  domain = "www.examplezone.com"
+  type   = "A"
+}
+
+// the addresses of www managed by another configuration
+output "www_addresses" {
+  value = flatten([
+    for r in data.gcore_dns_zone_records.www.records : [
+      for rr in r.resource_record : rr.content if rr.enabled
+    ]
+  ])
+}
@@ -0,0 +1,16 @@
+provider gcore {
+  permanent_api_token = "251$d3361.............1b35f26d8"
+}
+
+data "gcore_k8s_config" "v" {
+  project_id = 1
+  region_id  = 1
+  cluster_id = "dc3a3ea9-86ae-47ad-a8e8-79df0ce04839"
+}
+
+provider "kubernetes" {
+  host                   = data.gcore_k8s_config.v.host
+  cluster_ca_certificate = data.gcore_k8s_config.v.cluster_ca_certificate
+  client_certificate     = data.gcore_k8s_config.v.client_certificate
+  client_key             = data.gcore_k8s_config.v.client_key
+}
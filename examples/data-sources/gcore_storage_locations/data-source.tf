@@ -0,0 +1,14 @@
+provider gcore {
+  permanent_api_token = "251$d3361.............1b35f26d8"
+}
+
+data "gcore_storage_locations" "s3" {
+  type = "s3"
+}
+
+resource "gcore_storage_s3" "example_s3" {
+  name = "example"
+  location = [
+    for l in data.gcore_storage_locations.s3.locations : l.name if l.allow_for_new_storage
+  ][0]
+}
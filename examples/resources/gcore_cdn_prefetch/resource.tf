@@ -0,0 +1,15 @@
+provider gcore {
+  permanent_api_token = "251$d3361.............1b35f26d8"
+}
+
+resource "gcore_cdn_prefetch" "videos" {
+  resource_id = gcore_cdn_resource.cdn_example_com.id
+  paths = [
+    "/video/intro.mp4",
+    "/video/trailer.mp4",
+  ]
+
+  triggers = {
+    release = var.release
+  }
+}
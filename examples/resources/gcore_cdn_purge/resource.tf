@@ -0,0 +1,12 @@
+provider gcore {
+  permanent_api_token = "251$d3361.............1b35f26d8"
+}
+
+resource "gcore_cdn_purge" "static" {
+  resource_id = gcore_cdn_resource.cdn_example_com.id
+  paths       = ["/static/*"]
+
+  triggers = {
+    release = var.release
+  }
+}
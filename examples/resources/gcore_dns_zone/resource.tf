@@ -3,5 +3,11 @@ provider gcore {
 }
 
 resource "gcore_dns_zone" "example_zone" {
-  name = "example_zone.com"
+  name   = "example_zone.com"
+  dnssec = true
+}
+
+// publish it at the registrar of the zone
+output "ds" {
+  value = gcore_dns_zone.example_zone.dnssec_ds
 }
@@ -83,3 +83,25 @@ resource "gcore_dns_zone_record" "subdomain_examplezone_caa" {
     enabled = true
   }
 }
+
+resource "gcore_dns_zone_record" "examplezone_https" {
+  zone   = "examplezone.com"
+  domain = "examplezone.com"
+  type   = "HTTPS"
+  ttl    = 300
+
+  resource_record {
+    content = "1 . alpn=h2,h3 port=443 ipv4hint=192.0.2.1"
+  }
+}
+
+resource "gcore_dns_zone_record" "reverse_ptr" {
+  zone   = "2.0.192.in-addr.arpa"
+  domain = "1.2.0.192.in-addr.arpa"
+  type   = "PTR"
+  ttl    = 300
+
+  resource_record {
+    content = "examplezone.com."
+  }
+}
@@ -29,3 +29,17 @@ EOF
         min_instances = 1
         max_instances = 2
 }
+
+resource "gcore_faas_function" "archive" {
+        project_id = 1
+        region_id = 1
+        name = "testf-archive"
+        namespace = "ns4test"
+        runtime = "python3.7"
+        code_archive = "${path.module}/function"
+        timeout = 5
+        flavor = "80mCPU-128MB"
+        main_method = "main"
+        min_instances = 1
+        max_instances = 2
+}
@@ -8,6 +8,7 @@ resource "gcore_k8s" "v" {
   name          = "tf-k8s"
   fixed_network = "6bf878c1-1ce4-47c3-a39b-6b5f1d79bf25"
   fixed_subnet  = "dc3a3ea9-86ae-47ad-a8e8-79df0ce04839"
+  version       = "1.20.6"
   pool {
     name               = "tf-pool"
     flavor_id          = "g1-standard-1-2"
@@ -16,5 +17,12 @@ resource "gcore_k8s" "v" {
     node_count         = 1
     docker_volume_size = 2
   }
+  pool {
+    name           = "tf-pool-2"
+    flavor_id      = "g1-standard-2-4"
+    min_node_count = 1
+    max_node_count = 3
+    node_count     = 2
+  }
 }
 
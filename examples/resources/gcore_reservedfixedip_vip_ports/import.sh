@@ -0,0 +1,2 @@
+# import using <project_id>:<region_id>:<reservedfixedip_id> format
+terraform import gcore_reservedfixedip_vip_ports.vip_ports 1:6:447d2959-8ae0-4ca0-8d47-9f050a3637d7
@@ -0,0 +1,22 @@
+provider gcore {
+  permanent_api_token = "251$d3361.............1b35f26d8"
+}
+
+resource "gcore_reservedfixedip" "vip" {
+  project_id = 1
+  region_id  = 1
+  type       = "subnet"
+  subnet_id  = gcore_subnet.subnet.id
+  is_vip     = true
+}
+
+// the ports of the keepalived pair share the address of the VIP
+resource "gcore_reservedfixedip_vip_ports" "vip_ports" {
+  project_id           = 1
+  region_id            = 1
+  reserved_fixed_ip_id = gcore_reservedfixedip.vip.port_id
+  port_ids             = [
+    gcore_instance.master.interface[0].port_id,
+    gcore_instance.backup.interface[0].port_id,
+  ]
+}
@@ -0,0 +1,2 @@
+# import using <project_id>:<region_id>:<securitygroup_id>:<rule_id> format
+terraform import gcore_securitygroup_rule.rule1 1:6:447d2959-8ae0-4ca0-8d47-9f050a3637d7:3a2b1c0d-5e6f-4a7b-8c9d-0e1f2a3b4c5d
\ No newline at end of file
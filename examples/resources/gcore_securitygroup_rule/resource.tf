@@ -0,0 +1,40 @@
+provider gcore {
+  permanent_api_token = "251$d3361.............1b35f26d8"
+}
+
+resource "gcore_securitygroup" "lb" {
+  name       = "lb"
+  region_id  = 1
+  project_id = 1
+
+  security_group_rules {
+    direction = "egress"
+    ethertype = "IPv4"
+    protocol  = "any"
+  }
+}
+
+resource "gcore_securitygroup" "web" {
+  name       = "web"
+  region_id  = 1
+  project_id = 1
+
+  security_group_rules {
+    direction = "egress"
+    ethertype = "IPv4"
+    protocol  = "any"
+  }
+}
+
+// the rule can be added to a security group managed in another module
+resource "gcore_securitygroup_rule" "http_from_lb" {
+  region_id         = 1
+  project_id        = 1
+  security_group_id = gcore_securitygroup.web.id
+  direction         = "ingress"
+  ethertype         = "IPv4"
+  protocol          = "tcp"
+  port_range_min    = 80
+  port_range_max    = 80
+  remote_group_id   = gcore_securitygroup.lb.id
+}
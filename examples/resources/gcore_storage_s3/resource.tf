@@ -5,4 +5,7 @@ provider gcore {
 resource "gcore_storage_s3" "example_s3" {
   name     = "example"
   location = "s-ed1"
+
+  // change to generate new keys, the buckets are kept
+  rotate_keys_trigger = "2022-01"
 }
@@ -5,4 +5,14 @@ provider gcore {
 resource "gcore_storage_s3_bucket" "example_s3_bucket" {
   name       = "example1bucket2name"
   storage_id = 1
+
+  cors {
+    allowed_origins = ["https://example.com"]
+  }
+
+  lifecycle_rule {
+    expiration_days = 30
+  }
+
+  policy = "public-read"
 }
@@ -0,0 +1,36 @@
+provider gcore {
+  permanent_api_token = "251$d3361.............1b35f26d8"
+}
+
+resource "gcore_storage_s3" "example_s3" {
+  name     = "example"
+  location = "s-ed1"
+}
+
+resource "gcore_storage_s3_bucket" "example_s3_bucket" {
+  name       = "static"
+  storage_id = gcore_storage_s3.example_s3.storage_id
+  policy     = "public-read"
+}
+
+resource "gcore_storage_s3_object" "example_s3_object" {
+  s3_endpoint = gcore_storage_s3.example_s3.generated_s3_endpoint
+  access_key  = gcore_storage_s3.example_s3.generated_access_key
+  secret_key  = gcore_storage_s3.example_s3.generated_secret_key
+
+  bucket        = gcore_storage_s3_bucket.example_s3_bucket.name
+  key           = "js/app.js"
+  source        = "${path.module}/dist/app.js"
+  cache_control = "max-age=3600"
+}
+
+resource "gcore_storage_s3_object" "example_s3_config" {
+  s3_endpoint = gcore_storage_s3.example_s3.generated_s3_endpoint
+  access_key  = gcore_storage_s3.example_s3.generated_access_key
+  secret_key  = gcore_storage_s3.example_s3.generated_secret_key
+
+  bucket       = gcore_storage_s3_bucket.example_s3_bucket.name
+  key          = "config.json"
+  content      = jsonencode({ version = "1.2.0" })
+  content_type = "application/json"
+}
@@ -0,0 +1,758 @@
+package gcore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	storageSDK "github.com/G-Core/gcore-storage-sdk-go"
+	"github.com/G-Core/gcore-storage-sdk-go/swagger/client/key"
+	"github.com/G-Core/gcore-storage-sdk-go/swagger/client/storage"
+	"github.com/G-Core/gcore-storage-sdk-go/swagger/models"
+	gcdn "github.com/G-Core/gcorelabscdn-go"
+	"github.com/G-Core/gcorelabscdn-go/gcore"
+	"github.com/G-Core/gcorelabscdn-go/rules"
+)
+
+const (
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = "1s"
+
+	// maxRetryBackoff caps the exponential backoff, Retry-After isn't capped.
+	maxRetryBackoff = 30 * time.Second
+)
+
+// retryPolicy is the provider-level policy of the requests of all API
+// clients: Cloud, CDN, DNS and Storage.
+type retryPolicy struct {
+	// maxRetries is the number of retries of a failed idempotent request.
+	maxRetries int
+	// backoff is the base delay of the exponential backoff.
+	backoff time.Duration
+	// requestsPerSecond limits the request rate, 0 means unlimited.
+	requestsPerSecond int
+}
+
+// delay returns how long to wait before the retry following the attempt,
+// the server's Retry-After wins over the jittered exponential backoff.
+func (p retryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return d
+		}
+	}
+
+	d := p.backoff
+	for i := 0; i < attempt && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Half of the delay is random, so the clients of a run that failed
+	// together don't retry together.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// parseRetryAfter parses the Retry-After header, either delay seconds or
+// an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		d := time.Until(date)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// isIdempotentMethod reports whether a request with the method can be sent
+// again without side effects, see RFC 7231 section 4.2.2.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isRetryableResponse reports whether the request failed because of a
+// transient error: a network error, throttling or an unavailable server.
+func isRetryableResponse(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryTransport is an http.RoundTripper applying the retry policy: it
+// limits the request rate and retries the idempotent requests which failed
+// with a transient error.
+type retryTransport struct {
+	next    http.RoundTripper
+	policy  retryPolicy
+	limiter *rateLimiter
+}
+
+func newRetryTransport(next http.RoundTripper, policy retryPolicy) *retryTransport {
+	return &retryTransport{
+		next:    next,
+		policy:  policy,
+		limiter: newRateLimiter(policy.requestsPerSecond),
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	retryable := isIdempotentMethod(req.Method) && replayable
+
+	r := req
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		if err := t.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+		resp, err := t.next.RoundTrip(r)
+		if !retryable || attempt >= t.policy.maxRetries || ctx.Err() != nil || !isRetryableResponse(resp, err) {
+			return resp, err
+		}
+
+		delay := t.policy.delay(attempt, resp)
+		if err != nil {
+			log.Printf("[DEBUG] %s %s: %s, retry %d in %s", req.Method, req.URL, err, attempt+1, delay)
+		} else {
+			log.Printf("[DEBUG] %s %s: %s, retry %d in %s", req.Method, req.URL, resp.Status, attempt+1, delay)
+			// Drain the body, so the connection is reused.
+			_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// rateLimiter spaces the requests evenly, a nil limiter doesn't limit.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond int) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Second / time.Duration(requestsPerSecond)}
+}
+
+// wait blocks until the next request is allowed or the context is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	d := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// userAgentTransport sets the User-Agent header of the requests.
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+func (t userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
+
+// cdnRequester is the gcore.Requester of the CDN service. It does what the
+// SDK's provider.Client does, but through the HTTP client of the provider,
+// which the SDK doesn't allow to replace.
+type cdnRequester struct {
+	client  *http.Client
+	baseURL string
+	signer  gcore.RequestSignerFunc
+}
+
+func (c *cdnRequester) Request(ctx context.Context, method, path string, payload interface{}, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		payloadBuf := new(bytes.Buffer)
+		if err := json.NewEncoder(payloadBuf).Encode(payload); err != nil {
+			return fmt.Errorf("encode req payload: %w", err)
+		}
+
+		body = payloadBuf
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.signer != nil {
+		if err := c.signer.Sign(req); err != nil {
+			return fmt.Errorf("do request: %w", err)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		var errResp gcore.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+			return fmt.Errorf("decode err resp %d: %w", resp.StatusCode, err)
+		}
+
+		return &errResp
+	}
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("decode successful resp %d: %w", resp.StatusCode, err)
+		}
+	}
+
+	return nil
+}
+
+// CDNClient is the CDN SDK with the cache calls the SDK lacks: the purge
+// and the prefetch of the files of a resource.
+type CDNClient struct {
+	gcdn.ClientService
+
+	requester gcore.Requester
+}
+
+// newCDNClient returns the SDK sending its requests through r.
+func newCDNClient(r gcore.Requester) *CDNClient {
+	return &CDNClient{ClientService: gcdn.NewService(r), requester: r}
+}
+
+// CDNCacheTask is a purge or a prefetch of the API.
+type CDNCacheTask struct {
+	Created  string   `json:"created"`
+	Type     string   `json:"purge_type"`
+	Status   string   `json:"status"`
+	Paths    []string `json:"paths"`
+	Resource struct {
+		ID    int64  `json:"id"`
+		CName string `json:"cname"`
+	} `json:"resource"`
+}
+
+const (
+	CDNPurgeByURL     = "purge_by_url"
+	CDNPurgeByPattern = "purge_by_pattern"
+	CDNPurgeAll       = "purge_all"
+	CDNPrefetch       = "prefetch"
+
+	CDNCacheTaskInProgress = "In progress"
+	CDNCacheTaskSuccessful = "Successful"
+	CDNCacheTaskFailed     = "Failed"
+)
+
+// Purge removes the files of the resource from the cache: the urls, the
+// files matching the patterns, or all the files when both are empty.
+func (c *CDNClient) Purge(ctx context.Context, resourceID int64, urls, patterns []string) error {
+	req := map[string][]string{"paths": {}}
+	switch {
+	case len(urls) > 0:
+		req = map[string][]string{"urls": urls}
+	case len(patterns) > 0:
+		req = map[string][]string{"paths": patterns}
+	}
+	return c.requester.Request(ctx, http.MethodPost, fmt.Sprintf("/cdn/resources/%d/purge", resourceID), req, nil)
+}
+
+// Prefetch loads the files of the resource to the cache.
+func (c *CDNClient) Prefetch(ctx context.Context, resourceID int64, paths []string) error {
+	req := map[string][]string{"paths": paths}
+	return c.requester.Request(ctx, http.MethodPost, fmt.Sprintf("/cdn/resources/%d/prefetch", resourceID), req, nil)
+}
+
+// CacheTasks returns the purges or the prefetches of the type of the
+// resource with the CNAME created since from.
+func (c *CDNClient) CacheTasks(ctx context.Context, cname, taskType string, from time.Time) ([]CDNCacheTask, error) {
+	query := url.Values{}
+	query.Set("cname", cname)
+	query.Set("purge_type", taskType)
+	query.Set("from_created", from.UTC().Format("2006-01-02T15:04:05"))
+	var tasks []CDNCacheTask
+	if err := c.requester.Request(ctx, http.MethodGet, "/cdn/purge_statuses?"+query.Encode(), nil, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ListRules returns the rules of the resource.
+func (c *CDNClient) ListRules(ctx context.Context, resourceID int64) ([]rules.Rule, error) {
+	var list []rules.Rule
+	if err := c.requester.Request(ctx, http.MethodGet, fmt.Sprintf("/cdn/resources/%d/rules", resourceID), nil, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// StorageClient is the Storage SDK sending the requests through the HTTP
+// client of the provider. The SDK takes its transport from
+// http.DefaultTransport, only the parameters of a call can replace it.
+type StorageClient struct {
+	*storageSDK.SDK
+
+	// httpClient is nil when the SDK's own transport is used.
+	httpClient *http.Client
+	// apiURL and authorization are the endpoint and the Authorization
+	// header of the calls the SDK lacks.
+	apiURL        string
+	authorization func() string
+}
+
+// KeysList getter for g-core storage api
+func (c *StorageClient) KeysList(opts ...func(*key.KeyListHTTPV2Params)) ([]models.Key, error) {
+	return c.SDK.KeysList(append(opts, func(opt *key.KeyListHTTPV2Params) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// CreateKey for g-core storage api
+func (c *StorageClient) CreateKey(opts ...func(*key.KeyCreateHTTPParams)) (*models.Key, error) {
+	return c.SDK.CreateKey(append(opts, func(opt *key.KeyCreateHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// DeleteKey for g-core storage api
+func (c *StorageClient) DeleteKey(opts ...func(*key.KeyDeleteHTTPParams)) error {
+	return c.SDK.DeleteKey(append(opts, func(opt *key.KeyDeleteHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// StoragesList getter for g-core storage api
+func (c *StorageClient) StoragesList(opts ...func(*storage.StorageListHTTPV2Params)) ([]models.Storage, error) {
+	return c.SDK.StoragesList(append(opts, func(opt *storage.StorageListHTTPV2Params) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// CreateStorage for g-core storage api
+func (c *StorageClient) CreateStorage(opts ...func(*storage.StorageCreateHTTPParams)) (*models.Storage, error) {
+	return c.SDK.CreateStorage(append(opts, func(opt *storage.StorageCreateHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// ModifyStorage for g-core storage api
+func (c *StorageClient) ModifyStorage(opts ...func(*storage.StorageUpdateHTTPParams)) (*models.Storage, error) {
+	return c.SDK.ModifyStorage(append(opts, func(opt *storage.StorageUpdateHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// DeleteStorage for g-core storage api
+func (c *StorageClient) DeleteStorage(opts ...func(*storage.StorageDeleteHTTPParams)) error {
+	return c.SDK.DeleteStorage(append(opts, func(opt *storage.StorageDeleteHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// LinkKeyToStorage for g-core storage api
+func (c *StorageClient) LinkKeyToStorage(opts ...func(*storage.KeyLinkHTTPParams)) error {
+	return c.SDK.LinkKeyToStorage(append(opts, func(opt *storage.KeyLinkHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// UnlinkKeyFromStorage for g-core storage api
+func (c *StorageClient) UnlinkKeyFromStorage(opts ...func(*storage.KeyUnlinkHTTPParams)) error {
+	return c.SDK.UnlinkKeyFromStorage(append(opts, func(opt *storage.KeyUnlinkHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// UpdateStorageCredentials for g-core storage api
+func (c *StorageClient) UpdateStorageCredentials(opts ...func(*storage.StorageUpdateCredentialsHTTPParams)) (*models.Credentials, error) {
+	return c.SDK.UpdateStorageCredentials(append(opts, func(opt *storage.StorageUpdateCredentialsHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// BucketsList getter for g-core storage api
+func (c *StorageClient) BucketsList(opts ...func(*storage.StorageListBucketsHTTPParams)) ([]models.BucketDto, error) {
+	return c.SDK.BucketsList(append(opts, func(opt *storage.StorageListBucketsHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// CreateBucket for g-core storage api
+func (c *StorageClient) CreateBucket(opts ...func(*storage.StorageBucketCreateHTTPParams)) error {
+	return c.SDK.CreateBucket(append(opts, func(opt *storage.StorageBucketCreateHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// DeleteBucket for g-core storage api
+func (c *StorageClient) DeleteBucket(opts ...func(*storage.StorageBucketRemoveHTTPParams)) error {
+	return c.SDK.DeleteBucket(append(opts, func(opt *storage.StorageBucketRemoveHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// BucketCORS getter for g-core storage api
+func (c *StorageClient) BucketCORS(opts ...func(*storage.GetStorageBucketCORSHTTPParams)) (string, error) {
+	return c.SDK.BucketCORS(append(opts, func(opt *storage.GetStorageBucketCORSHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// CreateBucketCORS for g-core storage api
+func (c *StorageClient) CreateBucketCORS(opts ...func(*storage.StorageBucketCORSCreateHTTPParams)) error {
+	return c.SDK.CreateBucketCORS(append(opts, func(opt *storage.StorageBucketCORSCreateHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// CreateBucketLifecycle for g-core storage api
+func (c *StorageClient) CreateBucketLifecycle(opts ...func(*storage.StorageBucketLifecycleCreateHTTPParams)) error {
+	return c.SDK.CreateBucketLifecycle(append(opts, func(opt *storage.StorageBucketLifecycleCreateHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// DeleteBucketLifecycle for g-core storage api
+func (c *StorageClient) DeleteBucketLifecycle(opts ...func(*storage.StorageBucketLifecycleDeleteHTTPParams)) error {
+	return c.SDK.DeleteBucketLifecycle(append(opts, func(opt *storage.StorageBucketLifecycleDeleteHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// CreateBucketPolicy for g-core storage api
+func (c *StorageClient) CreateBucketPolicy(opts ...func(*storage.StorageBucketPolicyCreateHTTPParams)) error {
+	return c.SDK.CreateBucketPolicy(append(opts, func(opt *storage.StorageBucketPolicyCreateHTTPParams) { c.setHTTPClient(&opt.HTTPClient) })...)
+}
+
+// DeleteBucketPolicy makes the bucket private again, the SDK can only make
+// it public.
+func (c *StorageClient) DeleteBucketPolicy(ctx context.Context, storageID int64, name string) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/provisioning/v1/storage/%d/s3/bucket/%s/policy", storageID, url.PathEscape(name)), nil)
+}
+
+// LocationsList returns the locations of the storages, the SDK doesn't
+// expose its location client.
+func (c *StorageClient) LocationsList(ctx context.Context) ([]models.ClientLocationRes, error) {
+	var res []models.ClientLocationRes
+	if err := c.do(ctx, http.MethodGet, "/provisioning/v1/location", &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// do sends a request of the calls the SDK lacks and decodes the response
+// into dest, when it isn't nil.
+func (c *StorageClient) do(ctx context.Context, method, uri string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.apiURL, "/")+uri, nil)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	if c.authorization != nil {
+		req.Header.Set("Authorization", c.authorization())
+	}
+
+	client := c.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		all, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("request: %s: %s", resp.Status, strings.TrimSpace(string(all)))
+	}
+	if dest == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func (c *StorageClient) setHTTPClient(client **http.Client) {
+	if c.httpClient != nil {
+		*client = c.httpClient
+	}
+}
+
+// StorageS3Client is a client of the S3 API of an S3 storage, which stores
+// the objects of its buckets. The requests are signed by AWS signature
+// version 4 with the keys of the storage.
+type StorageS3Client struct {
+	endpoint   *url.URL
+	signer     storageS3Signer
+	httpClient *http.Client
+}
+
+// StorageS3Object is the metadata of an object of a bucket.
+type StorageS3Object struct {
+	ETag         string
+	ContentType  string
+	CacheControl string
+	Size         int64
+}
+
+// StorageS3Error is an error response of the S3 API.
+type StorageS3Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e StorageS3Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("s3 api: %d %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("s3 api: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// isStorageS3NotFound returns whether the bucket or the object don't exist.
+func isStorageS3NotFound(err error) bool {
+	var e StorageS3Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+// newStorageS3Client returns a client of the S3 endpoint of a storage, e.g.
+// https://s-ed1.cloud.gcore.lu. The region is the location of the storage,
+// the first label of the endpoint host when it's empty. The requests are sent
+// through httpClient.
+func newStorageS3Client(httpClient *http.Client, endpoint, region, accessKey, secretKey string) (*StorageS3Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parse s3 endpoint: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint %q should be an url, e.g. https://s-ed1.cloud.gcore.lu", endpoint)
+	}
+	if region == "" {
+		region = strings.SplitN(u.Hostname(), ".", 2)[0]
+	}
+	return &StorageS3Client{
+		endpoint:   u,
+		signer:     storageS3Signer{region: region, accessKey: accessKey, secretKey: secretKey},
+		httpClient: httpClient,
+	}, nil
+}
+
+// PutObject uploads the object of size bytes with the hex SHA-256 of the
+// body and returns its ETag.
+func (c *StorageS3Client) PutObject(ctx context.Context, bucket, key string, body io.Reader, size int64, payloadHash string, contentType, cacheControl string) (string, error) {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	if cacheControl != "" {
+		header.Set("Cache-Control", cacheControl)
+	}
+	if size == 0 {
+		body = http.NoBody
+	}
+	resp, err := c.do(ctx, http.MethodPut, bucket, key, header, body, size, payloadHash)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	return strings.Trim(resp.Header.Get("ETag"), `"`), nil
+}
+
+// HeadObject returns the metadata of the object.
+func (c *StorageS3Client) HeadObject(ctx context.Context, bucket, key string) (*StorageS3Object, error) {
+	resp, err := c.do(ctx, http.MethodHead, bucket, key, nil, nil, 0, storageS3EmptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return &StorageS3Object{
+		ETag:         strings.Trim(resp.Header.Get("ETag"), `"`),
+		ContentType:  resp.Header.Get("Content-Type"),
+		CacheControl: resp.Header.Get("Cache-Control"),
+		Size:         resp.ContentLength,
+	}, nil
+}
+
+// DeleteObject deletes the object, a missing object isn't an error.
+func (c *StorageS3Client) DeleteObject(ctx context.Context, bucket, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, bucket, key, nil, nil, 0, storageS3EmptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// do sends a signed request to the object in the path style, the bucket
+// names of a storage aren't valid host names.
+func (c *StorageS3Client) do(ctx context.Context, method, bucket, key string, header http.Header, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	u := *c.endpoint
+	prefix := strings.TrimSuffix(u.Path, "/")
+	u.Path = prefix + "/" + bucket + "/" + key
+	u.RawPath = storageS3EscapePath(prefix + "/" + bucket + "/" + key)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil && body != http.NoBody {
+		req.ContentLength = size
+	}
+	c.signer.sign(req, payloadHash, time.Now())
+
+	log.Printf("[DEBUG] s3 api: %s %s", method, u.Path)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request: %w", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close()
+		e := StorageS3Error{StatusCode: resp.StatusCode}
+		all, _ := ioutil.ReadAll(resp.Body)
+		if err := xml.Unmarshal(all, &e); err != nil {
+			e.Message = strings.TrimSpace(string(all))
+		}
+		if e.Code == "" {
+			e.Code = http.StatusText(resp.StatusCode)
+		}
+		return nil, e
+	}
+	return resp, nil
+}
+
+// storageS3EmptyPayloadHash is the hex SHA-256 of an empty body.
+const storageS3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// storageS3Signer signs requests by AWS signature version 4.
+type storageS3Signer struct {
+	region    string
+	accessKey string
+	secretKey string
+}
+
+// sign sets the Authorization header of the request signed with the host
+// and all the headers of the request.
+func (s storageS3Signer) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Del("Authorization")
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+	signedHeaders, signature := s.signature(req.Method, req.URL.EscapedPath(), req.URL.Query(), headers, amzDate, payloadHash)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, s.scope(amzDate), signedHeaders, signature))
+}
+
+// signature returns the signed headers and the signature of a request, the
+// header names are lower case.
+func (s storageS3Signer) signature(method, escapedPath string, query url.Values, headers map[string]string, amzDate, payloadHash string) (string, string) {
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + strings.Join(strings.Fields(headers[k]), " ") + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// url.Values.Encode sorts by key and escapes spaces by +, S3 wants %20.
+	canonicalQuery := strings.ReplaceAll(query.Encode(), "+", "%20")
+	canonicalRequest := strings.Join([]string{method, escapedPath, canonicalQuery, canonicalHeaders.String(), signedHeaders, payloadHash}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, s.scope(amzDate), hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := []byte("AWS4" + s.secretKey)
+	for _, part := range []string{amzDate[:8], s.region, "s3", "aws4_request"} {
+		key = storageS3HMAC(key, part)
+	}
+	return signedHeaders, hex.EncodeToString(storageS3HMAC(key, stringToSign))
+}
+
+func (s storageS3Signer) scope(amzDate string) string {
+	return amzDate[:8] + "/" + s.region + "/s3/aws4_request"
+}
+
+func storageS3HMAC(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// storageS3EscapePath escapes all the bytes of the path but the unreserved
+// characters and slashes, as the signature wants.
+func storageS3EscapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~/", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package gcore
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCDNRules() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCDNRulesRead,
+		Description: "Represent the rules of an existing CDN resource, e.g. to import the rules created in the portal.",
+		Schema: map[string]*schema.Schema{
+			"resource_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "ID of the CDN resource.",
+			},
+			"rules": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Rules of the resource in the order of execution, by weight. The fields fit gcore_cdn_rule.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "ID of the rule, gcore_cdn_rule is imported by resource_id:id.",
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rule": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rule_type": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"origin_group": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"origin_protocol": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"weight": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"options": computedSchema(optionsSchema),
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceCDNRulesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start CDN Rules reading")
+	config := m.(*Config)
+	client := config.CDNClient
+
+	resourceID := d.Get("resource_id").(int)
+	result, err := client.ListRules(ctx, int64(resourceID))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Weight != result[j].Weight {
+			return result[i].Weight < result[j].Weight
+		}
+		return result[i].ID < result[j].ID
+	})
+
+	list := make([]interface{}, 0, len(result))
+	for _, rule := range result {
+		originGroup := 0
+		if rule.OriginGroup != nil {
+			originGroup = *rule.OriginGroup
+		}
+		list = append(list, map[string]interface{}{
+			"id":              rule.ID,
+			"name":            rule.Name,
+			"rule":            rule.Pattern,
+			"rule_type":       rule.Type,
+			"origin_group":    originGroup,
+			"origin_protocol": rule.OriginProtocol,
+			"weight":          rule.Weight,
+			"options":         optionsToList(rule.Options),
+		})
+	}
+	d.SetId(strconv.Itoa(resourceID))
+	if err := d.Set("rules", list); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Println("[DEBUG] Finish CDN Rules reading")
+	return nil
+}
+
+// computedSchema returns a read-only copy of the schema for data sources.
+func computedSchema(s *schema.Schema) *schema.Schema {
+	res := &schema.Schema{
+		Type:        s.Type,
+		Computed:    true,
+		Sensitive:   s.Sensitive,
+		Description: s.Description,
+		Elem:        s.Elem,
+	}
+	if r, ok := s.Elem.(*schema.Resource); ok {
+		elem := make(map[string]*schema.Schema, len(r.Schema))
+		for k, v := range r.Schema {
+			elem[k] = computedSchema(v)
+		}
+		res.Elem = &schema.Resource{Schema: elem}
+	}
+	return res
+}
@@ -0,0 +1,61 @@
+//go:build !cloud
+// +build !cloud
+
+package gcore
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitCDNRulesDataSource(t *testing.T) {
+	api := newMockAPI(t)
+	groupID := api.cdn.SeedOriginGroup("origin-group", "example.com")
+	resourceID := api.cdn.SeedResource("cdn.example.com", groupID)
+	emptyResourceID := api.cdn.SeedResource("cdn.example.org", groupID)
+	for _, rule := range []map[string]interface{}{
+		{"name": "images", "rule": "/images/*", "ruleType": 0, "weight": 2, "originGroup": groupID},
+		{"name": "static", "rule": "/static/*", "ruleType": 0, "weight": 1,
+			"options": map[string]interface{}{"gzipOn": map[string]interface{}{"enabled": true, "value": true}}},
+	} {
+		if err := api.cdn.SeedRule(resourceID, rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fullName := "data.gcore_cdn_rules.test"
+	template := func(resourceID int) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+data "gcore_cdn_rules" "test" {
+  resource_id = %d
+}
+`, resourceID)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: template(resourceID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "id", strconv.Itoa(resourceID)),
+					resource.TestCheckResourceAttr(fullName, "rules.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "rules.0.name", "static"),
+					resource.TestCheckResourceAttr(fullName, "rules.0.weight", "1"),
+					resource.TestCheckResourceAttr(fullName, "rules.0.origin_group", "0"),
+					resource.TestCheckResourceAttr(fullName, "rules.0.origin_protocol", "HTTP"),
+					resource.TestCheckResourceAttr(fullName, "rules.0.options.0.gzip_on.0.value", "true"),
+					resource.TestCheckResourceAttr(fullName, "rules.1.name", "images"),
+					resource.TestCheckResourceAttr(fullName, "rules.1.rule", "/images/*"),
+					resource.TestCheckResourceAttr(fullName, "rules.1.origin_group", strconv.Itoa(groupID)),
+				),
+			},
+			{
+				Config: template(emptyResourceID),
+				Check:  resource.TestCheckResourceAttr(fullName, "rules.#", "0"),
+			},
+		},
+	})
+}
@@ -20,40 +20,30 @@ func dataSourceDDoSProfileTemplate() *schema.Resource {
 		Description: "Represents list of available DDoS protection profile templates",
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"template_id": &schema.Schema{
 				Type:        schema.TypeInt,
@@ -129,9 +119,8 @@ func dataSourceDDoSProfileTemplateRead(ctx context.Context, d *schema.ResourceDa
 	log.Println("[DEBUG] Starts DDoS protection profile template reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, ddosTemplatesPoint, versionPointV1)
+	client, err := CreateClient(config, d, ddosTemplatesPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
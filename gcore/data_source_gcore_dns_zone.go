@@ -0,0 +1,53 @@
+package gcore
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	DNSZoneDataSource = "gcore_dns_zone"
+)
+
+func dataSourceDNSZone() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: checkDNSDependency(dataSourceDNSZoneRead),
+		Description: "Represent an existing DNS zone, e.g. one managed by another configuration.",
+		Schema: map[string]*schema.Schema{
+			DNSZoneSchemaName: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "A name of the zone.",
+			},
+			DNSZoneSchemaZoneFile: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Records of the zone in BIND zone file format.",
+			},
+		},
+	}
+}
+
+func dataSourceDNSZoneRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := strings.TrimSpace(d.Get(DNSZoneSchemaName).(string))
+	log.Printf("[DEBUG] Start DNS Zone reading (name=%s)\n", name)
+	defer log.Println("[DEBUG] Finish DNS Zone reading")
+
+	config := m.(*Config)
+	client := config.DNSClient
+
+	result, err := client.Zone(ctx, name)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("get zone: %w", err))
+	}
+	d.SetId(result.Name)
+	_ = d.Set(DNSZoneSchemaName, result.Name)
+	_ = d.Set(DNSZoneSchemaZoneFile, renderDNSZoneFile(result.Name, dnsZoneFileRRSetsFromZone(result)))
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package gcore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	DNSZoneFileDataSource = "gcore_dns_zone_file"
+
+	DNSZoneFileSchemaContent = "content"
+	DNSZoneFileSchemaOrigin  = "origin"
+	DNSZoneFileSchemaZone    = "zone"
+	DNSZoneFileSchemaRecords = "records"
+)
+
+func dataSourceDNSZoneFile() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDNSZoneFileRead,
+		Description: "Parse a BIND zone file (RFC 1035) into rrsets fitting gcore_dns_zone_record resources. The SOA record is skipped. It doesn't call the DNS API.",
+		Schema: map[string]*schema.Schema{
+			DNSZoneFileSchemaContent: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Content of the zone file, e.g. file(\"example.com.zone\").",
+			},
+			DNSZoneFileSchemaOrigin: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Zone name relative names are completed with until the file sets $ORIGIN.",
+			},
+			DNSZoneFileSchemaZone: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Zone name, the last origin of the file.",
+			},
+			DNSZoneFileSchemaRecords: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Rrsets of the zone file in the order they first appear.",
+				Elem:        dnsRRSetDataSourceElem("A ttl of the rrset, 0 when the file sets none."),
+			},
+		},
+	}
+}
+
+func dataSourceDNSZoneFileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start DNS Zone File reading")
+	defer log.Println("[DEBUG] Finish DNS Zone File reading")
+
+	content := d.Get(DNSZoneFileSchemaContent).(string)
+	rrsets, origin, err := parseDNSZoneFile(content, d.Get(DNSZoneFileSchemaOrigin).(string))
+	if err != nil {
+		return diag.Errorf("parse zone file: %s", err)
+	}
+
+	records := make([]map[string]interface{}, 0, len(rrsets))
+	for _, rrset := range rrsets {
+		records = append(records, map[string]interface{}{
+			DNSZoneRecordSchemaDomain:         rrset.Domain,
+			DNSZoneRecordSchemaType:           rrset.Type,
+			DNSZoneRecordSchemaTTL:            rrset.RRSet.TTL,
+			DNSZoneRecordSchemaResourceRecord: flattenDNSResourceRecords(rrset.RRSet.Records),
+		})
+	}
+	if err := d.Set(DNSZoneFileSchemaRecords, records); err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set(DNSZoneFileSchemaZone, origin)
+
+	sum := sha256.Sum256([]byte(content))
+	d.SetId(hex.EncodeToString(sum[:]))
+
+	return nil
+}
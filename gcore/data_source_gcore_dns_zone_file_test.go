@@ -0,0 +1,168 @@
+//go:build !cloud
+// +build !cloud
+
+package gcore
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	dnssdk "github.com/G-Core/gcore-dns-sdk-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const unitZoneFile = `$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1.example.com. admin.example.com. (
+		2021010101 ; serial
+		7200       ; refresh
+		3600       ; retry
+		1209600    ; expire
+		300 )      ; minimum
+@		IN	NS	ns1.gcorelabs.net.
+		IN	NS	ns2.gcorelabs.net.
+@	300	IN	A	192.0.2.1
+		IN	A	192.0.2.2
+www	IN	600	CNAME	@
+mail		AAAA	2001:db8::1
+@		MX	10 mail
+@		MX	20 mx.example.net.
+_sip._tcp	1d	SRV	10 60 5060 sip
+@		TXT	"v=spf1 include:_spf.example.com ~all"
+txt		TXT	"first; not a comment " "second \"quoted\""
+@		CAA	0 issue "letsencrypt.org"
+ptr		PTR	www
+@		HTTPS	1 . alpn="h2,h3" port=443
+`
+
+func TestUnitParseDNSZoneFile(t *testing.T) {
+	rrsets, origin, err := parseDNSZoneFile(unitZoneFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if origin != "example.com" {
+		t.Fatalf("expected origin example.com, got %s", origin)
+	}
+
+	var got []string
+	for _, rrset := range rrsets {
+		var contents []string
+		for _, rr := range rrset.RRSet.Records {
+			contents = append(contents, rr.ContentToString())
+		}
+		got = append(got, fmt.Sprintf("%s %d %s %s", rrset.Domain, rrset.RRSet.TTL, rrset.Type, strings.Join(contents, " | ")))
+	}
+	expected := []string{
+		"example.com 3600 NS ns1.gcorelabs.net. | ns2.gcorelabs.net.",
+		"example.com 300 A 192.0.2.1 | 192.0.2.2",
+		"www.example.com 600 CNAME example.com.",
+		"mail.example.com 3600 AAAA 2001:db8::1",
+		"example.com 3600 MX 10 mail.example.com. | 20 mx.example.net.",
+		"_sip._tcp.example.com 86400 SRV 10 60 5060 sip.example.com.",
+		"example.com 3600 TXT v=spf1 include:_spf.example.com ~all",
+		`txt.example.com 3600 TXT first; not a comment second "quoted"`,
+		`example.com 3600 CAA 0 issue "letsencrypt.org"`,
+		"ptr.example.com 3600 PTR www.example.com.",
+		"example.com 3600 HTTPS 1 . alpn=h2,h3 port=443",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected rrsets:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestUnitParseDNSZoneFileErrors(t *testing.T) {
+	for content, expected := range map[string]string{
+		"www 300 IN A 192.0.2.1\n":                         `line 1: relative name "www" without \$ORIGIN`,
+		"$ORIGIN example.com.\nwww A 2001:db8::1\n":        "line 2: www.example.com A: expected an IPv4 address",
+		"$ORIGIN example.com.\n@ HINFO \"PC\" \"Linux\"\n": "line 2: record type HINFO is not supported",
+		"$ORIGIN example.com.\n@ CH TXT \"x\"\n":           "line 2: class CH is not supported",
+		"$ORIGIN example.com.\n@ TXT \"x\n":                "line 2: unterminated string",
+		"$ORIGIN example.com.\n@ MX ( 10 mail\n":           "unbalanced parenthesis",
+		"$INCLUDE other.zone\n":                            "line 1: directive \\$INCLUDE is not supported",
+		"$ORIGIN example.com.\n@ 1x A 192.0.2.1\n":         `line 2: invalid ttl "1x"`,
+	} {
+		_, _, err := parseDNSZoneFile(content, "")
+		if err == nil || !regexp.MustCompile(expected).MatchString(err.Error()) {
+			t.Errorf("%q: expected error matching %s, got %v", content, expected, err)
+		}
+	}
+}
+
+func TestUnitDNSZoneFileRoundTrip(t *testing.T) {
+	rrsets, _, err := parseDNSZoneFile(unitZoneFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	long := strings.Repeat("k", 300)
+	rrsets = append(rrsets, dnsZoneFileRRSet{
+		Domain: "dkim.example.com",
+		Type:   "TXT",
+		RRSet:  dnssdk.RRSet{TTL: 300, Records: []dnssdk.ResourceRecord{*(&dnssdk.ResourceRecord{Enabled: true}).SetContent("TXT", long)}},
+	})
+
+	rendered := renderDNSZoneFile("example.com", rrsets)
+	if !strings.Contains(rendered, "www\t600\tIN\tCNAME\texample.com.\n") {
+		t.Fatalf("expected relative owners, got:\n%s", rendered)
+	}
+	parsed, _, err := parseDNSZoneFile(rendered, "")
+	if err != nil {
+		t.Fatalf("parse rendered zone file: %s\n%s", err, rendered)
+	}
+
+	index := func(rrsets []dnsZoneFileRRSet) map[string]dnssdk.RRSet {
+		res := make(map[string]dnssdk.RRSet)
+		for _, rrset := range rrsets {
+			res[rrset.Domain+"/"+rrset.Type] = rrset.RRSet
+		}
+		return res
+	}
+	if got, expected := index(parsed), index(rrsets); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected the rrsets back, got:\n%v\nexpected:\n%v", got, expected)
+	}
+}
+
+func TestUnitDNSZoneFileDataSource(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "data." + DNSZoneFileDataSource + ".test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + `
+data "gcore_dns_zone_file" "test" {
+  content = <<-EOT
+    www 300 IN A 192.0.2.1
+        IN A 192.0.2.2
+    mail MX 10 mx
+  EOT
+  origin  = "example.com"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "zone", "example.com"),
+					resource.TestCheckResourceAttr(fullName, "records.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "records.0.domain", "www.example.com"),
+					resource.TestCheckResourceAttr(fullName, "records.0.type", "A"),
+					resource.TestCheckResourceAttr(fullName, "records.0.ttl", "300"),
+					resource.TestCheckResourceAttr(fullName, "records.0.resource_record.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "records.0.resource_record.1.content", "192.0.2.2"),
+					resource.TestCheckResourceAttr(fullName, "records.0.resource_record.1.enabled", "true"),
+					resource.TestCheckResourceAttr(fullName, "records.1.domain", "mail.example.com"),
+					resource.TestCheckResourceAttr(fullName, "records.1.ttl", "300"),
+					resource.TestCheckResourceAttr(fullName, "records.1.resource_record.0.content", "10 mx.example.com."),
+					func(*terraform.State) error {
+						if reqs := api.Requests("GET /dns/"); len(reqs) != 0 {
+							return fmt.Errorf("expected no DNS API requests, got %v", reqs)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
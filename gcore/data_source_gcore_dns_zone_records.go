@@ -0,0 +1,148 @@
+package gcore
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	DNSZoneRecordsDataSource = "gcore_dns_zone_records"
+
+	DNSZoneRecordsSchemaZone    = "zone"
+	DNSZoneRecordsSchemaDomain  = "domain"
+	DNSZoneRecordsSchemaType    = "type"
+	DNSZoneRecordsSchemaRecords = "records"
+)
+
+func dataSourceDNSZoneRecords() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: checkDNSDependency(dataSourceDNSZoneRecordsRead),
+		Description: "Represent the rrsets of an existing DNS zone, e.g. one managed by another configuration.",
+		Schema: map[string]*schema.Schema{
+			DNSZoneRecordsSchemaZone: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "A name of the zone.",
+			},
+			DNSZoneRecordsSchemaDomain: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool { return suppressDNSNameDiff(old, new) },
+				Description:      "Read only the rrsets of the domain.",
+			},
+			DNSZoneRecordsSchemaType: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Read only the rrsets of the type.",
+			},
+			DNSZoneRecordsSchemaRecords: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Rrsets of the zone sorted by domain and type.",
+				Elem:        dnsRRSetDataSourceElem("A ttl of the rrset."),
+			},
+		},
+	}
+}
+
+// dnsRRSetDataSourceElem is a rrset of the data sources, its fields fit
+// gcore_dns_zone_record.
+func dnsRRSetDataSourceElem(ttlDescription string) *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			DNSZoneRecordSchemaDomain: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A domain of the rrset.",
+			},
+			DNSZoneRecordSchemaType: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A type of the rrset.",
+			},
+			DNSZoneRecordSchemaTTL: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: ttlDescription,
+			},
+			DNSZoneRecordSchemaResourceRecord: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						DNSZoneRecordSchemaContent: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "A content of the record in the format of gcore_dns_zone_record.",
+						},
+						DNSZoneRecordSchemaEnabled: {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDNSZoneRecordsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	zoneName := strings.TrimSpace(d.Get(DNSZoneRecordsSchemaZone).(string))
+	domain := strings.TrimSpace(d.Get(DNSZoneRecordsSchemaDomain).(string))
+	rType := strings.TrimSpace(d.Get(DNSZoneRecordsSchemaType).(string))
+	log.Printf("[DEBUG] Start DNS Zone Records reading (zone=%s)\n", zoneName)
+	defer log.Println("[DEBUG] Finish DNS Zone Records reading")
+
+	config := m.(*Config)
+	client := config.DNSClient
+
+	zone, err := client.Zone(ctx, zoneName)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("get zone: %w", err))
+	}
+
+	records := make([]map[string]interface{}, 0, len(zone.Records))
+	for _, rrset := range dnsZoneFileRRSetsFromZone(zone) {
+		if domain != "" && !suppressDNSNameDiff(rrset.Domain, domain) {
+			continue
+		}
+		if rType != "" && !strings.EqualFold(rrset.Type, rType) {
+			continue
+		}
+		// The zone holds only the short answers of the enabled records.
+		result, err := client.RRSet(ctx, zoneName, rrset.Domain, rrset.Type)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("get zone rrset: %w", err))
+		}
+		rr := flattenDNSResourceRecords(result.Records)
+		keepDNSResourceRecordsContent(rrset.Type, rr, nil)
+		for _, r := range rr {
+			delete(r, DNSZoneRecordSchemaMeta)
+		}
+		records = append(records, map[string]interface{}{
+			DNSZoneRecordSchemaDomain:         rrset.Domain,
+			DNSZoneRecordSchemaType:           rrset.Type,
+			DNSZoneRecordSchemaTTL:            result.TTL,
+			DNSZoneRecordSchemaResourceRecord: rr,
+		})
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a[DNSZoneRecordSchemaDomain] != b[DNSZoneRecordSchemaDomain] {
+			return a[DNSZoneRecordSchemaDomain].(string) < b[DNSZoneRecordSchemaDomain].(string)
+		}
+		return a[DNSZoneRecordSchemaType].(string) < b[DNSZoneRecordSchemaType].(string)
+	})
+	if err := d.Set(DNSZoneRecordsSchemaRecords, records); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(zone.Name)
+
+	return nil
+}
@@ -0,0 +1,64 @@
+//go:build !cloud
+// +build !cloud
+
+package gcore
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitDNSZoneDataSources(t *testing.T) {
+	api := newMockAPI(t)
+	zone := api.dns.SeedZone("example.com")
+	api.dns.SeedRRSet(zone, "www."+zone, "A", 120, []interface{}{"192.0.2.1"}, []interface{}{"192.0.2.2"})
+	api.dns.SeedRRSet(zone, zone, "MX", 300, []interface{}{10, "mail.example.com"})
+	zoneName := "data." + DNSZoneDataSource + ".test"
+	recordsName := "data." + DNSZoneRecordsDataSource + ".test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + `
+data "gcore_dns_zone" "test" {
+  name = "example.com"
+}
+
+data "gcore_dns_zone_records" "test" {
+  zone = "example.com"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(zoneName, "id", zone),
+					resource.TestCheckResourceAttr(zoneName, "zone_file", "$ORIGIN example.com.\n@\t300\tIN\tMX\t10 mail.example.com.\nwww\t120\tIN\tA\t192.0.2.1\nwww\t120\tIN\tA\t192.0.2.2\n"),
+					resource.TestCheckResourceAttr(recordsName, "records.#", "2"),
+					resource.TestCheckResourceAttr(recordsName, "records.0.domain", zone),
+					resource.TestCheckResourceAttr(recordsName, "records.0.type", "MX"),
+					resource.TestCheckResourceAttr(recordsName, "records.0.ttl", "300"),
+					resource.TestCheckResourceAttr(recordsName, "records.0.resource_record.0.content", "10 mail.example.com."),
+					resource.TestCheckResourceAttr(recordsName, "records.1.domain", "www."+zone),
+					resource.TestCheckResourceAttr(recordsName, "records.1.type", "A"),
+					resource.TestCheckResourceAttr(recordsName, "records.1.resource_record.#", "2"),
+					resource.TestCheckResourceAttr(recordsName, "records.1.resource_record.1.content", "192.0.2.2"),
+					resource.TestCheckResourceAttr(recordsName, "records.1.resource_record.1.enabled", "true"),
+				),
+			},
+			{
+				Config: api.providerHCL("") + `
+data "gcore_dns_zone_records" "test" {
+  zone   = "example.com"
+  domain = "WWW.example.com."
+  type   = "a"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(recordsName, "records.#", "1"),
+					resource.TestCheckResourceAttr(recordsName, "records.0.type", "A"),
+					resource.TestCheckResourceAttr(recordsName, "records.0.ttl", "120"),
+				),
+			},
+		},
+	})
+}
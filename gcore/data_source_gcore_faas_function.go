@@ -15,40 +15,30 @@ func dataSourceFaaSFunction() *schema.Resource {
 		Description: "Represent FaaS function",
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -136,12 +126,11 @@ func dataSourceFaaSFunctionRead(ctx context.Context, d *schema.ResourceData, m i
 	log.Println("[DEBUG] Start FaaS function reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	fName := d.Get("name").(string)
 	nsName := d.Get("namespace").(string)
 	log.Printf("[DEBUG] function = %s in %s", fName, nsName)
 
-	client, err := CreateClient(provider, d, faasPoint, versionPointV1)
+	client, err := CreateClient(config, d, faasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
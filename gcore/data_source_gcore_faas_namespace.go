@@ -16,40 +16,30 @@ func dataSourceFaaSNamespace() *schema.Resource {
 		Description: "Represent FaaS namespace",
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -82,11 +72,10 @@ func dataSourceFaaSNamespaceRead(ctx context.Context, d *schema.ResourceData, m
 	log.Println("[DEBUG] Start FaaS namespace reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	nsName := d.Get("name").(string)
 	log.Printf("[DEBUG] namespace = %s", nsName)
 
-	client, err := CreateClient(provider, d, faasPoint, versionPointV1)
+	client, err := CreateClient(config, d, faasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
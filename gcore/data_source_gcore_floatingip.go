@@ -20,36 +20,26 @@ func dataSourceFloatingIP() *schema.Resource {
 		Description: "A floating IP is a static IP address that points to one of your Instances. It allows you to redirect network traffic to any of your Instances in the same datacenter.",
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"floating_ip_address": &schema.Schema{
 				Type:     schema.TypeString,
@@ -119,9 +109,8 @@ func dataSourceFloatingIPRead(ctx context.Context, d *schema.ResourceData, m int
 	log.Println("[DEBUG] Start FloatingIP reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, floatingIPsPoint, versionPointV1)
+	client, err := CreateClient(config, d, floatingIPsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -23,36 +23,26 @@ func dataSourceImage() *schema.Resource {
 		Description: "Represent image data",
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:        schema.TypeString,
@@ -124,13 +114,12 @@ func dataSourceImageRead(ctx context.Context, d *schema.ResourceData, m interfac
 	name := d.Get("name").(string)
 
 	config := m.(*Config)
-	provider := config.Provider
 
 	point := imagesPoint
 	if isBm, _ := d.Get("is_baremetal").(bool); isBm {
 		point = bmImagesPoint
 	}
-	client, err := CreateClient(provider, d, point, versionPointV1)
+	client, err := CreateClient(config, d, point, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -17,36 +17,26 @@ func dataSourceK8s() *schema.Resource {
 		Description: "Represent k8s cluster with one default pool.",
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"cluster_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -210,9 +200,8 @@ func dataSourceK8sRead(ctx context.Context, d *schema.ResourceData, m interface{
 	log.Println("[DEBUG] Start K8s reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, K8sPoint, versionPointV1)
+	client, err := CreateClient(config, d, K8sPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
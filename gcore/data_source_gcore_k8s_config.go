@@ -0,0 +1,210 @@
+package gcore
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/k8s/v1/clusters"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+func dataSourceK8sConfig() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceK8sConfigRead,
+		Description: "Represent kubeconfig of k8s cluster, its attributes fit the kubernetes and helm providers.",
+		Schema: map[string]*schema.Schema{
+			"project_id": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
+			},
+			"region_id": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
+			},
+			"project_name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
+			},
+			"region_name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
+			},
+			"cluster_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"kubeconfig": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Raw kubeconfig of the cluster in YAML.",
+			},
+			"host": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL of the cluster API server.",
+			},
+			"cluster_ca_certificate": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "PEM encoded CA certificate of the cluster.",
+			},
+			"client_certificate": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "PEM encoded client certificate.",
+			},
+			"client_key": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "PEM encoded client key.",
+			},
+		},
+	}
+}
+
+func dataSourceK8sConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start K8s config reading")
+	var diags diag.Diagnostics
+	config := m.(*Config)
+
+	projectID, regionID, err := cloudScope(config, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	client, err := config.serviceClient(gcorecloud.EndpointOpts{
+		Name:    K8sPoint,
+		Region:  regionID,
+		Project: projectID,
+		Version: versionPointV1,
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	clusterID := d.Get("cluster_id").(string)
+	kubeconfig, err := clusters.GetConfig(client, clusterID).Extract()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	parsed, err := parseKubeconfig(kubeconfig.Config)
+	if err != nil {
+		return diag.Errorf("cannot parse kubeconfig of k8s cluster %s: %s", clusterID, err)
+	}
+
+	d.SetId(clusterID)
+	d.Set("project_id", projectID)
+	d.Set("region_id", regionID)
+	d.Set("kubeconfig", kubeconfig.Config)
+	d.Set("host", parsed.host)
+	d.Set("cluster_ca_certificate", parsed.clusterCACertificate)
+	d.Set("client_certificate", parsed.clientCertificate)
+	d.Set("client_key", parsed.clientKey)
+
+	log.Println("[DEBUG] Finish K8s config reading")
+	return diags
+}
+
+// kubeconfig is the part of a kubeconfig file the data source exposes.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKeyData         string `yaml:"client-key-data"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+type kubeconfigCredentials struct {
+	host                 string
+	clusterCACertificate string
+	clientCertificate    string
+	clientKey            string
+}
+
+// parseKubeconfig takes the cluster and the user of the current context, or
+// of the first context when none is current, and decodes their certificates.
+func parseKubeconfig(raw string) (*kubeconfigCredentials, error) {
+	var kc kubeconfig
+	if err := yaml.Unmarshal([]byte(raw), &kc); err != nil {
+		return nil, err
+	}
+	if len(kc.Contexts) == 0 {
+		return nil, fmt.Errorf("no contexts found")
+	}
+
+	ctx := kc.Contexts[0]
+	for _, c := range kc.Contexts {
+		if c.Name == kc.CurrentContext {
+			ctx = c
+			break
+		}
+	}
+
+	var res kubeconfigCredentials
+	var found bool
+	for _, c := range kc.Clusters {
+		if c.Name != ctx.Context.Cluster {
+			continue
+		}
+		ca, err := base64.StdEncoding.DecodeString(c.Cluster.CertificateAuthorityData)
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: certificate-authority-data: %w", c.Name, err)
+		}
+		res.host, res.clusterCACertificate, found = c.Cluster.Server, string(ca), true
+		break
+	}
+	if !found {
+		return nil, fmt.Errorf("cluster %s of context %s not found", ctx.Context.Cluster, ctx.Name)
+	}
+
+	found = false
+	for _, u := range kc.Users {
+		if u.Name != ctx.Context.User {
+			continue
+		}
+		cert, err := base64.StdEncoding.DecodeString(u.User.ClientCertificateData)
+		if err != nil {
+			return nil, fmt.Errorf("user %s: client-certificate-data: %w", u.Name, err)
+		}
+		key, err := base64.StdEncoding.DecodeString(u.User.ClientKeyData)
+		if err != nil {
+			return nil, fmt.Errorf("user %s: client-key-data: %w", u.Name, err)
+		}
+		res.clientCertificate, res.clientKey, found = string(cert), string(key), true
+		break
+	}
+	if !found {
+		return nil, fmt.Errorf("user %s of context %s not found", ctx.Context.User, ctx.Name)
+	}
+	return &res, nil
+}
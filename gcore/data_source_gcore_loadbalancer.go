@@ -19,36 +19,26 @@ func dataSourceLoadBalancer() *schema.Resource {
 		DeprecationMessage: "!> **WARNING:** This data-source is deprecated and will be removed in the next major version. Use gcore_loadbalancerv2 data-source instead",
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -126,9 +116,8 @@ func dataSourceLoadBalancerRead(ctx context.Context, d *schema.ResourceData, m i
 	log.Println("[DEBUG] Start LoadBalancer reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LoadBalancersPoint, versionPointV1)
+	client, err := CreateClient(config, d, LoadBalancersPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -190,7 +179,7 @@ func dataSourceLoadBalancerRead(ctx context.Context, d *schema.ResourceData, m i
 		return diag.FromErr(err)
 	}
 
-	listenersClient, err := CreateClient(provider, d, LBListenersPoint, versionPointV1)
+	listenersClient, err := CreateClient(config, d, LBListenersPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -16,36 +16,26 @@ func dataSourceNetwork() *schema.Resource {
 		Description: "Represent network. A network is a software-defined network in a cloud computing infrastructure",
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -107,13 +97,12 @@ func dataSourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interf
 	log.Println("[DEBUG] Start Network reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, networksPoint, versionPointV1)
+	client, err := CreateClient(config, d, networksPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	clientShared, err := CreateClient(provider, d, sharedNetworksPoint, versionPointV1)
+	clientShared, err := CreateClient(config, d, sharedNetworksPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
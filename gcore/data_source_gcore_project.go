@@ -27,8 +27,7 @@ func dataSourceProjectRead(ctx context.Context, d *schema.ResourceData, m interf
 	log.Println("[DEBUG] Start Project reading")
 	name := d.Get("name").(string)
 	config := m.(*Config)
-	provider := config.Provider
-	projectID, err := GetProject(provider, 0, name)
+	projectID, err := config.projectID(0, name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
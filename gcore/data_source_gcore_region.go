@@ -28,8 +28,7 @@ func dataSourceRegionRead(ctx context.Context, d *schema.ResourceData, m interfa
 
 	name := d.Get("name").(string)
 	config := m.(*Config)
-	provider := config.Provider
-	regionID, err := GetRegion(provider, 0, name)
+	regionID, err := config.regionID(0, name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
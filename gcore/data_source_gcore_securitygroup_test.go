@@ -5,9 +5,10 @@ package gcore
 
 import (
 	"fmt"
+	"testing"
+
 	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/securitygroups"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"testing"
 )
 
 const (
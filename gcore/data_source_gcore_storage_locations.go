@@ -0,0 +1,118 @@
+package gcore
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/G-Core/gcore-storage-sdk-go/swagger/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	StorageLocationsSchemaType      = "type"
+	StorageLocationsSchemaLocations = "locations"
+)
+
+func dataSourceStorageLocations() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceStorageLocationsRead,
+		Description: "Represent the locations of the storages, e.g. to find the locations available for new storages.",
+		Schema: map[string]*schema.Schema{
+			StorageLocationsSchemaType: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"s3", "sftp"}, false),
+				Description:  "A type of the storages of the locations, s3 or sftp. All the locations are listed when unset.",
+			},
+			StorageLocationsSchemaLocations: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Locations sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "A name of the location, the location of gcore_storage_s3 and gcore_storage_sftp.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "A type of the storages of the location, s3 or sftp.",
+						},
+						"address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "A host name of the location.",
+						},
+						"allow_for_new_storage": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether new storages can be created in the location.",
+						},
+						"s3_endpoint": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "An s3 endpoint of the location, empty for sftp locations.",
+						},
+						"http_endpoint": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "A http s3 entry point of the buckets of the location, empty for sftp locations.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceStorageLocationsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start Storage Locations reading")
+	config := m.(*Config)
+	client := config.StorageClient
+	if client == nil {
+		return diag.Errorf("storage api client is null. make sure that you defined gcore_storage_api var in gcore provider section.")
+	}
+
+	result, err := client.LocationsList(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	storageType := d.Get(StorageLocationsSchemaType).(string)
+	list := make([]interface{}, 0, len(result))
+	for _, l := range result {
+		if storageType != "" && l.Type != storageType {
+			continue
+		}
+		s3Endpoint, httpEndpoint := "", ""
+		if l.Type == "s3" {
+			s3Endpoint = "https://" + l.Address
+			httpEndpoint = s3Endpoint + "/{bucket_name}"
+		}
+		list = append(list, map[string]interface{}{
+			"name":                  l.Name,
+			"type":                  l.Type,
+			"address":               l.Address,
+			"allow_for_new_storage": l.AllowForNewStorage != models.ClientLocationResAllowForNewStorageDeny,
+			"s3_endpoint":           s3Endpoint,
+			"http_endpoint":         httpEndpoint,
+		})
+	}
+	if storageType == "" {
+		d.SetId("all")
+	} else {
+		d.SetId(storageType)
+	}
+	if err := d.Set(StorageLocationsSchemaLocations, list); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Println("[DEBUG] Finish Storage Locations reading")
+	return nil
+}
@@ -0,0 +1,71 @@
+//go:build !cloud
+// +build !cloud
+
+package gcore
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitStorageLocationsDataSource(t *testing.T) {
+	api := newMockAPI(t)
+	api.storage.SeedLocation("s-old1", "s3", "deny")
+	fullName := "data.gcore_storage_locations.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + `
+data "gcore_storage_locations" "test" {
+  type = "ftp"
+}
+`,
+				ExpectError: regexp.MustCompile(`expected type to be one of \[s3 sftp\]`),
+			},
+			{
+				Config: api.providerHCL("") + `
+data "gcore_storage_locations" "test" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "id", "all"),
+					resource.TestCheckResourceAttr(fullName, "locations.#", "10"),
+				),
+			},
+			{
+				Config: api.providerHCL("") + `
+data "gcore_storage_locations" "test" {
+  type = "s3"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "id", "s3"),
+					resource.TestCheckResourceAttr(fullName, "locations.#", "6"),
+					resource.TestCheckResourceAttr(fullName, "locations.0.name", "s-darz1"),
+					resource.TestCheckResourceAttr(fullName, "locations.0.type", "s3"),
+					resource.TestCheckResourceAttr(fullName, "locations.0.address", "s-darz1.cloud.example.com"),
+					resource.TestCheckResourceAttr(fullName, "locations.0.allow_for_new_storage", "true"),
+					resource.TestCheckResourceAttr(fullName, "locations.0.s3_endpoint", "https://s-darz1.cloud.example.com"),
+					resource.TestCheckResourceAttr(fullName, "locations.0.http_endpoint", "https://s-darz1.cloud.example.com/{bucket_name}"),
+					resource.TestCheckResourceAttr(fullName, "locations.4.name", "s-old1"),
+					resource.TestCheckResourceAttr(fullName, "locations.4.allow_for_new_storage", "false"),
+				),
+			},
+			{
+				Config: api.providerHCL("") + `
+data "gcore_storage_locations" "test" {
+  type = "sftp"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "locations.#", "4"),
+					resource.TestCheckResourceAttr(fullName, "locations.0.name", "ams"),
+					resource.TestCheckResourceAttr(fullName, "locations.0.s3_endpoint", ""),
+				),
+			},
+		},
+	})
+}
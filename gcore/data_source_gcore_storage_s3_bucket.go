@@ -30,6 +30,35 @@ func dataSourceStorageS3Bucket() *schema.Resource {
 				},
 				Description: "A name of storage bucket resource.",
 			},
+			StorageS3BucketSchemaCORS: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "CORS settings of the bucket.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						StorageS3BucketSchemaAllowedOrigins: {
+							Type:        schema.TypeSet,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Origins allowed to read the objects.",
+						},
+					},
+				},
+			},
+			StorageS3BucketSchemaLifecycle: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Lifecycle rules of the objects of the bucket.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						StorageS3BucketSchemaExpirationDays: {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Days after which the objects are removed.",
+						},
+					},
+				},
+			},
 		},
 		ReadContext: resourceStorageS3BucketRead,
 		Description: "Represent storage s3 bucket resource.",
@@ -0,0 +1,37 @@
+//go:build !cloud
+// +build !cloud
+
+package gcore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitStorageS3BucketDataSource(t *testing.T) {
+	api := newMockAPI(t)
+	storageID := api.storage.SeedStorage("s3", "s-ed1", "unit-s3")
+	api.storage.SeedBucket(storageID, "bucket", map[string]interface{}{"lifecycle": 14, "cors": []interface{}{"*"}})
+	fullName := "data.gcore_storage_s3_bucket.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+data "gcore_storage_s3_bucket" "test" {
+  storage_id = %d
+  name       = "bucket"
+}
+`, storageID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "id", fmt.Sprintf("%d:bucket", storageID)),
+					resource.TestCheckResourceAttr(fullName, "cors.0.allowed_origins.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "lifecycle_rule.0.expiration_days", "14"),
+				),
+			},
+		},
+	})
+}
@@ -54,7 +54,7 @@ func dataSourceStorageSFTP() *schema.Resource {
 			StorageSchemaLocation: {
 				Type:        schema.TypeString,
 				Computed:    true,
-				Description: "A location of new storage resource. One of (ams, sin, fra, mia)",
+				Description: "A location of the storage, e.g. ams.",
 			},
 			StorageSFTPSchemaKeyId: {
 				Type: schema.TypeList,
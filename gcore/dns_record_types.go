@@ -0,0 +1,258 @@
+package gcore
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	dnssdk "github.com/G-Core/gcore-dns-sdk-go"
+)
+
+// dnsRecordType validates and normalises the content of a record type.
+type dnsRecordType struct {
+	// normalize returns the canonical content, names are lowercase and end
+	// with a dot.
+	normalize func(content string) (string, error)
+	// content splits the canonical content into the parts the API expects,
+	// the SDK splits it when nil.
+	content func(content string) []interface{}
+}
+
+// dnsRecordTypes are the record types the provider supports.
+var dnsRecordTypes = map[string]dnsRecordType{
+	"A": {normalize: func(content string) (string, error) {
+		ip := net.ParseIP(content)
+		if ip == nil || ip.To4() == nil {
+			return "", fmt.Errorf("expected an IPv4 address, got %q", content)
+		}
+		return ip.String(), nil
+	}},
+	"AAAA": {normalize: func(content string) (string, error) {
+		ip := net.ParseIP(content)
+		if ip == nil || ip.To4() != nil {
+			return "", fmt.Errorf("expected an IPv6 address, got %q", content)
+		}
+		return ip.String(), nil
+	}},
+	"CNAME": {normalize: normalizeDNSNameFields()},
+	"NS":    {normalize: normalizeDNSNameFields()},
+	"PTR":   {normalize: normalizeDNSNameFields()},
+	"MX":    {normalize: normalizeDNSNameFields("preference")},
+	"SRV":   {normalize: normalizeDNSNameFields("priority", "weight", "port")},
+	"TXT": {normalize: func(content string) (string, error) {
+		if content == "" {
+			return "", fmt.Errorf("text is empty")
+		}
+		return content, nil
+	}},
+	"CAA":   {normalize: normalizeDNSCAA, content: dnsCAAContent},
+	"HTTPS": {normalize: normalizeDNSSVCB, content: dnsSVCBContent},
+	"SVCB":  {normalize: normalizeDNSSVCB, content: dnsSVCBContent},
+}
+
+// dnsRecordTypeNames returns the supported record types sorted.
+func dnsRecordTypeNames() []string {
+	names := make([]string, 0, len(dnsRecordTypes))
+	for name := range dnsRecordTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// normalizeDNSRecordContent returns the canonical content of a record of the
+// type.
+func normalizeDNSRecordContent(rType, content string) (string, error) {
+	rt, ok := dnsRecordTypes[strings.ToUpper(strings.TrimSpace(rType))]
+	if !ok {
+		return "", fmt.Errorf("dns record type should be one of %v", dnsRecordTypeNames())
+	}
+	return rt.normalize(strings.TrimSpace(content))
+}
+
+// newDNSResourceRecord returns a record of the canonical content with the
+// content parts the API expects.
+func newDNSResourceRecord(rType, content string) *dnssdk.ResourceRecord {
+	rType = strings.ToUpper(strings.TrimSpace(rType))
+	if rt, ok := dnsRecordTypes[rType]; ok && rt.content != nil {
+		return &dnssdk.ResourceRecord{Content: rt.content(content)}
+	}
+	return (&dnssdk.ResourceRecord{}).SetContent(rType, content)
+}
+
+// canonicalDNSName returns the name lowercase with a trailing dot, "." is the
+// root name.
+func canonicalDNSName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "." {
+		return name, nil
+	}
+	trimmed := strings.TrimSuffix(name, ".")
+	if trimmed == "" || len(trimmed) > 253 {
+		return "", fmt.Errorf("invalid name %q", name)
+	}
+	for _, label := range strings.Split(trimmed, ".") {
+		if label == "" || len(label) > 63 {
+			return "", fmt.Errorf("invalid name %q", name)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '*') {
+				return "", fmt.Errorf("invalid name %q", name)
+			}
+		}
+	}
+	return trimmed + ".", nil
+}
+
+// suppressDNSNameDiff hides the difference of case and of the trailing dot
+// between two names.
+func suppressDNSNameDiff(old, new string) bool {
+	return strings.EqualFold(strings.TrimSuffix(old, "."), strings.TrimSuffix(new, "."))
+}
+
+// normalizeDNSNameFields normalises content of 16 bit numbers followed by a
+// name.
+func normalizeDNSNameFields(numbers ...string) func(content string) (string, error) {
+	return func(content string) (string, error) {
+		fields := strings.Fields(content)
+		if len(fields) != len(numbers)+1 {
+			return "", fmt.Errorf("%q should be %s", content, strings.Join(append(numbers, "name"), " "))
+		}
+		for i, n := range numbers {
+			v, err := strconv.ParseUint(fields[i], 10, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid %s %q, it should be 0-65535", n, fields[i])
+			}
+			fields[i] = strconv.FormatUint(v, 10)
+		}
+		name, err := canonicalDNSName(fields[len(numbers)])
+		if err != nil {
+			return "", err
+		}
+		fields[len(numbers)] = name
+		return strings.Join(fields, " "), nil
+	}
+}
+
+// dnsCAATags are the CAA property tags of RFC 8659.
+var dnsCAATags = []string{"issue", "issuewild", "iodef", "contactemail", "contactphone"}
+
+// normalizeDNSCAA normalises 'flags tag "value"', the value is quoted.
+func normalizeDNSCAA(content string) (string, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 3 {
+		return "", fmt.Errorf(`%q should be flags tag "value"`, content)
+	}
+	flags, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil {
+		return "", fmt.Errorf("invalid flags %q, it should be 0-255", fields[0])
+	}
+	tag := strings.ToLower(fields[1])
+	known := false
+	for _, t := range dnsCAATags {
+		known = known || t == tag
+	}
+	if !known {
+		return "", fmt.Errorf("invalid tag %q, it should be one of %v", fields[1], dnsCAATags)
+	}
+	// The value keeps its spaces.
+	value := strings.TrimSpace(content[strings.Index(content, fields[1])+len(fields[1]):])
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		value = value[1 : len(value)-1]
+	}
+	return fmt.Sprintf(`%d %s "%s"`, flags, tag, value), nil
+}
+
+// dnsCAAContent splits the content into the flags, the tag and the value,
+// the value may hold spaces.
+func dnsCAAContent(content string) []interface{} {
+	parts := strings.SplitN(content, " ", 3)
+	flags, _ := strconv.ParseInt(parts[0], 10, 64)
+	return []interface{}{flags, parts[1], parts[2]}
+}
+
+// dnsSVCBKeys are the SvcParamKeys of RFC 9460, others are given as keyNNNNN.
+var dnsSVCBKeys = []string{"mandatory", "alpn", "no-default-alpn", "port", "ipv4hint", "ech", "ipv6hint"}
+
+// normalizeDNSSVCB normalises "priority target key=value...", the priority 0
+// is the alias form without parameters.
+func normalizeDNSSVCB(content string) (string, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("%q should be priority target [key=value...]", content)
+	}
+	priority, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return "", fmt.Errorf("invalid priority %q, it should be 0-65535", fields[0])
+	}
+	target, err := canonicalDNSName(fields[1])
+	if err != nil {
+		return "", err
+	}
+	res := []string{strconv.FormatUint(priority, 10), target}
+	params := fields[2:]
+	if priority == 0 && len(params) > 0 {
+		return "", fmt.Errorf("alias form with priority 0 can't have parameters")
+	}
+	for _, p := range params {
+		kv := strings.SplitN(p, "=", 2)
+		key, hasValue := strings.ToLower(kv[0]), len(kv) == 2
+		value := ""
+		if hasValue {
+			value = strings.Trim(kv[1], `"`)
+		}
+		known := strings.HasPrefix(key, "key") && len(key) > 3
+		if known {
+			_, err := strconv.ParseUint(key[3:], 10, 16)
+			known = err == nil
+		}
+		for _, k := range dnsSVCBKeys {
+			known = known || k == key
+		}
+		if !known {
+			return "", fmt.Errorf("invalid parameter %q, it should be one of %v or keyNNNNN", key, dnsSVCBKeys)
+		}
+		switch key {
+		case "no-default-alpn":
+			if hasValue {
+				return "", fmt.Errorf("parameter %s has no value", key)
+			}
+			res = append(res, key)
+			continue
+		case "port":
+			if _, err := strconv.ParseUint(value, 10, 16); err != nil {
+				return "", fmt.Errorf("invalid port %q, it should be 0-65535", value)
+			}
+		case "ipv4hint", "ipv6hint":
+			for _, v := range strings.Split(value, ",") {
+				ip := net.ParseIP(v)
+				if ip == nil || (ip.To4() != nil) != (key == "ipv4hint") {
+					return "", fmt.Errorf("invalid %s address %q", key, v)
+				}
+			}
+		}
+		if value == "" {
+			return "", fmt.Errorf("parameter %s needs a value", key)
+		}
+		res = append(res, key+"="+value)
+	}
+	return strings.Join(res, " "), nil
+}
+
+// dnsSVCBContent splits the content into the priority, the target and the
+// parameters.
+func dnsSVCBContent(content string) []interface{} {
+	fields := strings.Fields(content)
+	res := make([]interface{}, 0, len(fields))
+	for i, f := range fields {
+		if i == 0 {
+			priority, _ := strconv.ParseInt(f, 10, 64)
+			res = append(res, priority)
+			continue
+		}
+		res = append(res, f)
+	}
+	return res
+}
@@ -0,0 +1,482 @@
+package gcore
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	dnssdk "github.com/G-Core/gcore-dns-sdk-go"
+)
+
+// dnsZoneFileRRSet is a rrset of a BIND zone file, its records are built by
+// newDNSResourceRecord like fillRRSet does.
+type dnsZoneFileRRSet struct {
+	Domain string
+	Type   string
+	RRSet  dnssdk.RRSet
+}
+
+// dnsZoneFileToken is a word of a zone file entry, quoted holds whether it
+// was a quoted string.
+type dnsZoneFileToken struct {
+	text   string
+	quoted bool
+}
+
+// dnsZoneFileEntry is a logical line of a zone file: parentheses are joined
+// and comments are dropped. A blank owner starts the line with a space.
+type dnsZoneFileEntry struct {
+	line       int
+	blankOwner bool
+	tokens     []dnsZoneFileToken
+}
+
+// dnsZoneFileType converts the record data of a type between the zone file
+// and the content of the API, the content is then normalised like the one of
+// gcore_dns_zone_record.
+type dnsZoneFileType struct {
+	// parse returns the content of the record data, names are made absolute
+	// with origin.
+	parse func(origin string, rdata []dnsZoneFileToken) (string, error)
+	// render returns the record data of the normalised content.
+	render func(content string) string
+}
+
+// dnsZoneFileTypes are the record types of a zone file the provider supports,
+// the ones of dnsRecordTypes.
+var dnsZoneFileTypes = map[string]dnsZoneFileType{
+	"A":     {parse: dnsZoneFileParseWord},
+	"AAAA":  {parse: dnsZoneFileParseWord},
+	"CNAME": {parse: dnsZoneFileParseNames()},
+	"NS":    {parse: dnsZoneFileParseNames()},
+	"PTR":   {parse: dnsZoneFileParseNames()},
+	"MX":    {parse: dnsZoneFileParseNames("preference")},
+	"SRV":   {parse: dnsZoneFileParseNames("priority", "weight", "port")},
+	"TXT": {
+		parse: func(origin string, rdata []dnsZoneFileToken) (string, error) {
+			if len(rdata) == 0 {
+				return "", fmt.Errorf("expected a text")
+			}
+			// The strings of a record are one text.
+			var sb strings.Builder
+			for _, t := range rdata {
+				sb.WriteString(t.text)
+			}
+			return sb.String(), nil
+		},
+		render: func(content string) string {
+			// A string of a zone file holds up to 255 characters.
+			var parts []string
+			for len(content) > 255 {
+				parts = append(parts, dnsZoneFileQuote(content[:255]))
+				content = content[255:]
+			}
+			return strings.Join(append(parts, dnsZoneFileQuote(content)), " ")
+		},
+	},
+	"CAA": {
+		parse: func(origin string, rdata []dnsZoneFileToken) (string, error) {
+			if len(rdata) != 3 {
+				return "", fmt.Errorf("expected flags, tag and value")
+			}
+			if _, err := strconv.ParseUint(rdata[0].text, 10, 8); err != nil {
+				return "", fmt.Errorf("invalid flags %q", rdata[0].text)
+			}
+			return fmt.Sprintf("%s %s %s", rdata[0].text, rdata[1].text, dnsZoneFileQuote(rdata[2].text)), nil
+		},
+	},
+	"HTTPS": {parse: dnsZoneFileParseSVCB},
+	"SVCB":  {parse: dnsZoneFileParseSVCB},
+}
+
+// dnsZoneFileParseWord parses record data of a single word.
+func dnsZoneFileParseWord(origin string, rdata []dnsZoneFileToken) (string, error) {
+	if len(rdata) != 1 {
+		return "", fmt.Errorf("expected a single value")
+	}
+	return rdata[0].text, nil
+}
+
+// dnsZoneFileParseNames parses record data of numbers followed by a name.
+func dnsZoneFileParseNames(numbers ...string) func(origin string, rdata []dnsZoneFileToken) (string, error) {
+	return func(origin string, rdata []dnsZoneFileToken) (string, error) {
+		if len(rdata) != len(numbers)+1 {
+			return "", fmt.Errorf("expected %s", strings.Join(append(numbers, "name"), ", "))
+		}
+		parts := make([]string, 0, len(rdata))
+		for i, n := range numbers {
+			if _, err := strconv.ParseUint(rdata[i].text, 10, 16); err != nil {
+				return "", fmt.Errorf("invalid %s %q", n, rdata[i].text)
+			}
+			parts = append(parts, rdata[i].text)
+		}
+		name, err := dnsZoneFileName(origin, rdata[len(numbers)].text)
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(append(parts, name+"."), " "), nil
+	}
+}
+
+// dnsZoneFileParseSVCB parses the priority, the target and the parameters of
+// SVCB and HTTPS, a quoted value follows its key= word.
+func dnsZoneFileParseSVCB(origin string, rdata []dnsZoneFileToken) (string, error) {
+	if len(rdata) < 2 {
+		return "", fmt.Errorf("expected priority, target and parameters")
+	}
+	target := "."
+	if rdata[1].text != "." {
+		name, err := dnsZoneFileName(origin, rdata[1].text)
+		if err != nil {
+			return "", err
+		}
+		target = name + "."
+	}
+	parts := []string{rdata[0].text, target}
+	for i := 2; i < len(rdata); i++ {
+		param := rdata[i].text
+		if strings.HasSuffix(param, "=") && i+1 < len(rdata) && rdata[i+1].quoted {
+			i++
+			param += rdata[i].text
+		}
+		parts = append(parts, param)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// dnsZoneFileName returns the name relative to origin as an absolute name
+// without the trailing dot.
+func dnsZoneFileName(origin, name string) (string, error) {
+	switch {
+	case name == "@":
+		name = origin
+	case strings.HasSuffix(name, "."):
+	case origin == "":
+		return "", fmt.Errorf("relative name %q without $ORIGIN", name)
+	default:
+		name = name + "." + origin
+	}
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if name == "" {
+		return "", fmt.Errorf("empty name")
+	}
+	return name, nil
+}
+
+func dnsZoneFileQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// parseDNSZoneFile parses a RFC 1035 zone file into rrsets in the order they
+// first appear and returns the last origin. The SOA record is skipped since
+// the API manages it, other types the provider doesn't support are an error.
+// origin is used until the file sets $ORIGIN, a relative name without an
+// origin is an error.
+func parseDNSZoneFile(content, origin string) ([]dnsZoneFileRRSet, string, error) {
+	entries, err := dnsZoneFileEntries(content)
+	if err != nil {
+		return nil, "", err
+	}
+
+	origin = strings.TrimSuffix(origin, ".")
+	var (
+		res        []dnsZoneFileRRSet
+		index      = make(map[string]int)
+		defaultTTL = -1
+		lastTTL    = -1
+		owner      string
+	)
+	for _, e := range entries {
+		fail := func(format string, args ...interface{}) error {
+			return fmt.Errorf("line %d: %s", e.line, fmt.Sprintf(format, args...))
+		}
+		tokens := e.tokens
+
+		if !e.blankOwner && !tokens[0].quoted && strings.HasPrefix(tokens[0].text, "$") {
+			switch strings.ToUpper(tokens[0].text) {
+			case "$ORIGIN":
+				if len(tokens) != 2 {
+					return nil, "", fail("$ORIGIN expects a name")
+				}
+				name, err := dnsZoneFileName(origin, tokens[1].text)
+				if err != nil {
+					return nil, "", fail("%s", err)
+				}
+				origin = name
+			case "$TTL":
+				if len(tokens) != 2 {
+					return nil, "", fail("$TTL expects a ttl")
+				}
+				ttl, err := parseDNSZoneFileTTL(tokens[1].text)
+				if err != nil {
+					return nil, "", fail("%s", err)
+				}
+				defaultTTL = ttl
+			default:
+				return nil, "", fail("directive %s is not supported", tokens[0].text)
+			}
+			continue
+		}
+
+		if !e.blankOwner {
+			name, err := dnsZoneFileName(origin, tokens[0].text)
+			if err != nil {
+				return nil, "", fail("%s", err)
+			}
+			owner = name
+			tokens = tokens[1:]
+		}
+		if owner == "" {
+			return nil, "", fail("record without an owner")
+		}
+
+		// TTL and class come in any order before the type.
+		ttl := -1
+		var rType string
+		for len(tokens) > 0 && rType == "" {
+			t := tokens[0].text
+			tokens = tokens[1:]
+			switch {
+			case strings.EqualFold(t, "IN"):
+			case strings.EqualFold(t, "CH"), strings.EqualFold(t, "HS"), strings.EqualFold(t, "CS"):
+				return nil, "", fail("class %s is not supported", t)
+			case ttl < 0 && t != "" && t[0] >= '0' && t[0] <= '9':
+				v, err := parseDNSZoneFileTTL(t)
+				if err != nil {
+					return nil, "", fail("%s", err)
+				}
+				ttl = v
+			default:
+				rType = strings.ToUpper(t)
+			}
+		}
+		if rType == "" {
+			return nil, "", fail("record without a type")
+		}
+		if ttl < 0 {
+			ttl = defaultTTL
+		}
+		if ttl < 0 {
+			// RFC 2308: the last explicit TTL applies without $TTL.
+			ttl = lastTTL
+		} else {
+			lastTTL = ttl
+		}
+		if ttl < 0 {
+			ttl = 0
+		}
+
+		if rType == "SOA" {
+			continue
+		}
+		zt, ok := dnsZoneFileTypes[rType]
+		if !ok {
+			return nil, "", fail("record type %s is not supported", rType)
+		}
+		content, err := zt.parse(origin, tokens)
+		if err == nil {
+			content, err = normalizeDNSRecordContent(rType, content)
+		}
+		if err != nil {
+			return nil, "", fail("%s %s: %s", owner, rType, err)
+		}
+
+		key := owner + "/" + rType
+		i, ok := index[key]
+		if !ok {
+			i = len(res)
+			index[key] = i
+			res = append(res, dnsZoneFileRRSet{
+				Domain: owner,
+				Type:   rType,
+				RRSet:  dnssdk.RRSet{TTL: ttl, Records: make([]dnssdk.ResourceRecord, 0)},
+			})
+		}
+		rr := newDNSResourceRecord(rType, content)
+		rr.Enabled = true
+		res[i].RRSet.Records = append(res[i].RRSet.Records, *rr)
+	}
+	return res, origin, nil
+}
+
+// parseDNSZoneFileTTL parses a TTL in seconds or with BIND units, e.g. 1h30m.
+func parseDNSZoneFileTTL(s string) (int, error) {
+	if v, err := strconv.ParseUint(s, 10, 31); err == nil {
+		return int(v), nil
+	}
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	var total, n int
+	var digits bool
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			n = n*10 + int(c-'0')
+			digits = true
+		case units[c|0x20] != 0 && digits:
+			total += n * units[c|0x20]
+			n, digits = 0, false
+		default:
+			return 0, fmt.Errorf("invalid ttl %q", s)
+		}
+		if total+n > 1<<31-1 {
+			return 0, fmt.Errorf("ttl %q is too big", s)
+		}
+	}
+	if digits {
+		return 0, fmt.Errorf("invalid ttl %q", s)
+	}
+	return total, nil
+}
+
+// dnsZoneFileEntries splits a zone file into entries.
+func dnsZoneFileEntries(content string) ([]dnsZoneFileEntry, error) {
+	var (
+		res     []dnsZoneFileEntry
+		cur     dnsZoneFileEntry
+		word    strings.Builder
+		inWord  bool
+		quoted  bool
+		depth   int
+		line    = 1
+		started bool
+	)
+	flushWord := func() {
+		if inWord {
+			cur.tokens = append(cur.tokens, dnsZoneFileToken{text: word.String(), quoted: quoted})
+		}
+		word.Reset()
+		inWord, quoted = false, false
+	}
+	flushEntry := func() {
+		flushWord()
+		if len(cur.tokens) > 0 {
+			res = append(res, cur)
+		}
+		cur = dnsZoneFileEntry{}
+		started = false
+	}
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if !started && depth == 0 {
+			cur.line = line
+			cur.blankOwner = c == ' ' || c == '\t'
+			started = true
+		}
+		switch {
+		case quoted && c == '"':
+			cur.tokens = append(cur.tokens, dnsZoneFileToken{text: word.String(), quoted: true})
+			word.Reset()
+			inWord, quoted = false, false
+		case c == '\\' && i+1 < len(content):
+			i++
+			if content[i] == '\n' {
+				line++
+			}
+			word.WriteByte(content[i])
+			inWord = true
+		case quoted:
+			if c == '\n' {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			word.WriteByte(c)
+		case c == '"':
+			flushWord()
+			inWord, quoted = true, true
+		case c == ';':
+			for i+1 < len(content) && content[i+1] != '\n' {
+				i++
+			}
+		case c == '(':
+			flushWord()
+			depth++
+		case c == ')':
+			flushWord()
+			if depth == 0 {
+				return nil, fmt.Errorf("line %d: unbalanced parenthesis", line)
+			}
+			depth--
+		case c == '\n':
+			line++
+			if depth == 0 {
+				flushEntry()
+			} else {
+				flushWord()
+			}
+		case c == ' ' || c == '\t' || c == '\r':
+			flushWord()
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("line %d: unterminated string", line)
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parenthesis", line)
+	}
+	flushEntry()
+	return res, nil
+}
+
+// renderDNSZoneFile renders the rrsets of the zone in BIND format. Owners are
+// relative to the zone and the rrsets are sorted by owner and type.
+func renderDNSZoneFile(zone string, rrsets []dnsZoneFileRRSet) string {
+	zone = strings.TrimSuffix(zone, ".")
+	sorted := make([]dnsZoneFileRRSet, len(rrsets))
+	copy(sorted, rrsets)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Domain != sorted[j].Domain {
+			return sorted[i].Domain < sorted[j].Domain
+		}
+		return sorted[i].Type < sorted[j].Type
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "$ORIGIN %s.\n", zone)
+	for _, rrset := range sorted {
+		domain := strings.TrimSuffix(rrset.Domain, ".")
+		owner := domain + "."
+		switch {
+		case domain == zone:
+			owner = "@"
+		case strings.HasSuffix(domain, "."+zone):
+			owner = strings.TrimSuffix(domain, "."+zone)
+		}
+		render := dnsZoneFileTypes[strings.ToUpper(rrset.Type)].render
+		for _, rr := range rrset.RRSet.Records {
+			content := rr.ContentToString()
+			// The API may return names without the trailing dot.
+			if normalized, err := normalizeDNSRecordContent(rrset.Type, content); err == nil {
+				content = normalized
+			}
+			if render != nil {
+				content = render(content)
+			}
+			fmt.Fprintf(&sb, "%s\t%d\tIN\t%s\t%s\n", owner, rrset.RRSet.TTL, strings.ToUpper(rrset.Type), content)
+		}
+	}
+	return sb.String()
+}
+
+// dnsZoneFileRRSetsFromZone converts the records of a zone of the API.
+func dnsZoneFileRRSetsFromZone(zone dnssdk.Zone) []dnsZoneFileRRSet {
+	res := make([]dnsZoneFileRRSet, 0, len(zone.Records))
+	for _, r := range zone.Records {
+		rrset := dnsZoneFileRRSet{
+			Domain: strings.TrimSuffix(r.Name, "."),
+			Type:   strings.ToUpper(r.Type),
+			RRSet:  dnssdk.RRSet{TTL: int(r.TTL), Records: make([]dnssdk.ResourceRecord, 0, len(r.ShortAnswers))},
+		}
+		for _, answer := range r.ShortAnswers {
+			rr := newDNSResourceRecord(rrset.Type, answer)
+			rr.Enabled = true
+			rrset.RRSet.Records = append(rrset.RRSet.Records, *rr)
+		}
+		res = append(res, rrset)
+	}
+	return res
+}
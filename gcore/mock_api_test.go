@@ -0,0 +1,280 @@
+package gcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const mockAPIToken = "mock-api-token"
+
+// mockAPI is an in-process fake of the Cloud, CDN, DNS and Storage APIs,
+// and of the S3 API of the storages. It is wired into the provider through
+// the regular api_endpoint and gcore_*_api options, so resources run their
+// real CRUD code against it without any network access.
+type mockAPI struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu       sync.Mutex
+	seq      int
+	requests []string
+	failures []*mockFailure
+
+	cloud   *mockCloud
+	cdn     *mockCDN
+	dns     *mockDNS
+	storage *mockStorage
+}
+
+func newMockAPI(t *testing.T) *mockAPI {
+	t.Helper()
+
+	api := &mockAPI{t: t}
+	api.cloud = newMockCloud(api)
+	api.cdn = newMockCDN(api)
+	api.dns = newMockDNS(api)
+	api.storage = newMockStorage(api)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/jwt/login", api.serveLogin)
+	mux.HandleFunc("/cloud/", api.cloud.serve)
+	mux.HandleFunc("/cdn/", api.cdn.serve)
+	mux.HandleFunc("/dns/", api.dns.serve)
+	mux.HandleFunc("/storage/", api.storage.serve)
+	mux.HandleFunc("/s3/", api.storage.serveS3)
+
+	api.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		api.requests = append(api.requests, r.Method+" "+r.URL.Path)
+		if api.fail(w, r) {
+			return
+		}
+		if r.URL.Path != "/auth/jwt/login" && r.Header.Get("Authorization") == "" {
+			mockWriteJSON(w, http.StatusUnauthorized, map[string]interface{}{
+				"message": "authorization header is missing",
+				"error":   "authorization header is missing",
+			})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(api.server.Close)
+
+	return api
+}
+
+// S3Endpoint returns the S3 endpoint of the storages of the fake API.
+func (api *mockAPI) S3Endpoint() string {
+	return api.server.URL + "/s3"
+}
+
+// URL returns the base URL of the fake API, suitable for api_endpoint.
+func (api *mockAPI) URL() string {
+	return api.server.URL
+}
+
+// providerConfig returns the provider block pointing every product at the
+// fake API. Product endpoints are set explicitly so that GCORE_*_API
+// variables of the environment can't leak into unit tests.
+func (api *mockAPI) providerConfig() map[string]interface{} {
+	return map[string]interface{}{
+		ProviderOptPermanentToken:    mockAPIToken,
+		ProviderOptSingleApiEndpoint: api.URL(),
+		"gcore_platform_api":         api.URL(),
+		"gcore_cloud_api":            api.URL() + "/cloud",
+		"gcore_cdn_api":              api.URL(),
+		"gcore_dns_api":              api.URL() + "/dns",
+		"gcore_storage_api":          api.URL() + "/storage",
+	}
+}
+
+// providerHCL returns providerConfig as a provider block, extra holds more
+// provider arguments, e.g. the default project and region.
+func (api *mockAPI) providerHCL(extra string) string {
+	return fmt.Sprintf(`
+provider "gcore" {
+  permanent_api_token = "%s"
+  api_endpoint        = "%s"
+  gcore_platform_api  = "%s"
+  gcore_cloud_api     = "%s/cloud"
+  gcore_cdn_api       = "%s"
+  gcore_dns_api       = "%s/dns"
+  gcore_storage_api   = "%s/storage"
+  %s
+}
+`, mockAPIToken, api.URL(), api.URL(), api.URL(), api.URL(), api.URL(), api.URL(), extra)
+}
+
+// Requests returns the "METHOD /path" log of calls matching the prefix.
+func (api *mockAPI) Requests(prefix string) []string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	var res []string
+	for _, r := range api.requests {
+		if strings.HasPrefix(r, prefix) {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// ResetRequests clears the request log.
+func (api *mockAPI) ResetRequests() {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.requests = nil
+}
+
+// mockFailure makes the next requests matching the method and the path
+// prefix fail with the status.
+type mockFailure struct {
+	method     string
+	prefix     string
+	status     int
+	retryAfter string
+	count      int
+}
+
+// FailNext makes the next count requests matching the method and the path
+// prefix fail with the status and the Retry-After header, when it's set.
+func (api *mockAPI) FailNext(method, prefix string, status int, retryAfter string, count int) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.failures = append(api.failures, &mockFailure{
+		method:     method,
+		prefix:     prefix,
+		status:     status,
+		retryAfter: retryAfter,
+		count:      count,
+	})
+}
+
+func (api *mockAPI) fail(w http.ResponseWriter, r *http.Request) bool {
+	for _, f := range api.failures {
+		if f.count == 0 || f.method != r.Method || !strings.HasPrefix(r.URL.Path, f.prefix) {
+			continue
+		}
+		f.count--
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		mockWriteJSON(w, f.status, map[string]interface{}{
+			"message": http.StatusText(f.status),
+			"error":   http.StatusText(f.status),
+		})
+		return true
+	}
+	return false
+}
+
+func (api *mockAPI) nextID() int {
+	api.seq++
+	return api.seq
+}
+
+// uuid returns a unique, well-formed version 4 UUID. The Cloud SDK
+// validates identifiers, so plain counters aren't enough.
+func (api *mockAPI) uuid() string {
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", 0xdeadbeef, api.nextID())
+}
+
+func (api *mockAPI) serveLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		mockWriteJSON(w, http.StatusMethodNotAllowed, nil)
+		return
+	}
+	mockWriteJSON(w, http.StatusOK, map[string]interface{}{
+		"access":  mockAPIToken,
+		"refresh": mockAPIToken,
+	})
+}
+
+func mockWriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if v == nil || status == http.StatusNoContent {
+		return
+	}
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func mockReadJSON(r *http.Request) (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return body, nil
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("invalid json body: %w", err)
+	}
+	return body, nil
+}
+
+// mockCopy returns a deep copy of a JSON object with the internal
+// bookkeeping keys (prefixed with "_") stripped.
+func mockCopy(obj map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(obj)
+	res := make(map[string]interface{})
+	_ = json.Unmarshal(data, &res)
+	for k := range res {
+		if strings.HasPrefix(k, "_") {
+			delete(res, k)
+		}
+	}
+	return res
+}
+
+func mockString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func mockInt(v interface{}) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	case json.Number:
+		i, _ := n.Int64()
+		return int(i)
+	}
+	return 0
+}
+
+func mockBool(v interface{}) bool {
+	b, _ := v.(bool)
+	return b
+}
+
+func mockList(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func mockMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func mockSortedKeys(m map[string]map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
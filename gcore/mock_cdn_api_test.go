@@ -0,0 +1,621 @@
+package gcore
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const mockCDNClientID = 1
+
+// mockCDN is the CDN API part of mockAPI. Objects are stored by numeric ID
+// and rendered the way the API returns them.
+type mockCDN struct {
+	api          *mockAPI
+	resources    map[int]map[string]interface{}
+	rules        map[int]map[int]map[string]interface{}
+	originGroups map[int]map[string]interface{}
+	sslData      map[int]map[string]interface{}
+	// cacheTasks are the purges and the prefetches, a task is in progress
+	// until it is listed once.
+	cacheTasks []map[string]interface{}
+}
+
+func newMockCDN(api *mockAPI) *mockCDN {
+	return &mockCDN{
+		api:          api,
+		resources:    make(map[int]map[string]interface{}),
+		rules:        make(map[int]map[int]map[string]interface{}),
+		originGroups: make(map[int]map[string]interface{}),
+		sslData:      make(map[int]map[string]interface{}),
+	}
+}
+
+// Resource returns a copy of the stored CDN resource, or nil.
+func (c *mockCDN) Resource(id int) map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	if r := c.resources[id]; r != nil {
+		return mockCopy(r)
+	}
+	return nil
+}
+
+// OriginGroup returns a copy of the stored origin group, or nil.
+func (c *mockCDN) OriginGroup(id int) map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	if g := c.originGroups[id]; g != nil {
+		return mockCopy(g)
+	}
+	return nil
+}
+
+// Rule returns a copy of the stored rule of the resource, or nil.
+func (c *mockCDN) Rule(resourceID, id int) map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	if r := c.rules[resourceID][id]; r != nil {
+		return mockCopy(r)
+	}
+	return nil
+}
+
+// SSLData returns a copy of the stored SSL certificate, or nil.
+func (c *mockCDN) SSLData(id int) map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	if d := c.sslData[id]; d != nil {
+		return mockCopy(d)
+	}
+	return nil
+}
+
+// SeedResource creates a CDN resource using the origin group and returns
+// its ID.
+func (c *mockCDN) SeedResource(cname string, originGroup int) int {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	res, err := c.createResource(map[string]interface{}{"cname": cname, "originGroup": originGroup})
+	if err != nil {
+		c.api.t.Fatalf("seed cdn resource: %v", err)
+	}
+	return mockInt(res["id"])
+}
+
+// SeedCacheTask adds a purge or a prefetch of the resource with the status.
+func (c *mockCDN) SeedCacheTask(resourceID int, taskType, status string, paths ...interface{}) {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	c.addCacheTask(c.resources[resourceID], taskType, status, paths)
+}
+
+// SeedOriginGroup creates an origin group and returns its ID.
+func (c *mockCDN) SeedOriginGroup(name, source string) int {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	return c.createOriginGroup(map[string]interface{}{
+		"name":    name,
+		"useNext": true,
+		"origins": []interface{}{map[string]interface{}{"source": source, "enabled": true}},
+	})
+}
+
+// SeedRule creates a rule of the resource from the API body.
+func (c *mockCDN) SeedRule(resourceID int, body map[string]interface{}) error {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	res := c.resources[resourceID]
+	if res == nil {
+		return fmt.Errorf("resource %d not found", resourceID)
+	}
+	id := c.api.nextID()
+	rule := map[string]interface{}{"id": id, "deleted": false}
+	if err := c.applyRule(res, rule, body); err != nil {
+		return err
+	}
+	c.rules[resourceID][id] = rule
+	return nil
+}
+
+func (c *mockCDN) fail(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	mockWriteJSON(w, status, map[string]interface{}{"message": fmt.Sprintf(format, args...)})
+}
+
+func (c *mockCDN) serve(w http.ResponseWriter, r *http.Request) {
+	segs := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/cdn/"), "/"), "/")
+	ids := make([]int, 0, 2)
+	for _, i := range []int{1, 3} {
+		if i >= len(segs) {
+			break
+		}
+		id, err := strconv.Atoi(segs[i])
+		if err != nil {
+			c.fail(w, http.StatusNotFound, "invalid id %q", segs[i])
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	var body map[string]interface{}
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		var err error
+		if body, err = mockReadJSON(r); err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	switch {
+	case segs[0] == "resources" && len(segs) == 3 && (segs[2] == "purge" || segs[2] == "prefetch"):
+		c.serveCacheTask(w, r, ids, segs[2], body)
+	case segs[0] == "purge_statuses":
+		c.serveCacheTaskStatuses(w, r)
+	case segs[0] == "resources" && len(segs) >= 3 && segs[2] == "rules":
+		c.serveRules(w, r, ids, body)
+	case segs[0] == "resources":
+		c.serveResources(w, r, ids, body)
+	case segs[0] == "originGroups":
+		c.serveOriginGroups(w, r, ids, body)
+	case segs[0] == "sslData":
+		c.serveSSLData(w, r, ids, body)
+	default:
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+	}
+}
+
+func (c *mockCDN) serveResources(w http.ResponseWriter, r *http.Request, ids []int, body map[string]interface{}) {
+	if len(ids) == 0 {
+		if r.Method != http.MethodPost {
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+			return
+		}
+		res, err := c.createResource(body)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		mockWriteJSON(w, http.StatusCreated, res)
+		return
+	}
+
+	res := c.resources[ids[0]]
+	if res == nil {
+		c.fail(w, http.StatusNotFound, "resource %d not found", ids[0])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		mockWriteJSON(w, http.StatusOK, res)
+	case http.MethodPut:
+		group := mockInt(body["originGroup"])
+		if c.originGroups[group] == nil {
+			c.fail(w, http.StatusBadRequest, "origin group %d not found", group)
+			return
+		}
+		if err := c.checkSSLData(mockBool(body["sslEnabled"]), mockInt(body["sslData"])); err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res["description"] = mockString(body["description"])
+		res["active"] = mockBool(body["active"])
+		res["originGroup"] = group
+		res["originProtocol"] = mockStringOr(body["originProtocol"], mockString(res["originProtocol"]))
+		res["secondaryHostnames"] = mockStrings(body["secondaryHostnames"])
+		res["sslEnabled"] = mockBool(body["sslEnabled"])
+		res["sslData"] = body["sslData"]
+		res["ssl_automated"] = mockBool(body["ssl_automated"])
+		res["options"] = mockOptions(body["options"])
+		res["updated"] = time.Now().UTC().Format(time.RFC3339)
+		mockWriteJSON(w, http.StatusOK, res)
+	case http.MethodDelete:
+		delete(c.resources, ids[0])
+		delete(c.rules, ids[0])
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+func (c *mockCDN) createResource(body map[string]interface{}) (map[string]interface{}, error) {
+	cname := mockString(body["cname"])
+	if cname == "" {
+		return nil, fmt.Errorf("cname is required")
+	}
+	for _, res := range c.resources {
+		if res["cname"] == cname {
+			return nil, fmt.Errorf("cname %s is already used", cname)
+		}
+	}
+	group := mockInt(body["originGroup"])
+	if origin := mockString(body["origin"]); origin != "" {
+		group = c.createOriginGroup(map[string]interface{}{
+			"name":    cname + "_origin_group",
+			"origins": []interface{}{map[string]interface{}{"source": origin, "enabled": true}},
+		})
+	}
+	if c.originGroups[group] == nil {
+		return nil, fmt.Errorf("origin group %d not found", group)
+	}
+	if err := c.checkSSLData(mockBool(body["sslEnabled"]), mockInt(body["sslData"])); err != nil {
+		return nil, err
+	}
+
+	id := c.api.nextID()
+	now := time.Now().UTC().Format(time.RFC3339)
+	res := map[string]interface{}{
+		"id":                 id,
+		"name":               nil,
+		"created":            now,
+		"updated":            now,
+		"status":             "active",
+		"active":             true,
+		"client":             mockCDNClientID,
+		"originGroup":        group,
+		"cname":              cname,
+		"description":        mockString(body["description"]),
+		"secondaryHostnames": mockStrings(body["secondaryHostnames"]),
+		"shielded":           false,
+		"sslEnabled":         mockBool(body["sslEnabled"]),
+		"sslData":            body["sslData"],
+		"ssl_automated":      mockBool(body["ssl_automated"]),
+		"originProtocol":     mockStringOr(body["originProtocol"], "HTTP"),
+		"options":            mockOptions(body["options"]),
+	}
+	c.resources[id] = res
+	c.rules[id] = make(map[int]map[string]interface{})
+	return res, nil
+}
+
+func (c *mockCDN) checkSSLData(enabled bool, id int) error {
+	if id == 0 {
+		return nil
+	}
+	if !enabled {
+		return fmt.Errorf("sslData requires sslEnabled")
+	}
+	if c.sslData[id] == nil {
+		return fmt.Errorf("ssl certificate %d not found", id)
+	}
+	return nil
+}
+
+func (c *mockCDN) serveRules(w http.ResponseWriter, r *http.Request, ids []int, body map[string]interface{}) {
+	res := c.resources[ids[0]]
+	if res == nil {
+		c.fail(w, http.StatusNotFound, "resource %d not found", ids[0])
+		return
+	}
+	rules := c.rules[ids[0]]
+
+	if len(ids) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			list := make([]interface{}, 0, len(rules))
+			for _, id := range mockSortedIntKeys(rules) {
+				list = append(list, rules[id])
+			}
+			mockWriteJSON(w, http.StatusOK, list)
+		case http.MethodPost:
+			id := c.api.nextID()
+			rule := map[string]interface{}{"id": id, "deleted": false}
+			if err := c.applyRule(res, rule, body); err != nil {
+				c.fail(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			rules[id] = rule
+			mockWriteJSON(w, http.StatusCreated, rule)
+		default:
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+		return
+	}
+
+	rule := rules[ids[1]]
+	if rule == nil {
+		c.fail(w, http.StatusNotFound, "rule %d not found", ids[1])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		mockWriteJSON(w, http.StatusOK, rule)
+	case http.MethodPut:
+		if err := c.applyRule(res, rule, body); err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		mockWriteJSON(w, http.StatusOK, rule)
+	case http.MethodDelete:
+		delete(rules, ids[1])
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+// CacheTasks returns copies of the purges and the prefetches.
+func (c *mockCDN) CacheTasks() []map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	tasks := make([]map[string]interface{}, 0, len(c.cacheTasks))
+	for _, task := range c.cacheTasks {
+		tasks = append(tasks, mockCopy(task))
+	}
+	return tasks
+}
+
+func (c *mockCDN) serveCacheTask(w http.ResponseWriter, r *http.Request, ids []int, kind string, body map[string]interface{}) {
+	res := c.resources[ids[0]]
+	if res == nil {
+		c.fail(w, http.StatusNotFound, "resource %d not found", ids[0])
+		return
+	}
+	if r.Method != http.MethodPost {
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+	paths := mockStrings(body["paths"])
+	taskType := "prefetch"
+	switch {
+	case kind == "prefetch" && len(paths) == 0:
+		c.fail(w, http.StatusBadRequest, "paths are required")
+		return
+	case kind == "prefetch":
+	case body["urls"] != nil:
+		taskType, paths = "purge_by_url", mockStrings(body["urls"])
+	case len(paths) > 0:
+		taskType = "purge_by_pattern"
+	default:
+		taskType = "purge_all"
+	}
+	if len(paths) > 100 {
+		c.fail(w, http.StatusBadRequest, "too many paths: %d", len(paths))
+		return
+	}
+	status := "In progress"
+	for _, p := range paths {
+		if strings.Contains(mockString(p), "broken") {
+			status = "Failed"
+		}
+	}
+	c.addCacheTask(res, taskType, status, paths)
+	mockWriteJSON(w, http.StatusCreated, nil)
+}
+
+// addCacheTask stamps the task with a clock running behind the one of the
+// provider, as the clock of the API may.
+func (c *mockCDN) addCacheTask(res map[string]interface{}, taskType, status string, paths []interface{}) {
+	c.cacheTasks = append(c.cacheTasks, map[string]interface{}{
+		"created":    time.Now().Add(-time.Minute).UTC().Format("2006-01-02T15:04:05"),
+		"purge_type": taskType,
+		"status":     status,
+		"paths":      paths,
+		"resource":   map[string]interface{}{"id": mockInt(res["id"]), "cname": res["cname"]},
+	})
+}
+
+func (c *mockCDN) serveCacheTaskStatuses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+	query := r.URL.Query()
+	list := make([]interface{}, 0)
+	for _, task := range c.cacheTasks {
+		if mockString(mockMap(task["resource"])["cname"]) != query.Get("cname") ||
+			task["purge_type"] != query.Get("purge_type") ||
+			mockString(task["created"]) < query.Get("from_created") {
+			continue
+		}
+		list = append(list, mockCopy(task))
+		if task["status"] == "In progress" {
+			task["status"] = "Successful"
+		}
+	}
+	mockWriteJSON(w, http.StatusOK, list)
+}
+
+func (c *mockCDN) applyRule(res, rule, body map[string]interface{}) error {
+	if mockString(body["rule"]) == "" {
+		return fmt.Errorf("rule is required")
+	}
+	if group, ok := body["originGroup"]; ok && group != nil && c.originGroups[mockInt(group)] == nil {
+		return fmt.Errorf("origin group %d not found", mockInt(group))
+	}
+	protocol := mockString(res["originProtocol"])
+	if override := mockString(body["overrideOriginProtocol"]); override != "" {
+		protocol = override
+	}
+	rule["name"] = mockString(body["name"])
+	rule["rule"] = mockString(body["rule"])
+	rule["ruleType"] = mockInt(body["ruleType"])
+	rule["weight"] = mockInt(body["weight"])
+	rule["originGroup"] = body["originGroup"]
+	rule["overrideOriginProtocol"] = body["overrideOriginProtocol"]
+	rule["originProtocol"] = protocol
+	rule["options"] = mockOptions(body["options"])
+	return nil
+}
+
+func (c *mockCDN) createOriginGroup(body map[string]interface{}) int {
+	id := c.api.nextID()
+	group := map[string]interface{}{"id": id}
+	c.applyOriginGroup(group, body)
+	c.originGroups[id] = group
+	return id
+}
+
+func (c *mockCDN) applyOriginGroup(group, body map[string]interface{}) {
+	origins := make([]interface{}, 0)
+	for _, raw := range mockList(body["origins"]) {
+		o := mockMap(raw)
+		origins = append(origins, map[string]interface{}{
+			"id":      c.api.nextID(),
+			"source":  mockString(o["source"]),
+			"backup":  mockBool(o["backup"]),
+			"enabled": mockBool(o["enabled"]),
+		})
+	}
+	group["name"] = mockString(body["name"])
+	group["useNext"] = mockBool(body["useNext"])
+	group["origin_ids"] = origins
+}
+
+func (c *mockCDN) serveOriginGroups(w http.ResponseWriter, r *http.Request, ids []int, body map[string]interface{}) {
+	if len(ids) == 0 {
+		if r.Method != http.MethodPost {
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+			return
+		}
+		if mockString(body["name"]) == "" || len(mockList(body["origins"])) == 0 {
+			c.fail(w, http.StatusBadRequest, "name and origins are required")
+			return
+		}
+		id := c.createOriginGroup(body)
+		mockWriteJSON(w, http.StatusCreated, c.originGroups[id])
+		return
+	}
+
+	group := c.originGroups[ids[0]]
+	if group == nil {
+		c.fail(w, http.StatusNotFound, "origin group %d not found", ids[0])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		mockWriteJSON(w, http.StatusOK, group)
+	case http.MethodPut:
+		c.applyOriginGroup(group, body)
+		mockWriteJSON(w, http.StatusOK, group)
+	case http.MethodDelete:
+		for _, res := range c.resources {
+			if mockInt(res["originGroup"]) == ids[0] {
+				c.fail(w, http.StatusBadRequest, "origin group %d is used by resource %d", ids[0], mockInt(res["id"]))
+				return
+			}
+		}
+		delete(c.originGroups, ids[0])
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+func (c *mockCDN) serveSSLData(w http.ResponseWriter, r *http.Request, ids []int, body map[string]interface{}) {
+	if len(ids) == 0 {
+		if r.Method != http.MethodPost {
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+			return
+		}
+		if !strings.Contains(mockString(body["sslCertificate"]), "BEGIN CERTIFICATE") ||
+			!strings.Contains(mockString(body["sslPrivateKey"]), "PRIVATE KEY") {
+			c.fail(w, http.StatusBadRequest, "invalid certificate or private key")
+			return
+		}
+		id := c.api.nextID()
+		cert := map[string]interface{}{
+			"id":                  id,
+			"name":                mockString(body["name"]),
+			"deleted":             false,
+			"cert_issuer":         "Mock CA",
+			"cert_subjeck_cn":     "example.com",
+			"validity_not_before": "2021-01-01T00:00:00Z",
+			"validity_not_after":  "2031-01-01T00:00:00Z",
+			"automated":           false,
+		}
+		c.sslData[id] = cert
+		mockWriteJSON(w, http.StatusCreated, c.renderSSLData(cert))
+		return
+	}
+
+	cert := c.sslData[ids[0]]
+	if cert == nil {
+		c.fail(w, http.StatusNotFound, "ssl certificate %d not found", ids[0])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		mockWriteJSON(w, http.StatusOK, c.renderSSLData(cert))
+	case http.MethodDelete:
+		for _, res := range c.resources {
+			if mockInt(res["sslData"]) == ids[0] {
+				c.fail(w, http.StatusBadRequest, "ssl certificate %d is used by resource %d", ids[0], mockInt(res["id"]))
+				return
+			}
+		}
+		delete(c.sslData, ids[0])
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+func (c *mockCDN) renderSSLData(cert map[string]interface{}) map[string]interface{} {
+	res := mockCopy(cert)
+	related := false
+	for _, r := range c.resources {
+		if mockInt(r["sslData"]) == mockInt(cert["id"]) {
+			related = true
+		}
+	}
+	res["hasRelatedResources"] = related
+	return res
+}
+
+// mockOptions returns a copy of the request options with disabled (null)
+// options dropped, the way the API echoes them back.
+func mockOptions(v interface{}) map[string]interface{} {
+	res := make(map[string]interface{})
+	for k, o := range mockMap(v) {
+		if o != nil {
+			res[k] = o
+		}
+	}
+	return res
+}
+
+func mockStrings(v interface{}) []interface{} {
+	return append([]interface{}{}, mockList(v)...)
+}
+
+func mockStringOr(v interface{}, def string) string {
+	if s := mockString(v); s != "" {
+		return s
+	}
+	return def
+}
+
+func mockSortedIntKeys(m map[int]map[string]interface{}) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// unitCDNCheckDestroy checks that the resources of the type are gone from
+// the objects get returns.
+func unitCDNCheckDestroy(resourceType string, get func(id int) map[string]interface{}) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			id, err := strconv.Atoi(rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+			if get(id) != nil {
+				return fmt.Errorf("cdn object %s still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
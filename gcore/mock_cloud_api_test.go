@@ -0,0 +1,3192 @@
+package gcore
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const (
+	mockProjectID   = 1
+	mockProjectName = "default"
+	mockRegionID    = 1
+	mockRegionName  = "Luxembourg"
+
+	mockExternalNetworkID = "ffffffff-0000-4000-8000-000000000001"
+	mockExternalSubnetID  = "ffffffff-0000-4000-8000-000000000002"
+
+	mockCloudTime    = "2021-01-01T00:00:00+0000"
+	mockCloudTimeNoZ = "2021-01-01T00:00:00"
+	mockCloudTimeZZ  = "2021-01-01T00:00:00Z"
+)
+
+// mockCloudScope sets the mock project and region of a cloud resource.
+var mockCloudScope = fmt.Sprintf("project_id = %d\n  region_id  = %d", mockProjectID, mockRegionID)
+
+// unitCloudImportID imports the resource by its ID in the mock project and
+// region.
+func unitCloudImportID(resourceName string) resource.ImportStateIdFunc {
+	return unitImportID(resourceName, func(s *terraform.InstanceState) string {
+		return fmt.Sprintf("%d:%d:%s", mockProjectID, mockRegionID, s.ID)
+	})
+}
+
+// unitCloudCheckDestroy checks that the resources of the type are gone from
+// the objects of the kind.
+func unitCloudCheckDestroy(api *mockAPI, resourceType, kind string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			if api.cloud.Get(kind, rs.Primary.ID) != nil {
+				return fmt.Errorf("%s %s still exists", kind, rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+// mockScope is the project and region a cloud request was made in.
+type mockScope struct {
+	project int
+	region  int
+}
+
+type mockCloudAction func(c *mockCloud, w http.ResponseWriter, r *http.Request, s mockScope, obj map[string]interface{}, rest []string)
+
+// mockCloudKind describes how one collection of the Cloud API behaves.
+// Kinds are keyed by the URL name the SDK uses, e.g. "volumes" or "lbpools".
+type mockCloudKind struct {
+	// created is the task created_resources key reported on create.
+	created string
+	// sync kinds are created and deleted without a task: POST returns the
+	// object and DELETE returns no content.
+	sync bool
+	// deleteTask makes a sync kind still deleted through a task.
+	deleteTask bool
+	// idField names the JSON field holding the object ID.
+	idField string
+	// store is the kind the created objects are kept in when it isn't the
+	// kind itself, e.g. baremetal servers are created through bminstances
+	// and served as instances.
+	store string
+	// metadata is the JSON field metadata is rendered to: "metadata" or
+	// "metadata_detailed" for a key/value/read_only list, "map" for a plain
+	// "metadata" object, empty when the kind has no metadata.
+	metadata string
+
+	create func(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error)
+	// update handles PATCH of the object and returns the response body.
+	// A nil update merges the request into the object and returns it.
+	update func(c *mockCloud, s mockScope, obj, body map[string]interface{}) (interface{}, int, error)
+	// inUse reports why the object can't be deleted, the DELETE fails with
+	// 409 when it returns an error.
+	inUse func(c *mockCloud, obj map[string]interface{}) error
+	// remove is called before the object is deleted from the store.
+	remove func(c *mockCloud, obj map[string]interface{})
+	// render adds computed fields to the response copy of an object.
+	render  func(c *mockCloud, obj, res map[string]interface{})
+	actions map[string]mockCloudAction
+}
+
+// mockCloud is the Cloud API part of mockAPI. Objects are stored as plain
+// JSON maps shaped like the API responses; keys starting with "_" hold
+// bookkeeping and are never sent to the client.
+type mockCloud struct {
+	api      *mockAPI
+	kinds    map[string]*mockCloudKind
+	objects  map[string]map[string]map[string]interface{}
+	tasks    map[string]map[string]interface{}
+	projects []map[string]interface{}
+	regions  []map[string]interface{}
+}
+
+func newMockCloud(api *mockAPI) *mockCloud {
+	c := &mockCloud{
+		api:     api,
+		kinds:   make(map[string]*mockCloudKind),
+		objects: make(map[string]map[string]map[string]interface{}),
+		tasks:   make(map[string]map[string]interface{}),
+		projects: []map[string]interface{}{
+			{
+				"id":          mockProjectID,
+				"client_id":   1,
+				"name":        mockProjectName,
+				"description": "",
+				"state":       "ACTIVE",
+				"task_id":     nil,
+				"created_at":  mockCloudTimeNoZ,
+			},
+		},
+		regions: []map[string]interface{}{
+			mockRegion(mockRegionID, mockRegionName),
+			mockRegion(2, "Frankfurt"),
+		},
+	}
+
+	c.register("volumes", &mockCloudKind{
+		created:  "volumes",
+		metadata: "metadata_detailed",
+		create:   mockCloudVolumeCreate,
+		actions: map[string]mockCloudAction{
+			"extend": mockCloudVolumeExtend,
+			"retype": mockCloudVolumeRetype,
+			"attach": mockCloudVolumeAttach,
+			"detach": mockCloudVolumeDetach,
+		},
+	})
+	c.register("networks", &mockCloudKind{
+		created:  "networks",
+		metadata: "metadata",
+		create:   mockCloudNetworkCreate,
+		remove:   mockCloudNetworkRemove,
+	})
+	c.register("subnets", &mockCloudKind{
+		created:  "subnets",
+		metadata: "metadata",
+		create:   mockCloudSubnetCreate,
+		update:   mockCloudSubnetUpdate,
+		remove:   mockCloudSubnetRemove,
+	})
+	c.register("instances", &mockCloudKind{
+		created:  "instances",
+		idField:  "instance_id",
+		metadata: "map",
+		create:   mockCloudInstanceCreate,
+		update:   mockCloudInstanceUpdate,
+		remove:   mockCloudInstanceRemove,
+		render:   mockCloudInstanceRender,
+		actions: map[string]mockCloudAction{
+			"interfaces":       mockCloudInstanceInterfaces,
+			"ports":            mockCloudInstancePorts,
+			"securitygroups":   mockCloudInstanceSecurityGroups,
+			"attach_interface": mockCloudInstanceAttachInterface,
+			"detach_interface": mockCloudInstanceDetachInterface,
+			"addsecuritygroup": mockCloudInstanceAddSecurityGroup,
+			"delsecuritygroup": mockCloudInstanceDelSecurityGroup,
+			"changeflavor":     mockCloudInstanceChangeFlavor,
+			"start":            mockCloudInstancePower("active", "ACTIVE"),
+			"stop":             mockCloudInstancePower("stopped", "SHUTOFF"),
+		},
+	})
+	c.register("securitygroups", &mockCloudKind{
+		sync:     true,
+		metadata: "metadata",
+		create:   mockCloudSecurityGroupCreate,
+		render:   mockCloudSecurityGroupRender,
+		actions: map[string]mockCloudAction{
+			"rules": mockCloudSecurityGroupAddRule,
+		},
+	})
+	c.register("loadbalancers", &mockCloudKind{
+		created:  "loadbalancers",
+		metadata: "metadata",
+		create:   mockCloudLoadBalancerCreate,
+		remove:   mockCloudLoadBalancerRemove,
+	})
+	c.register("lblisteners", &mockCloudKind{
+		created: "listeners",
+		create:  mockCloudListenerCreate,
+		remove:  mockCloudListenerRemove,
+	})
+	c.register("lbpools", &mockCloudKind{
+		created: "pools",
+		create:  mockCloudPoolCreate,
+		update:  mockCloudPoolUpdate,
+		remove:  mockCloudPoolRemove,
+		actions: map[string]mockCloudAction{
+			"member":        mockCloudPoolMember,
+			"healthmonitor": mockCloudPoolHealthMonitor,
+		},
+	})
+	c.register("secrets", &mockCloudKind{
+		created: "secrets",
+		create:  mockCloudSecretCreate,
+		inUse:   mockCloudSecretInUse,
+	})
+	c.register("reserved_fixed_ips", &mockCloudKind{
+		created: "ports",
+		idField: "port_id",
+		create:  mockCloudReservedFixedIPCreate,
+		actions: map[string]mockCloudAction{
+			"connected_devices": mockCloudReservedFixedIPConnectedDevices,
+			"available_devices": mockCloudReservedFixedIPAvailableDevices,
+		},
+	})
+	c.register("floatingips", &mockCloudKind{
+		created:  "floatingips",
+		metadata: "metadata",
+		create:   mockCloudFloatingIPCreate,
+		actions: map[string]mockCloudAction{
+			"assign":   mockCloudFloatingIPAssign,
+			"unassign": mockCloudFloatingIPUnassign,
+		},
+	})
+	c.register("routers", &mockCloudKind{
+		created: "routers",
+		create:  mockCloudRouterCreate,
+		update:  mockCloudRouterUpdate,
+		actions: map[string]mockCloudAction{
+			"attach": mockCloudRouterAttach,
+			"detach": mockCloudRouterDetach,
+		},
+	})
+	c.register("bminstances", &mockCloudKind{
+		created: "instances",
+		idField: "instance_id",
+		store:   "instances",
+		create:  mockCloudBmInstanceCreate,
+	})
+	c.register("snapshots", &mockCloudKind{
+		created: "snapshots",
+		create:  mockCloudSnapshotCreate,
+		actions: map[string]mockCloudAction{
+			"metadata": mockCloudSnapshotMetadata,
+		},
+	})
+	c.register("servergroups", &mockCloudKind{
+		sync:       true,
+		deleteTask: true,
+		idField:    "servergroup_id",
+		create:     mockCloudServerGroupCreate,
+	})
+	c.register("lifecycle_policy", &mockCloudKind{
+		sync:   true,
+		create: mockCloudLifecyclePolicyCreate,
+		actions: map[string]mockCloudAction{
+			"add_volumes_to_policy":      mockCloudLifecyclePolicyVolumes(true),
+			"remove_volumes_from_policy": mockCloudLifecyclePolicyVolumes(false),
+		},
+	})
+	c.register("ddos/profiles", &mockCloudKind{
+		created: "ddos_profiles",
+		create:  mockCloudDDoSProfileCreate,
+		update:  mockCloudDDoSProfileUpdate,
+		actions: map[string]mockCloudAction{
+			"action": mockCloudDDoSProfileActivate,
+		},
+	})
+	c.register("faas/namespaces", &mockCloudKind{
+		created: "faas_namespaces",
+		idField: "name",
+		create:  mockCloudFaaSNamespaceCreate,
+		update:  mockCloudFaaSNamespaceUpdate,
+		remove:  mockCloudFaaSNamespaceRemove,
+		render:  mockCloudFaaSNamespaceRender,
+		actions: map[string]mockCloudAction{
+			"functions": mockCloudFaaSFunctions,
+		},
+	})
+	c.objects["k8s_clusters"] = make(map[string]map[string]interface{})
+	c.objects["k8s_pools"] = make(map[string]map[string]interface{})
+	c.objects["faas_functions"] = make(map[string]map[string]interface{})
+	c.objects["keypairs"] = make(map[string]map[string]interface{})
+	c.objects["laas_topics"] = make(map[string]map[string]interface{})
+
+	return c
+}
+
+func mockRegion(id int, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                     id,
+		"display_name":           name,
+		"keystone_name":          fmt.Sprintf("%s-%d", name, id),
+		"state":                  "ACTIVE",
+		"task_id":                nil,
+		"endpoint_type":          "public",
+		"external_network_id":    mockExternalNetworkID,
+		"spice_proxy_url":        "https://spice.example.com",
+		"created_on":             mockCloudTimeNoZ,
+		"keystone_id":            id,
+		"has_baremetal":          true,
+		"available_volume_types": []string{"standard", "ssd_hiiops", "cold", "ultra"},
+		"keystone": map[string]interface{}{
+			"id":                           id,
+			"url":                          "https://keystone.example.com",
+			"state":                        "INITIALIZED",
+			"keystone_federated_domain_id": "",
+			"created_on":                   mockCloudTimeNoZ,
+			"admin_password":               "",
+		},
+	}
+}
+
+func (c *mockCloud) register(name string, kind *mockCloudKind) {
+	if kind.idField == "" {
+		kind.idField = "id"
+	}
+	if kind.store == "" {
+		kind.store = name
+	}
+	c.kinds[name] = kind
+	c.objects[name] = make(map[string]map[string]interface{})
+}
+
+// mockID returns the ID of an object as it appears in URLs.
+func mockID(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// Get returns a copy of the stored object, or nil when it doesn't exist.
+func (c *mockCloud) Get(kind, id string) map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+
+	obj := c.objects[kind][id]
+	if obj == nil {
+		return nil
+	}
+	return c.render(kind, obj)
+}
+
+// Metadata returns a copy of the metadata of the object, nil when there is
+// no such object.
+func (c *mockCloud) Metadata(kind, id string) map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+
+	obj := c.objects[kind][id]
+	if obj == nil {
+		return nil
+	}
+	return mockCopy(mockMap(obj["_metadata"]))
+}
+
+// Count returns the number of stored objects of the kind.
+func (c *mockCloud) Count(kind string) int {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	return len(c.objects[kind])
+}
+
+// Remove deletes a stored object like a DELETE request, without the in use
+// check, e.g. an object seeded for a test step.
+func (c *mockCloud) Remove(kind, id string) {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+
+	obj := c.objects[kind][id]
+	if obj == nil {
+		return
+	}
+	if k := c.kinds[kind]; k.remove != nil {
+		k.remove(c, obj)
+	}
+	delete(c.objects[kind], id)
+}
+
+// Seed creates an object through the same code path as a POST request and
+// returns its ID. It is used to prepare dependencies of the resource under
+// test.
+func (c *mockCloud) Seed(kind string, body map[string]interface{}) string {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+
+	k := c.kinds[kind]
+	obj, err := k.create(c, mockScope{project: mockProjectID, region: mockRegionID}, body)
+	if err != nil {
+		c.api.t.Fatalf("seed %s: %s", kind, err)
+	}
+	id := mockID(obj[k.idField])
+	c.objects[k.store][id] = obj
+	return id
+}
+
+func (c *mockCloud) fail(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	mockWriteJSON(w, status, map[string]interface{}{
+		"message":         msg,
+		"exception_class": http.StatusText(status),
+	})
+}
+
+func (c *mockCloud) page(items []interface{}) map[string]interface{} {
+	if items == nil {
+		items = []interface{}{}
+	}
+	return map[string]interface{}{"count": len(items), "results": items}
+}
+
+// task registers a finished task reporting the created resources.
+func (c *mockCloud) task(s mockScope, created map[string]interface{}) map[string]interface{} {
+	id := c.api.uuid()
+	var createdResources interface{}
+	if len(created) > 0 {
+		createdResources = created
+	}
+	c.tasks[id] = map[string]interface{}{
+		"id":                id,
+		"task_type":         "mock",
+		"project_id":        s.project,
+		"client_id":         1,
+		"region_id":         s.region,
+		"user_id":           1,
+		"user_client_id":    1,
+		"state":             "FINISHED",
+		"created_on":        mockCloudTimeNoZ,
+		"finished_on":       mockCloudTimeNoZ,
+		"created_resources": createdResources,
+		"error":             nil,
+	}
+	return map[string]interface{}{"tasks": []string{id}}
+}
+
+// SeedTask registers a task in the given state and returns its ID, errorText
+// is reported as the task error when it isn't empty.
+func (c *mockCloud) SeedTask(state, errorText string) string {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+
+	id := c.task(mockScope{project: mockProjectID, region: mockRegionID}, nil)["tasks"].([]string)[0]
+	c.tasks[id]["state"] = state
+	if state != "FINISHED" {
+		c.tasks[id]["finished_on"] = nil
+	}
+	if errorText != "" {
+		c.tasks[id]["error"] = errorText
+	}
+	return id
+}
+
+func (c *mockCloud) render(kind string, obj map[string]interface{}) map[string]interface{} {
+	k := c.kinds[kind]
+	res := mockCopy(obj)
+	md := mockMap(obj["_metadata"])
+	switch k.metadata {
+	case "map":
+		m := make(map[string]interface{}, len(md))
+		for key, v := range md {
+			m[key] = v
+		}
+		res["metadata"] = m
+	case "":
+	default:
+		res[k.metadata] = mockMetadataList(md)
+	}
+	if k.render != nil {
+		k.render(c, obj, res)
+	}
+	return res
+}
+
+func mockMetadataList(md map[string]interface{}) []interface{} {
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	res := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, map[string]interface{}{"key": k, "value": md[k], "read_only": false})
+	}
+	return res
+}
+
+func (c *mockCloud) base(s mockScope, id string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         id,
+		"project_id": s.project,
+		"region_id":  s.region,
+		"region":     c.regionName(s.region),
+		"created_at": mockCloudTime,
+		"_metadata":  map[string]interface{}{},
+	}
+}
+
+func (c *mockCloud) regionName(id int) string {
+	for _, r := range c.regions {
+		if mockInt(r["id"]) == id {
+			return mockString(r["display_name"])
+		}
+	}
+	return ""
+}
+
+func (c *mockCloud) serve(w http.ResponseWriter, r *http.Request) {
+	segs := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/cloud/"), "/"), "/")
+	if len(segs) < 2 {
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+		return
+	}
+
+	name := segs[1]
+	// Kinds like ddos/profiles span two segments before the scope.
+	if len(segs) > 2 && c.kinds[name+"/"+segs[2]] != nil {
+		name += "/" + segs[2]
+		segs = append([]string{segs[0], name}, segs[3:]...)
+	}
+	switch name {
+	case "projects":
+		c.serveList(w, r, segs[2:], c.projects)
+		return
+	case "regions":
+		c.serveList(w, r, segs[2:], c.regions)
+		return
+	case "k8s":
+		c.serveK8s(w, r, segs[2:])
+		return
+	case "securitygrouprules":
+		c.serveSecurityGroupRules(w, r, segs[2:])
+		return
+	case "keypairs":
+		c.serveKeypairs(w, r, segs[2:])
+		return
+	case "laas":
+		c.serveLaaS(w, r, segs[2:])
+		return
+	case "tasks":
+		if len(segs) != 3 || r.Method != http.MethodGet {
+			c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+			return
+		}
+		t, ok := c.tasks[segs[2]]
+		if !ok {
+			c.fail(w, http.StatusNotFound, "task %s not found", segs[2])
+			return
+		}
+		mockWriteJSON(w, http.StatusOK, t)
+		return
+	}
+
+	kind, ok := c.kinds[name]
+	if !ok || len(segs) < 4 {
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+		return
+	}
+	project, _ := strconv.Atoi(segs[2])
+	region, _ := strconv.Atoi(segs[3])
+	s := mockScope{project: project, region: region}
+	rest := segs[4:]
+
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			items := make([]interface{}, 0)
+			for _, id := range mockSortedKeys(c.objects[name]) {
+				obj := c.objects[name][id]
+				if mockInt(obj["project_id"]) == project && mockInt(obj["region_id"]) == region {
+					items = append(items, c.render(name, obj))
+				}
+			}
+			mockWriteJSON(w, http.StatusOK, c.page(items))
+		case http.MethodPost:
+			body, err := mockReadJSON(r)
+			if err != nil {
+				c.fail(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			obj, err := kind.create(c, s, body)
+			if err != nil {
+				c.fail(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			id := mockID(obj[kind.idField])
+			c.objects[kind.store][id] = obj
+			if kind.sync {
+				mockWriteJSON(w, http.StatusCreated, c.render(name, obj))
+				return
+			}
+			mockWriteJSON(w, http.StatusOK, c.task(s, map[string]interface{}{kind.created: []interface{}{obj[kind.idField]}}))
+		default:
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+		return
+	}
+
+	id := rest[0]
+	obj, ok := c.objects[name][id]
+	if !ok {
+		c.fail(w, http.StatusNotFound, "%s %s not found", name, id)
+		return
+	}
+
+	if len(rest) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			mockWriteJSON(w, http.StatusOK, c.render(name, obj))
+		case http.MethodPatch, http.MethodPut:
+			body, err := mockReadJSON(r)
+			if err != nil {
+				c.fail(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if kind.update == nil {
+				for k, v := range body {
+					obj[k] = v
+				}
+				mockWriteJSON(w, http.StatusOK, c.render(name, obj))
+				return
+			}
+			res, status, err := kind.update(c, s, obj, body)
+			if err != nil {
+				c.fail(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			mockWriteJSON(w, status, res)
+		case http.MethodDelete:
+			if kind.inUse != nil {
+				if err := kind.inUse(c, obj); err != nil {
+					c.fail(w, http.StatusConflict, err.Error())
+					return
+				}
+			}
+			if kind.remove != nil {
+				kind.remove(c, obj)
+			}
+			delete(c.objects[name], id)
+			if kind.sync && !kind.deleteTask {
+				mockWriteJSON(w, http.StatusNoContent, nil)
+				return
+			}
+			mockWriteJSON(w, http.StatusOK, c.task(s, nil))
+		default:
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+		return
+	}
+
+	action := rest[1]
+	if (action == "metadata" || action == "metadata_item") && kind.metadata != "" {
+		c.serveMetadata(w, r, obj, rest[1:])
+		return
+	}
+	if fn, ok := kind.actions[action]; ok {
+		fn(c, w, r, s, obj, rest[2:])
+		return
+	}
+	c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+}
+
+func (c *mockCloud) serveList(w http.ResponseWriter, r *http.Request, rest []string, items []map[string]interface{}) {
+	if r.Method != http.MethodGet {
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+	if len(rest) == 0 {
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			list[i] = item
+		}
+		mockWriteJSON(w, http.StatusOK, c.page(list))
+		return
+	}
+	for _, item := range items {
+		if strconv.Itoa(mockInt(item["id"])) == rest[0] {
+			mockWriteJSON(w, http.StatusOK, item)
+			return
+		}
+	}
+	c.fail(w, http.StatusNotFound, "%s not found", rest[0])
+}
+
+// serveMetadata handles both the generic metadata API (metadata,
+// metadata_item?key=) and the instance flavour of it (metadata/{key}).
+func (c *mockCloud) serveMetadata(w http.ResponseWriter, r *http.Request, obj map[string]interface{}, rest []string) {
+	md := mockMap(obj["_metadata"])
+	if md == nil {
+		md = make(map[string]interface{})
+		obj["_metadata"] = md
+	}
+
+	key := r.URL.Query().Get("key")
+	if len(rest) > 1 {
+		key = rest[1]
+	}
+	if rest[0] == "metadata_item" || key != "" {
+		v, ok := md[key]
+		switch r.Method {
+		case http.MethodGet:
+			if !ok {
+				c.fail(w, http.StatusNotFound, "metadata key %s not found", key)
+				return
+			}
+			mockWriteJSON(w, http.StatusOK, map[string]interface{}{"key": key, "value": v, "read_only": false})
+		case http.MethodDelete:
+			if !ok {
+				c.fail(w, http.StatusNotFound, "metadata key %s not found", key)
+				return
+			}
+			delete(md, key)
+			mockWriteJSON(w, http.StatusNoContent, nil)
+		default:
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		mockWriteJSON(w, http.StatusOK, c.page(mockMetadataList(md)))
+	case http.MethodPost, http.MethodPut:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if r.Method == http.MethodPut {
+			md = make(map[string]interface{})
+			obj["_metadata"] = md
+		}
+		for k, v := range body {
+			md[k] = v
+		}
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+func mockMetadataFromBody(v interface{}) map[string]interface{} {
+	md := make(map[string]interface{})
+	for k, val := range mockMap(v) {
+		md[k] = val
+	}
+	return md
+}
+
+// volumes
+
+func mockCloudVolumeCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	name := mockString(body["name"])
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	size := mockInt(body["size"])
+	if size == 0 {
+		size = 10
+	}
+	typeName := mockString(body["type_name"])
+	if typeName == "" {
+		typeName = "standard"
+	}
+	source := mockString(body["source"])
+
+	obj := c.base(s, c.api.uuid())
+	obj["name"] = name
+	obj["size"] = size
+	obj["volume_type"] = typeName
+	obj["status"] = "available"
+	obj["bootable"] = source == "image"
+	obj["snapshot_id"] = mockString(body["snapshot_id"])
+	obj["attachments"] = []interface{}{}
+	obj["volume_image_metadata"] = map[string]interface{}{"image_id": mockString(body["image_id"])}
+	obj["_metadata"] = mockMetadataFromBody(body["metadata"])
+	return obj, nil
+}
+
+func mockCloudVolumeExtend(c *mockCloud, w http.ResponseWriter, r *http.Request, s mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	size := mockInt(body["size"])
+	if size <= mockInt(obj["size"]) {
+		c.fail(w, http.StatusBadRequest, "new size %d must be greater than %d", size, mockInt(obj["size"]))
+		return
+	}
+	obj["size"] = size
+	mockWriteJSON(w, http.StatusOK, c.task(s, map[string]interface{}{"volumes": []string{mockString(obj["id"])}}))
+}
+
+func mockCloudVolumeRetype(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	obj["volume_type"] = mockString(body["volume_type"])
+	mockWriteJSON(w, http.StatusOK, c.render("volumes", obj))
+}
+
+func mockCloudVolumeAttach(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	instanceID := mockString(body["instance_id"])
+	inst := c.objects["instances"][instanceID]
+	if inst == nil {
+		c.fail(w, http.StatusNotFound, "instance %s not found", instanceID)
+		return
+	}
+	mockAttachVolume(obj, inst, false)
+	mockWriteJSON(w, http.StatusOK, c.render("volumes", obj))
+}
+
+func mockCloudVolumeDetach(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	instanceID := mockString(body["instance_id"])
+	if inst := c.objects["instances"][instanceID]; inst != nil {
+		mockDetachVolume(obj, inst)
+	}
+	mockWriteJSON(w, http.StatusOK, c.render("volumes", obj))
+}
+
+func mockAttachVolume(vol, inst map[string]interface{}, deleteOnTermination bool) {
+	vol["status"] = "in-use"
+	vol["attachments"] = append(mockList(vol["attachments"]), map[string]interface{}{
+		"server_id":     inst["instance_id"],
+		"attachment_id": vol["id"],
+		"instance_name": inst["instance_name"],
+		"volume_id":     vol["id"],
+		"device":        "/dev/vdb",
+	})
+	inst["volumes"] = append(mockList(inst["volumes"]), map[string]interface{}{
+		"id":                    vol["id"],
+		"delete_on_termination": deleteOnTermination,
+	})
+}
+
+func mockDetachVolume(vol, inst map[string]interface{}) {
+	var attachments []interface{}
+	for _, a := range mockList(vol["attachments"]) {
+		if mockMap(a)["server_id"] != inst["instance_id"] {
+			attachments = append(attachments, a)
+		}
+	}
+	vol["attachments"] = append([]interface{}{}, attachments...)
+	if len(attachments) == 0 {
+		vol["status"] = "available"
+	}
+	var vols []interface{}
+	for _, v := range mockList(inst["volumes"]) {
+		if mockMap(v)["id"] != vol["id"] {
+			vols = append(vols, v)
+		}
+	}
+	inst["volumes"] = append([]interface{}{}, vols...)
+}
+
+// networks
+
+func mockCloudNetworkCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	name := mockString(body["name"])
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	typ := mockString(body["type"])
+	if typ == "" {
+		typ = "vxlan"
+	}
+	mtu := mockInt(body["mtu"])
+	if mtu == 0 {
+		mtu = 1450
+	}
+
+	obj := c.base(s, c.api.uuid())
+	obj["name"] = name
+	obj["type"] = typ
+	obj["mtu"] = mtu
+	obj["external"] = false
+	obj["default"] = false
+	obj["shared"] = false
+	obj["subnets"] = []interface{}{}
+	obj["task_id"] = nil
+	obj["_metadata"] = mockMetadataFromBody(body["metadata"])
+	return obj, nil
+}
+
+func mockCloudNetworkRemove(c *mockCloud, obj map[string]interface{}) {
+	for _, id := range mockList(obj["subnets"]) {
+		delete(c.objects["subnets"], mockString(id))
+	}
+}
+
+// subnets
+
+func mockCloudSubnetCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	name := mockString(body["name"])
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	networkID := mockString(body["network_id"])
+	network := c.objects["networks"][networkID]
+	if network == nil {
+		return nil, fmt.Errorf("network %s not found", networkID)
+	}
+	cidr := mockString(body["cidr"])
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cidr %q", cidr)
+	}
+
+	gateway := body["gateway_ip"]
+	connect := mockBool(body["connect_to_network_router"])
+	if gateway == nil && connect {
+		gateway = mockNthIP(ip.Mask(ipNet.Mask), 1)
+	}
+
+	ones, bits := ipNet.Mask.Size()
+	total := 1<<uint(bits-ones) - 2
+
+	obj := c.base(s, c.api.uuid())
+	obj["name"] = name
+	obj["ip_version"] = 4
+	obj["enable_dhcp"] = mockBool(body["enable_dhcp"])
+	obj["cidr"] = ipNet.String()
+	obj["network_id"] = networkID
+	obj["updated_at"] = mockCloudTime
+	obj["available_ips"] = total
+	obj["total_ips"] = total
+	obj["has_router"] = connect && gateway != nil
+	obj["dns_nameservers"] = append([]interface{}{}, mockList(body["dns_nameservers"])...)
+	obj["host_routes"] = append([]interface{}{}, mockList(body["host_routes"])...)
+	obj["gateway_ip"] = gateway
+	obj["_metadata"] = mockMetadataFromBody(body["metadata"])
+	obj["_next_ip"] = 2
+
+	network["subnets"] = append(mockList(network["subnets"]), obj["id"])
+	return obj, nil
+}
+
+func mockCloudSubnetUpdate(c *mockCloud, _ mockScope, obj, body map[string]interface{}) (interface{}, int, error) {
+	if name := mockString(body["name"]); name != "" {
+		obj["name"] = name
+	}
+	if v, ok := body["enable_dhcp"]; ok {
+		obj["enable_dhcp"] = mockBool(v)
+	}
+	if v, ok := body["dns_nameservers"]; ok {
+		obj["dns_nameservers"] = mockStrings(v)
+	}
+	if v, ok := body["host_routes"]; ok {
+		obj["host_routes"] = mockStrings(v)
+	}
+	// A null gateway_ip leaves the gateway as it is.
+	if v := body["gateway_ip"]; v != nil {
+		if net.ParseIP(mockString(v)) == nil {
+			return nil, 0, fmt.Errorf("invalid gateway_ip %v", v)
+		}
+		obj["gateway_ip"] = v
+	}
+	return c.render("subnets", obj), http.StatusOK, nil
+}
+
+func mockCloudSubnetRemove(c *mockCloud, obj map[string]interface{}) {
+	network := c.objects["networks"][mockString(obj["network_id"])]
+	if network == nil {
+		return
+	}
+	subnets := make([]interface{}, 0)
+	for _, id := range mockList(network["subnets"]) {
+		if id != obj["id"] {
+			subnets = append(subnets, id)
+		}
+	}
+	network["subnets"] = subnets
+}
+
+func mockNthIP(base net.IP, n int) string {
+	ip := make(net.IP, len(base.To4()))
+	copy(ip, base.To4())
+	for i := len(ip) - 1; i >= 0 && n > 0; i-- {
+		sum := int(ip[i]) + n
+		ip[i] = byte(sum % 256)
+		n = sum / 256
+	}
+	return ip.String()
+}
+
+// allocateIP returns the next free address of the subnet.
+func (c *mockCloud) allocateIP(subnet map[string]interface{}) string {
+	_, ipNet, _ := net.ParseCIDR(mockString(subnet["cidr"]))
+	n := mockInt(subnet["_next_ip"])
+	subnet["_next_ip"] = n + 1
+	subnet["available_ips"] = mockInt(subnet["available_ips"]) - 1
+	return mockNthIP(ipNet.IP, n)
+}
+
+// instances
+
+func mockFlavor(id string) map[string]interface{} {
+	return map[string]interface{}{
+		"flavor_id":   id,
+		"flavor_name": id,
+		"ram":         2048,
+		"vcpus":       1,
+	}
+}
+
+func mockCloudInstanceCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	var name string
+	if names := mockList(body["names"]); len(names) > 0 {
+		name = mockString(names[0])
+	} else if templates := mockList(body["name_templates"]); len(templates) > 0 {
+		name = mockString(templates[0])
+	}
+	if name == "" {
+		return nil, fmt.Errorf("names or name_templates is required")
+	}
+	flavor := mockString(body["flavor"])
+	if flavor == "" {
+		return nil, fmt.Errorf("flavor is required")
+	}
+	if len(mockList(body["interfaces"])) == 0 {
+		return nil, fmt.Errorf("at least one interface is required")
+	}
+
+	id := c.api.uuid()
+	obj := map[string]interface{}{
+		"instance_id":          id,
+		"instance_name":        name,
+		"instance_description": "",
+		"instance_created":     mockCloudTimeZZ,
+		"status":               "ACTIVE",
+		"vm_state":             "active",
+		"task_state":           nil,
+		"flavor":               mockFlavor(flavor),
+		"volumes":              []interface{}{},
+		"project_id":           s.project,
+		"region_id":            s.region,
+		"region":               c.regionName(s.region),
+		"availability_zone":    "nova",
+		"keypair_name":         mockString(body["keypair_name"]),
+		"_metadata":            mockMetadataFromBody(body["metadata"]),
+		"_interfaces":          []interface{}{},
+	}
+
+	for _, raw := range mockList(body["volumes"]) {
+		v := mockMap(raw)
+		if mockString(v["source"]) == "existing-volume" {
+			vol := c.objects["volumes"][mockString(v["volume_id"])]
+			if vol == nil {
+				return nil, fmt.Errorf("volume %s not found", mockString(v["volume_id"]))
+			}
+			mockAttachVolume(vol, obj, false)
+			continue
+		}
+		volName := mockString(v["name"])
+		if volName == "" {
+			volName = fmt.Sprintf("%s-volume-%d", name, len(mockList(obj["volumes"])))
+		}
+		vol, err := mockCloudVolumeCreate(c, s, map[string]interface{}{
+			"name":      volName,
+			"size":      v["size"],
+			"type_name": v["type_name"],
+			"source":    v["source"],
+			"image_id":  v["image_id"],
+		})
+		if err != nil {
+			return nil, err
+		}
+		vol["_owner"] = id
+		c.objects["volumes"][mockString(vol["id"])] = vol
+		mockAttachVolume(vol, obj, true)
+	}
+
+	var instanceSGs []interface{}
+	for _, sg := range mockList(body["security_groups"]) {
+		instanceSGs = append(instanceSGs, mockString(mockMap(sg)["id"]))
+	}
+	for _, raw := range mockList(body["interfaces"]) {
+		if err := c.attachInterface(s, obj, mockMap(raw), instanceSGs); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+// attachInterface resolves an interface create request the way the API
+// does and adds the resulting port to the instance.
+func (c *mockCloud) attachInterface(s mockScope, inst, spec map[string]interface{}, defaultSGs []interface{}) error {
+	typ := mockString(spec["type"])
+	portID := mockString(spec["port_id"])
+	ipAddress := mockString(spec["ip_address"])
+
+	var networkID, subnetID string
+	switch typ {
+	case "subnet":
+		subnetID = mockString(spec["subnet_id"])
+		subnet := c.objects["subnets"][subnetID]
+		if subnet == nil {
+			return fmt.Errorf("subnet %s not found", subnetID)
+		}
+		networkID = mockString(subnet["network_id"])
+		if ipAddress == "" {
+			ipAddress = c.allocateIP(subnet)
+		}
+	case "any_subnet":
+		networkID = mockString(spec["network_id"])
+		network := c.objects["networks"][networkID]
+		if network == nil {
+			return fmt.Errorf("network %s not found", networkID)
+		}
+		subnets := mockList(network["subnets"])
+		if len(subnets) == 0 {
+			return fmt.Errorf("network %s has no subnets", networkID)
+		}
+		subnetID = mockString(subnets[0])
+		if ipAddress == "" {
+			ipAddress = c.allocateIP(c.objects["subnets"][subnetID])
+		}
+	case "external", "":
+		typ = "external"
+		networkID = mockExternalNetworkID
+		subnetID = mockExternalSubnetID
+		ipAddress = mockNthIP(net.ParseIP("92.38.0.0"), c.api.nextID())
+	case "reserved_fixed_ip":
+		port := c.objects["reserved_fixed_ips"][portID]
+		if port == nil {
+			return fmt.Errorf("reserved fixed ip port %s not found", portID)
+		}
+		networkID = mockString(port["network_id"])
+		subnetID = mockString(port["subnet_id"])
+		ipAddress = mockString(port["fixed_ip_address"])
+	default:
+		return fmt.Errorf("unknown interface type %q", typ)
+	}
+	if portID == "" {
+		portID = c.api.uuid()
+	}
+
+	sgIDs := mockList(spec["security_groups"])
+	if len(sgIDs) == 0 {
+		for _, id := range defaultSGs {
+			sgIDs = append(sgIDs, map[string]interface{}{"id": id})
+		}
+	}
+	sgs := make([]interface{}, 0, len(sgIDs))
+	for _, raw := range sgIDs {
+		sgs = append(sgs, c.securityGroupRef(mockString(mockMap(raw)["id"]), ""))
+	}
+
+	networkName := "external"
+	if n := c.objects["networks"][networkID]; n != nil {
+		networkName = mockString(n["name"])
+	}
+
+	if fip := mockMap(spec["floating_ip"]); fip != nil {
+		fipID := mockString(fip["existing_floating_id"])
+		obj := c.objects["floatingips"][fipID]
+		if obj == nil && fipID != "" {
+			return fmt.Errorf("floating ip %s not found", fipID)
+		}
+		if obj == nil {
+			obj, _ = mockCloudFloatingIPCreate(c, s, map[string]interface{}{})
+			c.objects["floatingips"][mockString(obj["id"])] = obj
+		}
+		mockAssignFloatingIP(obj, map[string]interface{}{"port_id": portID, "fixed_ip_address": ipAddress})
+	}
+
+	n := c.api.nextID()
+	iface := map[string]interface{}{
+		"port_id":               portID,
+		"mac_address":           fmt.Sprintf("fa:16:3e:00:%02x:%02x", (n>>8)&0xff, n&0xff),
+		"network_id":            networkID,
+		"port_security_enabled": true,
+		"ip_assignments": []interface{}{
+			map[string]interface{}{"ip_address": ipAddress, "subnet_id": subnetID},
+		},
+		"network_details": map[string]interface{}{
+			"id":         networkID,
+			"name":       networkName,
+			"mtu":        1450,
+			"external":   typ == "external",
+			"default":    false,
+			"shared":     typ == "external",
+			"subnets":    []interface{}{},
+			"project_id": s.project,
+			"region_id":  s.region,
+			"region":     c.regionName(s.region),
+			"created_at": mockCloudTime,
+		},
+		"sub_ports":        []interface{}{},
+		"_type":            typ,
+		"_security_groups": sgs,
+	}
+	inst["_interfaces"] = append(mockList(inst["_interfaces"]), iface)
+	return nil
+}
+
+// securityGroupRef returns the {id, name} reference of a security group,
+// resolving either side from the store when it's known.
+func (c *mockCloud) securityGroupRef(id, name string) map[string]interface{} {
+	for sgID, sg := range c.objects["securitygroups"] {
+		if sgID == id || (name != "" && mockString(sg["name"]) == name) {
+			return map[string]interface{}{"id": sgID, "name": sg["name"]}
+		}
+	}
+	if id == "" {
+		id = "sg-" + name
+	}
+	if name == "" {
+		name = id
+	}
+	return map[string]interface{}{"id": id, "name": name}
+}
+
+func mockCloudInstanceRender(c *mockCloud, obj, res map[string]interface{}) {
+	addresses := make(map[string]interface{})
+	sgNames := make(map[string]bool)
+	for _, raw := range mockList(obj["_interfaces"]) {
+		iface := mockMap(raw)
+		netName := mockString(mockMap(iface["network_details"])["name"])
+		list := mockList(addresses[netName])
+		for _, a := range mockList(iface["ip_assignments"]) {
+			list = append(list, map[string]interface{}{
+				"type":      "fixed",
+				"addr":      mockMap(a)["ip_address"],
+				"subnet_id": mockMap(a)["subnet_id"],
+			})
+		}
+		for _, f := range c.portFloatingIPs(mockString(iface["port_id"])) {
+			list = append(list, map[string]interface{}{
+				"type": "floating",
+				"addr": mockMap(f)["floating_ip_address"],
+			})
+		}
+		addresses[netName] = list
+		for _, sg := range mockList(iface["_security_groups"]) {
+			sgNames[mockString(mockMap(sg)["name"])] = true
+		}
+	}
+	names := make([]string, 0, len(sgNames))
+	for n := range sgNames {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	sgs := make([]interface{}, 0, len(names))
+	for _, n := range names {
+		sgs = append(sgs, map[string]interface{}{"name": n})
+	}
+	res["addresses"] = addresses
+	res["security_groups"] = sgs
+}
+
+func mockCloudInstanceUpdate(c *mockCloud, _ mockScope, obj, body map[string]interface{}) (interface{}, int, error) {
+	if name := mockString(body["name"]); name != "" {
+		obj["instance_name"] = name
+	}
+	return c.render("instances", obj), http.StatusOK, nil
+}
+
+func mockCloudInstanceRemove(c *mockCloud, obj map[string]interface{}) {
+	for _, raw := range mockList(obj["volumes"]) {
+		v := mockMap(raw)
+		vol := c.objects["volumes"][mockString(v["id"])]
+		if vol == nil {
+			continue
+		}
+		if mockBool(v["delete_on_termination"]) {
+			delete(c.objects["volumes"], mockString(v["id"]))
+			continue
+		}
+		mockDetachVolume(vol, obj)
+	}
+}
+
+func mockCloudInstanceInterfaces(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	items := make([]interface{}, 0)
+	for _, raw := range mockList(obj["_interfaces"]) {
+		iface := mockCopy(mockMap(raw))
+		iface["floatingip_details"] = c.portFloatingIPs(mockString(iface["port_id"]))
+		items = append(items, iface)
+	}
+	mockWriteJSON(w, http.StatusOK, c.page(items))
+}
+
+// portFloatingIPs returns the floating IPs currently assigned to a port, the
+// way the interface list of an instance reports them.
+func (c *mockCloud) portFloatingIPs(portID string) []interface{} {
+	ids := make([]string, 0)
+	for id, fip := range c.objects["floatingips"] {
+		if portID != "" && mockString(fip["port_id"]) == portID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	items := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, c.render("floatingips", c.objects["floatingips"][id]))
+	}
+	return items
+}
+
+func mockCloudInstancePorts(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	items := make([]interface{}, 0)
+	for i, raw := range mockList(obj["_interfaces"]) {
+		iface := mockMap(raw)
+		items = append(items, map[string]interface{}{
+			"id":              iface["port_id"],
+			"name":            fmt.Sprintf("%s-port-%d", mockString(obj["instance_name"]), i),
+			"security_groups": mockList(iface["_security_groups"]),
+		})
+	}
+	mockWriteJSON(w, http.StatusOK, c.page(items))
+}
+
+func mockCloudInstanceSecurityGroups(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	seen := make(map[string]bool)
+	items := make([]interface{}, 0)
+	for _, raw := range mockList(obj["_interfaces"]) {
+		for _, sg := range mockList(mockMap(raw)["_security_groups"]) {
+			id := mockString(mockMap(sg)["id"])
+			if !seen[id] {
+				seen[id] = true
+				items = append(items, sg)
+			}
+		}
+	}
+	mockWriteJSON(w, http.StatusOK, c.page(items))
+}
+
+func mockCloudInstanceAttachInterface(c *mockCloud, w http.ResponseWriter, r *http.Request, s mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := c.attachInterface(s, obj, body, nil); err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ifaces := mockList(obj["_interfaces"])
+	portID := mockMap(ifaces[len(ifaces)-1])["port_id"]
+	mockWriteJSON(w, http.StatusOK, c.task(s, map[string]interface{}{"ports": []interface{}{portID}}))
+}
+
+func mockCloudInstanceDetachInterface(c *mockCloud, w http.ResponseWriter, r *http.Request, s mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	portID := mockString(body["port_id"])
+	ipAddress := mockString(body["ip_address"])
+
+	ifaces := make([]interface{}, 0)
+	found := false
+	for _, raw := range mockList(obj["_interfaces"]) {
+		iface := mockMap(raw)
+		if !found && mockString(iface["port_id"]) == portID {
+			match := ipAddress == ""
+			for _, a := range mockList(iface["ip_assignments"]) {
+				if mockString(mockMap(a)["ip_address"]) == ipAddress {
+					match = true
+				}
+			}
+			if match {
+				found = true
+				continue
+			}
+		}
+		ifaces = append(ifaces, iface)
+	}
+	if !found {
+		c.fail(w, http.StatusNotFound, "port %s with ip %s is not attached", portID, ipAddress)
+		return
+	}
+	obj["_interfaces"] = ifaces
+	mockWriteJSON(w, http.StatusOK, c.task(s, nil))
+}
+
+// mockInstanceSecurityGroupTargets returns the security group names of an
+// add/del security group request by port ID. An empty port ID stands for
+// every port of the instance.
+func mockInstanceSecurityGroupTargets(body map[string]interface{}) map[string][]string {
+	targets := make(map[string][]string)
+	if name := mockString(body["name"]); name != "" {
+		targets[""] = append(targets[""], name)
+	}
+	for _, raw := range mockList(body["ports_security_group_names"]) {
+		p := mockMap(raw)
+		portID := mockString(p["port_id"])
+		for _, name := range mockList(p["security_group_names"]) {
+			targets[portID] = append(targets[portID], mockString(name))
+		}
+	}
+	return targets
+}
+
+func mockCloudInstanceAddSecurityGroup(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for portID, names := range mockInstanceSecurityGroupTargets(body) {
+		for _, name := range names {
+			ref := c.securityGroupRef("", name)
+			for _, raw := range mockList(obj["_interfaces"]) {
+				iface := mockMap(raw)
+				if portID != "" && mockString(iface["port_id"]) != portID {
+					continue
+				}
+				exists := false
+				for _, sg := range mockList(iface["_security_groups"]) {
+					if mockMap(sg)["id"] == ref["id"] {
+						exists = true
+					}
+				}
+				if !exists {
+					iface["_security_groups"] = append(mockList(iface["_security_groups"]), ref)
+				}
+			}
+		}
+	}
+	mockWriteJSON(w, http.StatusNoContent, nil)
+}
+
+func mockCloudInstanceDelSecurityGroup(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for portID, names := range mockInstanceSecurityGroupTargets(body) {
+		remove := make(map[string]bool, len(names))
+		for _, name := range names {
+			remove[name] = true
+		}
+		for _, raw := range mockList(obj["_interfaces"]) {
+			iface := mockMap(raw)
+			if portID != "" && mockString(iface["port_id"]) != portID {
+				continue
+			}
+			sgs := make([]interface{}, 0)
+			for _, sg := range mockList(iface["_security_groups"]) {
+				if !remove[mockString(mockMap(sg)["name"])] {
+					sgs = append(sgs, sg)
+				}
+			}
+			iface["_security_groups"] = sgs
+		}
+	}
+	mockWriteJSON(w, http.StatusNoContent, nil)
+}
+
+func mockCloudInstanceChangeFlavor(c *mockCloud, w http.ResponseWriter, r *http.Request, s mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	obj["flavor"] = mockFlavor(mockString(body["flavor_id"]))
+	mockWriteJSON(w, http.StatusOK, c.task(s, map[string]interface{}{"instances": []interface{}{obj["instance_id"]}}))
+}
+
+func mockCloudInstancePower(vmState, status string) mockCloudAction {
+	return func(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+		obj["vm_state"] = vmState
+		obj["status"] = status
+		mockWriteJSON(w, http.StatusOK, c.render("instances", obj))
+	}
+}
+
+// load balancers
+
+func mockCloudLoadBalancerCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	name := mockString(body["name"])
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	flavor := mockString(body["flavor"])
+	if flavor == "" {
+		flavor = "lb1-1-2"
+	}
+
+	vip := mockNthIP(net.ParseIP("5.5.0.0"), c.api.nextID())
+	if subnet := c.objects["subnets"][mockString(body["vip_subnet_id"])]; subnet != nil {
+		vip = c.allocateIP(subnet)
+	} else if network := c.objects["networks"][mockString(body["vip_network_id"])]; network != nil {
+		if subnets := mockList(network["subnets"]); len(subnets) > 0 {
+			vip = c.allocateIP(c.objects["subnets"][mockString(subnets[0])])
+		}
+	}
+
+	obj := c.base(s, c.api.uuid())
+	obj["name"] = name
+	obj["provisioning_status"] = "ACTIVE"
+	obj["operating_status"] = "ONLINE"
+	obj["vip_address"] = vip
+	obj["vip_port_id"] = c.api.uuid()
+	obj["listeners"] = []interface{}{}
+	obj["tags"] = []interface{}{}
+	obj["flavor"] = map[string]interface{}{
+		"flavor_id":   flavor,
+		"flavor_name": flavor,
+		"ram":         2048,
+		"vcpus":       1,
+	}
+	obj["_metadata"] = mockMetadataFromBody(body["metadata"])
+
+	// The listeners of the request are created together with the balancer.
+	c.objects["loadbalancers"][mockString(obj["id"])] = obj
+	for _, raw := range mockList(body["listeners"]) {
+		spec := mockCopy(mockMap(raw))
+		spec["loadbalancer_id"] = obj["id"]
+		l, err := mockCloudListenerCreate(c, s, spec)
+		if err != nil {
+			delete(c.objects["loadbalancers"], mockString(obj["id"]))
+			return nil, err
+		}
+		c.objects["lblisteners"][mockString(l["id"])] = l
+	}
+	return obj, nil
+}
+
+func mockCloudLoadBalancerRemove(c *mockCloud, obj map[string]interface{}) {
+	for id, l := range c.objects["lblisteners"] {
+		if l["_loadbalancer_id"] == obj["id"] {
+			delete(c.objects["lblisteners"], id)
+		}
+	}
+	for id, p := range c.objects["lbpools"] {
+		for _, lb := range mockList(p["loadbalancers"]) {
+			if mockMap(lb)["id"] == obj["id"] {
+				delete(c.objects["lbpools"], id)
+			}
+		}
+	}
+}
+
+func mockCloudListenerCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	lbID := mockString(body["loadbalancer_id"])
+	lb := c.objects["loadbalancers"][lbID]
+	if lb == nil {
+		return nil, fmt.Errorf("loadbalancer %s not found", lbID)
+	}
+	for _, l := range c.objects["lblisteners"] {
+		if l["_loadbalancer_id"] == lbID && mockInt(l["protocol_port"]) == mockInt(body["protocol_port"]) {
+			return nil, fmt.Errorf("port %d is already used by listener %s", mockInt(body["protocol_port"]), l["id"])
+		}
+	}
+
+	obj := c.base(s, c.api.uuid())
+	delete(obj, "_metadata")
+	obj["name"] = mockString(body["name"])
+	obj["protocol"] = mockString(body["protocol"])
+	obj["protocol_port"] = mockInt(body["protocol_port"])
+	obj["pool_count"] = 0
+	obj["provisioning_status"] = "ACTIVE"
+	obj["operating_status"] = "ONLINE"
+	obj["insert_x_forwarded"] = mockBool(body["insert_x_forwarded"])
+	obj["secret_id"] = mockString(body["secret_id"])
+	obj["sni_secret_id"] = append([]interface{}{}, mockList(body["sni_secret_id"])...)
+	obj["_loadbalancer_id"] = lbID
+
+	lb["listeners"] = append(mockList(lb["listeners"]), map[string]interface{}{"id": obj["id"]})
+	return obj, nil
+}
+
+func mockCloudSecretCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	payload := mockMap(body["payload"])
+	for _, field := range []string{"certificate", "certificate_chain", "private_key"} {
+		if mockString(payload[field]) == "" {
+			return nil, fmt.Errorf("payload %s is required", field)
+		}
+	}
+	obj := c.base(s, c.api.uuid())
+	delete(obj, "_metadata")
+	obj["name"] = mockString(body["name"])
+	obj["status"] = "ACTIVE"
+	obj["algorithm"] = "RSA"
+	obj["bit_length"] = 2048
+	obj["mode"] = "cbc"
+	obj["secret_type"] = "certificate"
+	obj["content_types"] = map[string]interface{}{"default": "application/octet-stream"}
+	obj["created"] = "2021-01-01T00:00:00+00:00"
+	obj["expiration"] = ""
+	if exp := mockString(body["expiration"]); exp != "" {
+		t, err := time.Parse(gcorecloud.RFC3339MilliNoZ, exp)
+		if err != nil {
+			return nil, err
+		}
+		obj["expiration"] = t.Format(gcorecloud.RFC3339ZColon)
+	}
+	return obj, nil
+}
+
+// mockCloudSecretInUse refuses to delete a secret of a listener, like the
+// API does.
+func mockCloudSecretInUse(c *mockCloud, obj map[string]interface{}) error {
+	for _, id := range mockSortedKeys(c.objects["lblisteners"]) {
+		l := c.objects["lblisteners"][id]
+		used := l["secret_id"] == obj["id"]
+		for _, sni := range mockList(l["sni_secret_id"]) {
+			used = used || sni == obj["id"]
+		}
+		if used {
+			return fmt.Errorf("secret %s is used by listener %s", obj["id"], id)
+		}
+	}
+	return nil
+}
+
+func mockCloudListenerRemove(c *mockCloud, obj map[string]interface{}) {
+	lb := c.objects["loadbalancers"][mockString(obj["_loadbalancer_id"])]
+	if lb == nil {
+		return
+	}
+	listeners := make([]interface{}, 0)
+	for _, l := range mockList(lb["listeners"]) {
+		if mockMap(l)["id"] != obj["id"] {
+			listeners = append(listeners, l)
+		}
+	}
+	lb["listeners"] = listeners
+}
+
+func (c *mockCloud) poolMember(spec map[string]interface{}) map[string]interface{} {
+	id := mockString(spec["id"])
+	if id == "" {
+		id = c.api.uuid()
+	}
+	weight := mockInt(spec["weight"])
+	if weight == 0 {
+		weight = 1
+	}
+	m := map[string]interface{}{
+		"id":               id,
+		"address":          spec["address"],
+		"protocol_port":    mockInt(spec["protocol_port"]),
+		"weight":           weight,
+		"subnet_id":        mockString(spec["subnet_id"]),
+		"instance_id":      mockString(spec["instance_id"]),
+		"operating_status": "ONLINE",
+	}
+	if v, ok := spec["monitor_address"]; ok {
+		m["monitor_address"] = v
+	}
+	if v, ok := spec["monitor_port"]; ok {
+		m["monitor_port"] = v
+	}
+	return m
+}
+
+func (c *mockCloud) healthMonitor(spec map[string]interface{}) interface{} {
+	if spec == nil {
+		return nil
+	}
+	hm := mockCopy(spec)
+	if mockString(hm["id"]) == "" {
+		hm["id"] = c.api.uuid()
+	}
+	return hm
+}
+
+func mockCloudPoolCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	lbID := mockString(body["loadbalancer_id"])
+	listenerID := mockString(body["listener_id"])
+	if listenerID != "" {
+		listener := c.objects["lblisteners"][listenerID]
+		if listener == nil {
+			return nil, fmt.Errorf("listener %s not found", listenerID)
+		}
+		if lbID == "" {
+			lbID = mockString(listener["_loadbalancer_id"])
+		}
+		listener["pool_count"] = mockInt(listener["pool_count"]) + 1
+	}
+	if c.objects["loadbalancers"][lbID] == nil {
+		return nil, fmt.Errorf("loadbalancer %s not found", lbID)
+	}
+
+	members := make([]interface{}, 0)
+	for _, m := range mockList(body["members"]) {
+		members = append(members, c.poolMember(mockMap(m)))
+	}
+	listeners := make([]interface{}, 0)
+	if listenerID != "" {
+		listeners = append(listeners, map[string]interface{}{"id": listenerID})
+	}
+
+	obj := c.base(s, c.api.uuid())
+	delete(obj, "_metadata")
+	obj["name"] = mockString(body["name"])
+	obj["protocol"] = mockString(body["protocol"])
+	obj["lb_algorithm"] = mockString(body["lb_algorithm"])
+	obj["loadbalancers"] = []interface{}{map[string]interface{}{"id": lbID}}
+	obj["listeners"] = listeners
+	obj["members"] = members
+	obj["healthmonitor"] = c.healthMonitor(mockMap(body["healthmonitor"]))
+	obj["session_persistence"] = body["session_persistence"]
+	obj["provisioning_status"] = "ACTIVE"
+	obj["operating_status"] = "ONLINE"
+	obj["timeout_client_data"] = mockInt(body["timeout_client_data"])
+	obj["timeout_member_data"] = mockInt(body["timeout_member_data"])
+	obj["timeout_member_connect"] = mockInt(body["timeout_member_connect"])
+	return obj, nil
+}
+
+func mockCloudPoolUpdate(c *mockCloud, s mockScope, obj, body map[string]interface{}) (interface{}, int, error) {
+	for k, v := range body {
+		switch k {
+		case "members":
+			members := make([]interface{}, 0)
+			for _, m := range mockList(v) {
+				members = append(members, c.poolMember(mockMap(m)))
+			}
+			obj["members"] = members
+		case "healthmonitor":
+			obj["healthmonitor"] = c.healthMonitor(mockMap(v))
+		default:
+			obj[k] = v
+		}
+	}
+	memberIDs := make([]interface{}, 0)
+	for _, m := range mockList(obj["members"]) {
+		memberIDs = append(memberIDs, mockMap(m)["id"])
+	}
+	return c.task(s, map[string]interface{}{
+		"pools":   []interface{}{obj["id"]},
+		"members": memberIDs,
+	}), http.StatusOK, nil
+}
+
+func mockCloudPoolRemove(c *mockCloud, obj map[string]interface{}) {
+	for _, l := range mockList(obj["listeners"]) {
+		if listener := c.objects["lblisteners"][mockString(mockMap(l)["id"])]; listener != nil {
+			listener["pool_count"] = mockInt(listener["pool_count"]) - 1
+		}
+	}
+}
+
+func mockCloudPoolMember(c *mockCloud, w http.ResponseWriter, r *http.Request, s mockScope, obj map[string]interface{}, rest []string) {
+	switch {
+	case r.Method == http.MethodPost && len(rest) == 0:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		m := c.poolMember(body)
+		obj["members"] = append(mockList(obj["members"]), m)
+		mockWriteJSON(w, http.StatusOK, c.task(s, map[string]interface{}{"members": []interface{}{m["id"]}}))
+	case r.Method == http.MethodDelete && len(rest) == 1:
+		members := make([]interface{}, 0)
+		found := false
+		for _, m := range mockList(obj["members"]) {
+			if mockString(mockMap(m)["id"]) == rest[0] {
+				found = true
+				continue
+			}
+			members = append(members, m)
+		}
+		if !found {
+			c.fail(w, http.StatusNotFound, "member %s not found", rest[0])
+			return
+		}
+		obj["members"] = members
+		mockWriteJSON(w, http.StatusOK, c.task(s, nil))
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+func mockCloudPoolHealthMonitor(c *mockCloud, w http.ResponseWriter, r *http.Request, s mockScope, obj map[string]interface{}, _ []string) {
+	switch r.Method {
+	case http.MethodPost:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		hm := c.healthMonitor(body)
+		obj["healthmonitor"] = hm
+		mockWriteJSON(w, http.StatusOK, c.task(s, map[string]interface{}{"healthmonitors": []interface{}{mockMap(hm)["id"]}}))
+	case http.MethodDelete:
+		obj["healthmonitor"] = nil
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+func mockCloudSecurityGroupCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	spec := mockMap(body["security_group"])
+	name := mockString(spec["name"])
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	id := c.api.uuid()
+	sg := c.base(s, id)
+	sg["name"] = name
+	sg["description"] = mockString(spec["description"])
+	sg["updated_at"] = nil
+	sg["revision_number"] = 0
+	rules := make([]interface{}, 0)
+	for _, raw := range mockList(spec["security_group_rules"]) {
+		rules = append(rules, c.securityGroupRule(id, mockMap(raw)))
+	}
+	sg["security_group_rules"] = rules
+	for k, v := range mockMap(spec["metadata"]) {
+		mockMap(sg["_metadata"])[k] = v
+	}
+	return sg, nil
+}
+
+func (c *mockCloud) securityGroupRule(sgID string, spec map[string]interface{}) map[string]interface{} {
+	rule := mockCopy(spec)
+	rule["id"] = c.api.uuid()
+	rule["security_group_id"] = sgID
+	rule["revision_number"] = 0
+	rule["created_at"] = mockCloudTime
+	rule["updated_at"] = nil
+	return rule
+}
+
+// mockCloudSecurityGroupRender renders the rules. A deleted rule is still
+// listed by the next read of the group, like the API does while the delete
+// is applied.
+func mockCloudSecurityGroupRender(c *mockCloud, obj, res map[string]interface{}) {
+	kept := make([]interface{}, 0)
+	rendered := make([]interface{}, 0)
+	for _, raw := range mockList(obj["security_group_rules"]) {
+		rule := mockMap(raw)
+		if reads, deleting := rule["_deleting"]; deleting {
+			if mockInt(reads) <= 0 {
+				continue
+			}
+			rule["_deleting"] = mockInt(reads) - 1
+		}
+		kept = append(kept, rule)
+		rendered = append(rendered, mockCopy(rule))
+	}
+	obj["security_group_rules"] = kept
+	res["security_group_rules"] = rendered
+}
+
+func mockCloudSecurityGroupAddRule(c *mockCloud, w http.ResponseWriter, r *http.Request, s mockScope, obj map[string]interface{}, rest []string) {
+	if r.Method != http.MethodPost || len(rest) != 0 {
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body["remote_group_id"] != nil && body["remote_ip_prefix"] != nil {
+		c.fail(w, http.StatusBadRequest, "remote_group_id and remote_ip_prefix are mutually exclusive")
+		return
+	}
+	if remote := mockString(body["remote_group_id"]); remote != "" && c.objects["securitygroups"][remote] == nil {
+		c.fail(w, http.StatusBadRequest, "security group %s not found", remote)
+		return
+	}
+	rule := c.securityGroupRule(mockString(obj["id"]), body)
+	obj["security_group_rules"] = append(mockList(obj["security_group_rules"]), rule)
+	mockWriteJSON(w, http.StatusCreated, mockCopy(rule))
+}
+
+// SecurityGroupRules returns copies of the rules of the security group
+// including the ones being deleted.
+func (c *mockCloud) SecurityGroupRules(sgID string) []map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+
+	var res []map[string]interface{}
+	for _, raw := range mockList(c.objects["securitygroups"][sgID]["security_group_rules"]) {
+		res = append(res, mockCopy(mockMap(raw)))
+	}
+	return res
+}
+
+// SeedSecurityGroupRule adds a rule to the security group as if it was
+// created by another client and returns its ID.
+func (c *mockCloud) SeedSecurityGroupRule(sgID string, spec map[string]interface{}) string {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+
+	sg := c.objects["securitygroups"][sgID]
+	if sg == nil {
+		c.api.t.Fatalf("seed security group rule: security group %s not found", sgID)
+	}
+	rule := c.securityGroupRule(sgID, spec)
+	sg["security_group_rules"] = append(mockList(sg["security_group_rules"]), rule)
+	return mockString(rule["id"])
+}
+
+// serveSecurityGroupRules serves /v1/securitygrouprules/{project}/{region}/{id},
+// the rules are kept in their security groups.
+func (c *mockCloud) serveSecurityGroupRules(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) != 3 {
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+		return
+	}
+	var sg, rule map[string]interface{}
+	for _, obj := range c.objects["securitygroups"] {
+		for _, raw := range mockList(obj["security_group_rules"]) {
+			if m := mockMap(raw); mockString(m["id"]) == segs[2] && m["_deleting"] == nil {
+				sg, rule = obj, m
+			}
+		}
+	}
+	if rule == nil {
+		c.fail(w, http.StatusNotFound, "rule %s not found", segs[2])
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		replaced := c.securityGroupRule(mockString(sg["id"]), body)
+		replaced["id"] = rule["id"]
+		for k := range rule {
+			delete(rule, k)
+		}
+		for k, v := range replaced {
+			rule[k] = v
+		}
+		mockWriteJSON(w, http.StatusOK, mockCopy(rule))
+	case http.MethodDelete:
+		rule["_deleting"] = 1
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+// mockKubeconfig renders a kubeconfig of the cluster, the certificates and
+// the key are PEM blocks with the cluster name as the body.
+func mockKubeconfig(cluster map[string]interface{}) string {
+	name := mockString(cluster["name"])
+	pem := func(kind string) string {
+		return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("-----BEGIN %s-----\n%s\n-----END %s-----\n", kind, name, kind)))
+	}
+	return fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: %[1]s
+  cluster:
+    server: %[2]s
+    certificate-authority-data: %[3]s
+contexts:
+- name: admin@%[1]s
+  context:
+    cluster: %[1]s
+    user: admin
+current-context: admin@%[1]s
+users:
+- name: admin
+  user:
+    client-certificate-data: %[4]s
+    client-key-data: %[5]s
+`, name, mockString(cluster["api_address"]), pem("CERTIFICATE"), pem("CERTIFICATE"), pem("RSA PRIVATE KEY"))
+}
+
+// K8sCluster returns a copy of the cluster, nil when it doesn't exist.
+func (c *mockCloud) K8sCluster(id string) map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+
+	cluster := c.objects["k8s_clusters"][id]
+	if cluster == nil {
+		return nil
+	}
+	return mockCopy(cluster)
+}
+
+// K8sPools returns copies of the pools of the cluster in creation order.
+func (c *mockCloud) K8sPools(clusterID string) []map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+
+	cluster := c.objects["k8s_clusters"][clusterID]
+	if cluster == nil {
+		return nil
+	}
+	var res []map[string]interface{}
+	for _, id := range cluster["_pools"].([]string) {
+		res = append(res, mockCopy(c.objects["k8s_pools"][id]))
+	}
+	return res
+}
+
+// SeedK8sCluster adds a cluster with one pool as if it was created by another
+// client and returns its ID.
+func (c *mockCloud) SeedK8sCluster(name string) string {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+
+	id := c.api.uuid()
+	cluster := map[string]interface{}{
+		"uuid":                 id,
+		"name":                 name,
+		"cluster_template_id":  c.api.uuid(),
+		"keypair":              "keypair",
+		"master_flavor_id":     "g1-standard-2-4",
+		"status":               "CREATE_COMPLETE",
+		"health_status":        "HEALTHY",
+		"health_status_reason": map[string]interface{}{},
+		"faults":               map[string]interface{}{},
+		"version":              "1.20.6",
+		"api_address":          "https://10.0.0.10:6443",
+		"discovery_url":        "https://discovery.etcd.io/" + id,
+		"node_addresses":       []interface{}{},
+		"master_addresses":     []interface{}{"10.0.0.10"},
+		"created_at":           mockCloudTimeZZ,
+		"updated_at":           nil,
+		"_project":             mockProjectID,
+		"_region":              mockRegionID,
+		"_pools":               []string{},
+	}
+	c.objects["k8s_clusters"][id] = cluster
+	c.createK8sPool(cluster, map[string]interface{}{"name": "default", "flavor_id": "g1-standard-1-2", "node_count": 1, "min_node_count": 1, "max_node_count": 1})
+	return id
+}
+
+// SeedK8sPool adds a pool to the cluster as if it was created by another
+// client, e.g. a gcore_k8s_pool resource, and returns its ID.
+func (c *mockCloud) SeedK8sPool(clusterID string, spec map[string]interface{}) string {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+
+	cluster := c.objects["k8s_clusters"][clusterID]
+	if cluster == nil {
+		c.api.t.Fatalf("seed k8s pool: cluster %s not found", clusterID)
+	}
+	return c.createK8sPool(cluster, spec)
+}
+
+func (c *mockCloud) createK8sPool(cluster, spec map[string]interface{}) string {
+	id := c.api.uuid()
+	ids := cluster["_pools"].([]string)
+	volumeType := mockString(spec["docker_volume_type"])
+	if volumeType == "" {
+		volumeType = "standard"
+	}
+	c.objects["k8s_pools"][id] = map[string]interface{}{
+		"uuid":               id,
+		"cluster_id":         cluster["uuid"],
+		"project_id":         strconv.Itoa(mockInt(cluster["_project"])),
+		"name":               mockString(spec["name"]),
+		"flavor_id":          mockString(spec["flavor_id"]),
+		"image_id":           "",
+		"node_count":         mockInt(spec["node_count"]),
+		"min_node_count":     mockInt(spec["min_node_count"]),
+		"max_node_count":     mockInt(spec["max_node_count"]),
+		"docker_volume_size": mockInt(spec["docker_volume_size"]),
+		"docker_volume_type": volumeType,
+		"is_default":         len(ids) == 0,
+		"stack_id":           c.api.uuid(),
+		"status":             "CREATE_COMPLETE",
+		"role":               "worker",
+		"labels":             map[string]interface{}{},
+		"node_addresses":     []interface{}{},
+		"status_reason":      "",
+		"created_at":         mockCloudTimeZZ,
+		"updated_at":         nil,
+	}
+	cluster["_pools"] = append(ids, id)
+	return id
+}
+
+func (c *mockCloud) renderK8sCluster(cluster map[string]interface{}) map[string]interface{} {
+	res := mockCopy(cluster)
+	nodes := 0
+	pools := make([]interface{}, 0)
+	for _, id := range cluster["_pools"].([]string) {
+		pool := c.objects["k8s_pools"][id]
+		nodes += mockInt(pool["node_count"])
+		pools = append(pools, mockCopy(pool))
+	}
+	res["node_count"] = nodes
+	res["pools"] = pools
+	return res
+}
+
+// serveK8s serves /v1/k8s/clusters/{project}/{region}/..., the clusters and
+// their pools are created, changed and deleted through tasks.
+func (c *mockCloud) serveK8s(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) < 3 || segs[0] != "clusters" {
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+		return
+	}
+	project, _ := strconv.Atoi(segs[1])
+	region, _ := strconv.Atoi(segs[2])
+	s := mockScope{project: project, region: region}
+	rest := segs[3:]
+	clusters := c.objects["k8s_clusters"]
+
+	if len(rest) == 0 {
+		if r.Method != http.MethodPost {
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+			return
+		}
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(mockList(body["pools"])) == 0 {
+			c.fail(w, http.StatusBadRequest, "pools are required")
+			return
+		}
+		id := c.api.uuid()
+		version := mockString(body["version"])
+		if version == "" {
+			version = "1.20.6"
+		}
+		cluster := map[string]interface{}{
+			"uuid":                 id,
+			"name":                 mockString(body["name"]),
+			"cluster_template_id":  c.api.uuid(),
+			"keypair":              mockString(body["keypair"]),
+			"master_count":         1,
+			"docker_volume_size":   10,
+			"labels":               map[string]interface{}{},
+			"master_flavor_id":     "g1-standard-2-4",
+			"flavor_id":            "g1-standard-1-2",
+			"create_timeout":       7200,
+			"stack_id":             c.api.uuid(),
+			"status":               "CREATE_COMPLETE",
+			"status_reason":        "",
+			"health_status":        "HEALTHY",
+			"health_status_reason": map[string]interface{}{},
+			"faults":               map[string]interface{}{},
+			"version":              version,
+			"coe_version":          "v" + version,
+			"container_version":    "1.12.6",
+			"api_address":          "https://10.0.0.10:6443",
+			"discovery_url":        "https://discovery.etcd.io/" + id,
+			"project_id":           strconv.Itoa(project),
+			"user_id":              "1",
+			"node_addresses":       []interface{}{},
+			"master_addresses":     []interface{}{"10.0.0.10"},
+			"fixed_network":        mockString(body["fixed_network"]),
+			"fixed_subnet":         mockString(body["fixed_subnet"]),
+			"floating_ip_enabled":  mockBool(body["master_lb_floating_ip_enabled"]),
+			"external_dns_enabled": mockBool(body["external_dns_enabled"]),
+			"auto_healing_enabled": mockBool(body["auto_healing_enabled"]),
+			"created_at":           mockCloudTimeZZ,
+			"updated_at":           nil,
+			"_project":             project,
+			"_region":              region,
+			"_pools":               []string{},
+		}
+		var poolIDs []string
+		for _, raw := range mockList(body["pools"]) {
+			poolIDs = append(poolIDs, c.createK8sPool(cluster, mockMap(raw)))
+		}
+		clusters[id] = cluster
+		mockWriteJSON(w, http.StatusOK, c.task(s, map[string]interface{}{
+			"k8s_clusters": []string{id},
+			"k8s_pools":    poolIDs,
+		}))
+		return
+	}
+
+	cluster, ok := clusters[rest[0]]
+	if !ok || mockInt(cluster["_project"]) != project || mockInt(cluster["_region"]) != region {
+		c.fail(w, http.StatusNotFound, "cluster %s not found", rest[0])
+		return
+	}
+
+	if len(rest) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			mockWriteJSON(w, http.StatusOK, c.renderK8sCluster(cluster))
+		case http.MethodDelete:
+			for _, id := range cluster["_pools"].([]string) {
+				delete(c.objects["k8s_pools"], id)
+			}
+			delete(clusters, rest[0])
+			mockWriteJSON(w, http.StatusOK, c.task(s, nil))
+		default:
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+		return
+	}
+
+	if len(rest) == 2 && rest[1] == "config" {
+		if r.Method != http.MethodGet {
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+			return
+		}
+		mockWriteJSON(w, http.StatusOK, map[string]interface{}{"config": mockKubeconfig(cluster)})
+		return
+	}
+
+	if len(rest) == 2 && rest[1] == "upgrade" {
+		if r.Method != http.MethodPost {
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+			return
+		}
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		version := mockString(body["version"])
+		if version == "" {
+			c.fail(w, http.StatusBadRequest, "version is required")
+			return
+		}
+		cluster["version"] = version
+		cluster["coe_version"] = "v" + version
+		cluster["updated_at"] = mockCloudTimeZZ
+		mockWriteJSON(w, http.StatusOK, c.task(s, nil))
+		return
+	}
+
+	if rest[1] != "pools" {
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+		return
+	}
+	if len(rest) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			items := make([]interface{}, 0)
+			for _, id := range cluster["_pools"].([]string) {
+				items = append(items, mockCopy(c.objects["k8s_pools"][id]))
+			}
+			mockWriteJSON(w, http.StatusOK, c.page(items))
+		case http.MethodPost:
+			body, err := mockReadJSON(r)
+			if err != nil {
+				c.fail(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			for _, id := range cluster["_pools"].([]string) {
+				if mockString(c.objects["k8s_pools"][id]["name"]) == mockString(body["name"]) {
+					c.fail(w, http.StatusConflict, "pool %s already exists", body["name"])
+					return
+				}
+			}
+			id := c.createK8sPool(cluster, body)
+			mockWriteJSON(w, http.StatusOK, c.task(s, map[string]interface{}{"k8s_pools": []string{id}}))
+		default:
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+		return
+	}
+
+	poolID := rest[2]
+	pool, ok := c.objects["k8s_pools"][poolID]
+	if !ok || pool["cluster_id"] != cluster["uuid"] {
+		c.fail(w, http.StatusNotFound, "pool %s not found", poolID)
+		return
+	}
+
+	switch {
+	case len(rest) == 3 && r.Method == http.MethodGet:
+		mockWriteJSON(w, http.StatusOK, mockCopy(pool))
+	case len(rest) == 3 && r.Method == http.MethodPatch:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for _, k := range []string{"name", "min_node_count", "max_node_count"} {
+			if v, ok := body[k]; ok {
+				pool[k] = v
+			}
+		}
+		mockWriteJSON(w, http.StatusOK, c.task(s, nil))
+	case len(rest) == 3 && r.Method == http.MethodDelete:
+		ids := cluster["_pools"].([]string)
+		if len(ids) == 1 {
+			c.fail(w, http.StatusBadRequest, "the last pool of a cluster can't be deleted")
+			return
+		}
+		kept := make([]string, 0, len(ids)-1)
+		for _, id := range ids {
+			if id != poolID {
+				kept = append(kept, id)
+			}
+		}
+		cluster["_pools"] = kept
+		delete(c.objects["k8s_pools"], poolID)
+		mockWriteJSON(w, http.StatusOK, c.task(s, nil))
+	case len(rest) == 4 && rest[3] == "resize" && r.Method == http.MethodPost:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pool["node_count"] = mockInt(body["node_count"])
+		mockWriteJSON(w, http.StatusOK, c.task(s, nil))
+	default:
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+	}
+}
+
+// mockFaaSBuildFailure in the code of a function makes its build fail.
+const mockFaaSBuildFailure = "BUILD_FAILURE"
+
+// FaaSFunction returns a copy of the function, nil when it doesn't exist.
+func (c *mockCloud) FaaSFunction(namespace, name string) map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+
+	for _, f := range c.objects["faas_functions"] {
+		if f["_namespace"] == namespace && f["name"] == name {
+			return mockCopy(f)
+		}
+	}
+	return nil
+}
+
+// mockFaaSSources returns the code of a function, the files of a base64
+// encoded zip archive are joined.
+func mockFaaSSources(code string) string {
+	data, err := base64.StdEncoding.DecodeString(code)
+	if err != nil {
+		return code
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return code
+	}
+	var sources []string
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return code
+		}
+		content, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return code
+		}
+		sources = append(sources, string(content))
+	}
+	return strings.Join(sources, "\n")
+}
+
+// buildFaaSFunction builds the code of the function at once, the build fails
+// when the code contains mockFaaSBuildFailure.
+func (c *mockCloud) buildFaaSFunction(f map[string]interface{}) {
+	total := mockInt(f["_min_instances"])
+	if total == 0 {
+		total = 1
+	}
+	if strings.Contains(mockFaaSSources(mockString(f["code_text"])), mockFaaSBuildFailure) {
+		f["build_status"] = "failed"
+		f["build_message"] = "SyntaxError: unexpected token"
+		f["status"] = "error"
+		f["deploy_status"] = map[string]interface{}{"total": total, "ready": 0}
+		return
+	}
+	f["build_status"] = "success"
+	f["build_message"] = ""
+	f["status"] = "active"
+	f["deploy_status"] = map[string]interface{}{"total": total, "ready": total}
+}
+
+// mockCloudFaaSFunctions serves the functions of a namespace, they are
+// created, changed and deleted through tasks.
+func mockCloudFaaSFunctions(c *mockCloud, w http.ResponseWriter, r *http.Request, s mockScope, ns map[string]interface{}, rest []string) {
+	namespace := mockString(ns["name"])
+	functions := c.objects["faas_functions"]
+	key := func(name string) string {
+		return fmt.Sprintf("%d/%d/%s/%s", s.project, s.region, namespace, name)
+	}
+
+	if len(rest) == 0 {
+		if r.Method != http.MethodPost {
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+			return
+		}
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		name := mockString(body["name"])
+		if _, ok := functions[key(name)]; ok {
+			c.fail(w, http.StatusConflict, "function %s already exists", name)
+			return
+		}
+		autoscaling := mockMap(body["autoscaling"])
+		f := map[string]interface{}{
+			"name":        name,
+			"description": mockString(body["description"]),
+			"envs":        body["envs"],
+			"runtime":     mockString(body["runtime"]),
+			"timeout":     mockInt(body["timeout"]),
+			"flavor":      mockString(body["flavor"]),
+			"autoscaling": map[string]interface{}{
+				"min_instances": mockInt(autoscaling["min_instances"]),
+				"max_instances": mockInt(autoscaling["max_instances"]),
+			},
+			"code_text":      mockString(body["code_text"]),
+			"main_method":    mockString(body["main_method"]),
+			"endpoint":       fmt.Sprintf("https://%s-%s.faas.example.com", name, namespace),
+			"created_at":     mockCloudTimeZZ,
+			"_namespace":     namespace,
+			"_min_instances": mockInt(autoscaling["min_instances"]),
+		}
+		c.buildFaaSFunction(f)
+		functions[key(name)] = f
+		mockWriteJSON(w, http.StatusOK, c.task(s, nil))
+		return
+	}
+
+	f, ok := functions[key(rest[0])]
+	if len(rest) != 1 || !ok {
+		c.fail(w, http.StatusNotFound, "function %s not found", rest[0])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		mockWriteJSON(w, http.StatusOK, mockCopy(f))
+	case http.MethodDelete:
+		delete(functions, key(rest[0]))
+		mockWriteJSON(w, http.StatusOK, c.task(s, nil))
+	case http.MethodPatch:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for k, v := range body {
+			f[k] = v
+		}
+		if autoscaling := mockMap(body["autoscaling"]); autoscaling != nil {
+			f["_min_instances"] = mockInt(autoscaling["min_instances"])
+		}
+		if _, ok := body["code_text"]; ok {
+			c.buildFaaSFunction(f)
+		}
+		mockWriteJSON(w, http.StatusOK, c.task(s, nil))
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+// reserved fixed ips
+
+func mockCloudReservedFixedIPCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	var networkID, subnetID, ipAddress string
+	switch typ := mockString(body["type"]); typ {
+	case "external":
+		networkID, subnetID = mockExternalNetworkID, mockExternalSubnetID
+		ipAddress = mockNthIP(net.ParseIP("92.38.0.0"), c.api.nextID())
+	case "subnet":
+		subnetID = mockString(body["subnet_id"])
+		subnet := c.objects["subnets"][subnetID]
+		if subnet == nil {
+			return nil, fmt.Errorf("subnet %s not found", subnetID)
+		}
+		networkID = mockString(subnet["network_id"])
+		ipAddress = c.allocateIP(subnet)
+	case "any_subnet", "ip_address":
+		networkID = mockString(body["network_id"])
+		network := c.objects["networks"][networkID]
+		if network == nil {
+			return nil, fmt.Errorf("network %s not found", networkID)
+		}
+		subnets := mockList(network["subnets"])
+		if len(subnets) == 0 {
+			return nil, fmt.Errorf("network %s has no subnets", networkID)
+		}
+		subnetID = mockString(subnets[0])
+		ipAddress = mockString(body["ip_address"])
+		if ipAddress == "" {
+			ipAddress = c.allocateIP(c.objects["subnets"][subnetID])
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q", typ)
+	}
+
+	obj := c.base(s, "")
+	delete(obj, "id")
+	obj["port_id"] = c.api.uuid()
+	obj["name"] = "reserved_fixed_ip_" + ipAddress
+	obj["updated_at"] = mockCloudTime
+	obj["status"] = "DOWN"
+	obj["fixed_ip_address"] = ipAddress
+	obj["subnet_id"] = subnetID
+	obj["network_id"] = networkID
+	obj["creator_task_id"] = ""
+	obj["task_id"] = nil
+	obj["is_external"] = networkID == mockExternalNetworkID
+	obj["is_vip"] = mockBool(body["is_vip"])
+	obj["reservation"] = map[string]interface{}{"status": "available", "resource_type": nil, "resource_id": nil}
+	obj["allowed_address_pairs"] = []interface{}{}
+	obj["_connected"] = []interface{}{}
+	return obj, nil
+}
+
+// ConnectedPorts returns the IDs of the ports sharing the VIP.
+func (c *mockCloud) ConnectedPorts(portID string) []string {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	var res []string
+	for _, id := range mockList(c.objects["reserved_fixed_ips"][portID]["_connected"]) {
+		res = append(res, mockString(id))
+	}
+	sort.Strings(res)
+	return res
+}
+
+// InstancePorts returns the IDs of the ports of the instance.
+func (c *mockCloud) InstancePorts(instanceID string) []string {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	var res []string
+	for _, raw := range mockList(c.objects["instances"][instanceID]["_interfaces"]) {
+		res = append(res, mockString(mockMap(raw)["port_id"]))
+	}
+	return res
+}
+
+// reservedFixedIPDevices returns the instance ports of the network of the
+// reserved fixed ip as devices, keyed by port ID.
+func (c *mockCloud) reservedFixedIPDevices(obj map[string]interface{}) map[string]map[string]interface{} {
+	res := make(map[string]map[string]interface{})
+	for _, id := range mockSortedKeys(c.objects["instances"]) {
+		inst := c.objects["instances"][id]
+		if mockInt(inst["project_id"]) != mockInt(obj["project_id"]) || mockInt(inst["region_id"]) != mockInt(obj["region_id"]) {
+			continue
+		}
+		for _, raw := range mockList(inst["_interfaces"]) {
+			iface := mockMap(raw)
+			if iface["network_id"] != obj["network_id"] {
+				continue
+			}
+			network := map[string]interface{}{"id": iface["network_id"]}
+			if n := c.objects["networks"][mockString(iface["network_id"])]; n != nil {
+				network = c.render("networks", n)
+			}
+			res[mockString(iface["port_id"])] = map[string]interface{}{
+				"port_id":        iface["port_id"],
+				"instance_id":    inst["instance_id"],
+				"instance_name":  inst["instance_name"],
+				"ip_assignments": iface["ip_assignments"],
+				"network":        network,
+			}
+		}
+	}
+	return res
+}
+
+func mockCloudReservedFixedIPAvailableDevices(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	devices := c.reservedFixedIPDevices(obj)
+	items := make([]interface{}, 0, len(devices))
+	for _, id := range mockSortedKeys(devices) {
+		items = append(items, devices[id])
+	}
+	mockWriteJSON(w, http.StatusOK, c.page(items))
+}
+
+// mockCloudReservedFixedIPConnectedDevices lists the ports sharing the VIP,
+// PUT replaces and PATCH adds them.
+func mockCloudReservedFixedIPConnectedDevices(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	devices := c.reservedFixedIPDevices(obj)
+	connected := mockList(obj["_connected"])
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPatch:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if !mockBool(obj["is_vip"]) {
+			c.fail(w, http.StatusBadRequest, "reserved fixed ip %s is not a vip", mockString(obj["port_id"]))
+			return
+		}
+		if r.Method == http.MethodPut {
+			connected = []interface{}{}
+		}
+		seen := make(map[string]bool)
+		for _, id := range connected {
+			seen[mockString(id)] = true
+		}
+		for _, raw := range mockList(body["port_ids"]) {
+			id := mockString(raw)
+			if devices[id] == nil {
+				c.fail(w, http.StatusBadRequest, "port %s is not available for the vip", id)
+				return
+			}
+			if !seen[id] {
+				seen[id] = true
+				connected = append(connected, id)
+			}
+		}
+		obj["_connected"] = connected
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+
+	items := make([]interface{}, 0, len(connected))
+	for _, id := range connected {
+		if d := devices[mockString(id)]; d != nil {
+			items = append(items, d)
+		}
+	}
+	mockWriteJSON(w, http.StatusOK, c.page(items))
+}
+
+// floating ips
+
+func mockCloudFloatingIPCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	obj := c.base(s, c.api.uuid())
+	obj["floating_ip_address"] = mockNthIP(net.ParseIP("185.0.0.0"), c.api.nextID())
+	obj["router_id"] = ""
+	obj["subnet_id"] = mockExternalSubnetID
+	obj["dns_domain"] = ""
+	obj["dns_name"] = ""
+	obj["updated_at"] = mockCloudTime
+	obj["creator_task_id"] = nil
+	obj["_metadata"] = mockMetadataFromBody(body["metadata"])
+	mockAssignFloatingIP(obj, body)
+	return obj, nil
+}
+
+func mockAssignFloatingIP(obj, body map[string]interface{}) {
+	obj["port_id"] = mockString(body["port_id"])
+	obj["fixed_ip_address"] = nil
+	if ip := mockString(body["fixed_ip_address"]); ip != "" {
+		obj["fixed_ip_address"] = ip
+	}
+	obj["status"] = "DOWN"
+	if obj["port_id"] != "" {
+		obj["status"] = "ACTIVE"
+	}
+}
+
+func mockCloudFloatingIPAssign(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	mockAssignFloatingIP(obj, body)
+	mockWriteJSON(w, http.StatusOK, c.render("floatingips", obj))
+}
+
+func mockCloudFloatingIPUnassign(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	mockAssignFloatingIP(obj, map[string]interface{}{})
+	mockWriteJSON(w, http.StatusOK, c.render("floatingips", obj))
+}
+
+// routers
+
+func mockCloudRouterCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	name := mockString(body["name"])
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	obj := c.base(s, c.api.uuid())
+	delete(obj, "_metadata")
+	obj["name"] = name
+	obj["status"] = "ACTIVE"
+	obj["updated_at"] = mockCloudTime
+	obj["routes"] = append([]interface{}{}, mockList(body["routes"])...)
+	obj["interfaces"] = []interface{}{}
+	mockRouterGateway(c, obj, mockMap(body["external_gateway_info"]))
+	for _, raw := range mockList(body["interfaces"]) {
+		if err := c.attachRouterSubnet(s, obj, mockString(mockMap(raw)["subnet_id"])); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+// mockRouterGateway sets the gateway of the router, the external network
+// is used unless the manual type names another one.
+func mockRouterGateway(c *mockCloud, obj, info map[string]interface{}) {
+	networkID := mockExternalNetworkID
+	if mockString(info["type"]) == "manual" && mockString(info["network_id"]) != "" {
+		networkID = mockString(info["network_id"])
+	}
+	snat := true
+	if v, ok := info["enable_snat"]; ok && v != nil {
+		snat = mockBool(v)
+	}
+	obj["external_gateway_info"] = map[string]interface{}{
+		"network_id":  networkID,
+		"enable_snat": snat,
+		"external_fixed_ips": []interface{}{
+			map[string]interface{}{
+				"ip_address": mockNthIP(net.ParseIP("185.1.0.0"), c.api.nextID()),
+				"subnet_id":  mockExternalSubnetID,
+			},
+		},
+	}
+}
+
+// attachRouterSubnet adds the port of the router at the gateway of the
+// subnet.
+func (c *mockCloud) attachRouterSubnet(s mockScope, router map[string]interface{}, subnetID string) error {
+	subnet := c.objects["subnets"][subnetID]
+	if subnet == nil {
+		return fmt.Errorf("subnet %s not found", subnetID)
+	}
+	if subnet["gateway_ip"] == nil {
+		return fmt.Errorf("subnet %s has no gateway", subnetID)
+	}
+	for _, iface := range mockList(router["interfaces"]) {
+		for _, ip := range mockList(mockMap(iface)["ip_assignments"]) {
+			if mockMap(ip)["subnet_id"] == subnetID {
+				return fmt.Errorf("subnet %s is already attached", subnetID)
+			}
+		}
+	}
+	networkID := mockString(subnet["network_id"])
+	n := c.api.nextID()
+	router["interfaces"] = append(mockList(router["interfaces"]), map[string]interface{}{
+		"port_id":               c.api.uuid(),
+		"mac_address":           fmt.Sprintf("fa:16:3e:01:%02x:%02x", (n>>8)&0xff, n&0xff),
+		"network_id":            networkID,
+		"port_security_enabled": false,
+		"ip_assignments": []interface{}{
+			map[string]interface{}{"ip_address": subnet["gateway_ip"], "subnet_id": subnetID},
+		},
+		"network_details": map[string]interface{}{
+			"id":         networkID,
+			"name":       mockString(c.objects["networks"][networkID]["name"]),
+			"mtu":        1450,
+			"subnets":    []interface{}{},
+			"project_id": s.project,
+			"region_id":  s.region,
+			"region":     c.regionName(s.region),
+			"created_at": mockCloudTime,
+		},
+		"floatingip_details": []interface{}{},
+		"sub_ports":          []interface{}{},
+	})
+	subnet["has_router"] = true
+	return nil
+}
+
+func mockCloudRouterUpdate(c *mockCloud, _ mockScope, obj, body map[string]interface{}) (interface{}, int, error) {
+	if name := mockString(body["name"]); name != "" {
+		obj["name"] = name
+	}
+	if info := mockMap(body["external_gateway_info"]); len(info) > 0 {
+		mockRouterGateway(c, obj, info)
+	}
+	obj["routes"] = append([]interface{}{}, mockList(body["routes"])...)
+	return c.render("routers", obj), http.StatusOK, nil
+}
+
+func mockCloudRouterAttach(c *mockCloud, w http.ResponseWriter, r *http.Request, s mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := c.attachRouterSubnet(s, obj, mockString(body["subnet_id"])); err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	mockWriteJSON(w, http.StatusOK, c.render("routers", obj))
+}
+
+func mockCloudRouterDetach(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	subnetID := mockString(body["subnet_id"])
+	interfaces := make([]interface{}, 0)
+	for _, iface := range mockList(obj["interfaces"]) {
+		attached := false
+		for _, ip := range mockList(mockMap(iface)["ip_assignments"]) {
+			attached = attached || mockMap(ip)["subnet_id"] == subnetID
+		}
+		if !attached {
+			interfaces = append(interfaces, iface)
+		}
+	}
+	if len(interfaces) == len(mockList(obj["interfaces"])) {
+		c.fail(w, http.StatusBadRequest, "subnet %s is not attached", subnetID)
+		return
+	}
+	obj["interfaces"] = interfaces
+	if subnet := c.objects["subnets"][subnetID]; subnet != nil {
+		subnet["has_router"] = false
+	}
+	mockWriteJSON(w, http.StatusOK, c.render("routers", obj))
+}
+
+// baremetal servers
+
+// mockCloudBmInstanceCreate creates a baremetal server, it's served as an
+// instance afterwards.
+func mockCloudBmInstanceCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	if mockString(body["image_id"]) == "" && mockString(body["apptemplate_id"]) == "" {
+		return nil, fmt.Errorf("image_id or apptemplate_id is required")
+	}
+	return mockCloudInstanceCreate(c, s, body)
+}
+
+// snapshots
+
+func mockCloudSnapshotCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	name := mockString(body["name"])
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	volumeID := mockString(body["volume_id"])
+	vol := c.objects["volumes"][volumeID]
+	if vol == nil {
+		return nil, fmt.Errorf("volume %s not found", volumeID)
+	}
+	obj := c.base(s, c.api.uuid())
+	delete(obj, "_metadata")
+	obj["name"] = name
+	obj["description"] = mockString(body["description"])
+	obj["status"] = "available"
+	obj["size"] = mockInt(vol["size"])
+	obj["volume_id"] = volumeID
+	obj["updated_at"] = nil
+	obj["creator_task_id"] = nil
+	obj["task_id"] = nil
+	obj["metadata"] = mockMetadataFromBody(body["metadata"])
+	return obj, nil
+}
+
+// mockCloudSnapshotMetadata replaces the metadata of a snapshot, it's kept
+// in the snapshot itself and the replaced snapshot is returned.
+func mockCloudSnapshotMetadata(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+	if r.Method != http.MethodPut {
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	obj["metadata"] = mockMetadataFromBody(body)
+	mockWriteJSON(w, http.StatusOK, c.render("snapshots", obj))
+}
+
+// server groups
+
+func mockCloudServerGroupCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	name := mockString(body["name"])
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	policy := mockString(body["policy"])
+	if policy != "affinity" && policy != "anti-affinity" {
+		return nil, fmt.Errorf("unknown policy %q", policy)
+	}
+	obj := c.base(s, c.api.uuid())
+	delete(obj, "_metadata")
+	obj["servergroup_id"] = obj["id"]
+	obj["name"] = name
+	obj["policy"] = policy
+	obj["instances"] = []interface{}{}
+	return obj, nil
+}
+
+// keypairs
+
+// serveKeypairs serves /v2/keypairs, the keypairs belong to a project and
+// aren't bound to a region.
+func (c *mockCloud) serveKeypairs(w http.ResponseWriter, r *http.Request, rest []string) {
+	keypairs := c.objects["keypairs"]
+	if len(rest) == 0 {
+		if r.Method != http.MethodPost {
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+			return
+		}
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		name := mockString(body["sshkey_name"])
+		for _, kp := range keypairs {
+			if kp["sshkey_name"] == name && mockInt(kp["project_id"]) == mockInt(body["project_id"]) {
+				c.fail(w, http.StatusConflict, "keypair %s already exists", name)
+				return
+			}
+		}
+		id := c.api.uuid()
+		n := c.api.nextID()
+		kp := map[string]interface{}{
+			"sshkey_id":   id,
+			"sshkey_name": name,
+			"public_key":  mockString(body["public_key"]),
+			"fingerprint": fmt.Sprintf("2b:0a:%02x:%02x", (n>>8)&0xff, n&0xff),
+			"private_key": nil,
+			"project_id":  mockInt(body["project_id"]),
+		}
+		keypairs[id] = kp
+		mockWriteJSON(w, http.StatusOK, kp)
+		return
+	}
+
+	kp, ok := keypairs[rest[0]]
+	if len(rest) != 1 || !ok {
+		c.fail(w, http.StatusNotFound, "keypair %s not found", rest[0])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		mockWriteJSON(w, http.StatusOK, kp)
+	case http.MethodDelete:
+		delete(keypairs, rest[0])
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+// lifecycle policies
+
+func mockCloudLifecyclePolicyCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	name := mockString(body["name"])
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	status := mockString(body["status"])
+	if status == "" {
+		status = "active"
+	}
+	obj := map[string]interface{}{
+		"id":         c.api.nextID(),
+		"name":       name,
+		"status":     status,
+		"action":     mockString(body["action"]),
+		"project_id": s.project,
+		"region_id":  s.region,
+		"user_id":    1,
+		"volumes":    []interface{}{},
+		"schedules":  []interface{}{},
+	}
+	schedules := make([]interface{}, 0)
+	for _, raw := range mockList(body["schedules"]) {
+		schedule := mockCopy(mockMap(raw))
+		schedule["id"] = c.api.uuid()
+		schedule["owner"] = "lifecycle_policy"
+		schedule["owner_id"] = obj["id"]
+		schedule["user_id"] = 1
+		if _, ok := schedule["retention_time"]; !ok {
+			schedule["retention_time"] = nil
+		}
+		if _, ok := schedule["resource_name_template"]; !ok {
+			schedule["resource_name_template"] = "reserve snap of the volume {volume_id}"
+		}
+		schedules = append(schedules, schedule)
+	}
+	obj["schedules"] = schedules
+	if err := c.lifecyclePolicyVolumes(obj, mockList(body["volume_ids"]), true); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (c *mockCloud) lifecyclePolicyVolumes(obj map[string]interface{}, ids []interface{}, add bool) error {
+	volumes := make([]interface{}, 0)
+	remove := make(map[string]bool)
+	for _, id := range ids {
+		remove[mockString(id)] = true
+	}
+	for _, v := range mockList(obj["volumes"]) {
+		if add || !remove[mockString(mockMap(v)["volume_id"])] {
+			volumes = append(volumes, v)
+		}
+	}
+	if add {
+		for _, id := range ids {
+			vol := c.objects["volumes"][mockString(id)]
+			if vol == nil {
+				return fmt.Errorf("volume %s not found", id)
+			}
+			volumes = append(volumes, map[string]interface{}{"volume_id": vol["id"], "volume_name": vol["name"]})
+		}
+	}
+	obj["volumes"] = volumes
+	return nil
+}
+
+func mockCloudLifecyclePolicyVolumes(add bool) mockCloudAction {
+	return func(c *mockCloud, w http.ResponseWriter, r *http.Request, _ mockScope, obj map[string]interface{}, _ []string) {
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := c.lifecyclePolicyVolumes(obj, mockList(body["volume_ids"]), add); err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		mockWriteJSON(w, http.StatusOK, c.render("lifecycle_policy", obj))
+	}
+}
+
+// DDoS protection profiles
+
+func mockCloudDDoSProfileCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	if mockString(body["ip_address"]) == "" || mockString(body["bm_instance_id"]) == "" {
+		return nil, fmt.Errorf("ip_address and bm_instance_id are required")
+	}
+	obj := map[string]interface{}{
+		"id":         c.api.nextID(),
+		"project_id": s.project,
+		"region_id":  s.region,
+		"site":       "ED",
+		"options":    map[string]interface{}{"price": "0", "bgp": true, "active": true},
+		"protocols":  []interface{}{map[string]interface{}{"port": "80", "protocols": []interface{}{"TCP"}}},
+	}
+	mockDDoSProfileSet(obj, body)
+	return obj, nil
+}
+
+func mockDDoSProfileSet(obj, body map[string]interface{}) {
+	obj["ip_address"] = mockString(body["ip_address"])
+	obj["profile_template"] = mockInt(body["profile_template"])
+	obj["_bm_instance_id"] = mockString(body["bm_instance_id"])
+	fields := make([]interface{}, 0)
+	for i, raw := range mockList(body["fields"]) {
+		field := mockCopy(mockMap(raw))
+		field["id"] = i + 1
+		field["name"] = fmt.Sprintf("field %d", mockInt(field["base_field"]))
+		fields = append(fields, field)
+	}
+	obj["fields"] = fields
+}
+
+func mockCloudDDoSProfileUpdate(c *mockCloud, s mockScope, obj, body map[string]interface{}) (interface{}, int, error) {
+	mockDDoSProfileSet(obj, body)
+	return c.task(s, nil), http.StatusOK, nil
+}
+
+func mockCloudDDoSProfileActivate(c *mockCloud, w http.ResponseWriter, r *http.Request, s mockScope, obj map[string]interface{}, _ []string) {
+	body, err := mockReadJSON(r)
+	if err != nil {
+		c.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	options := mockMap(obj["options"])
+	options["bgp"] = mockBool(body["bgp"])
+	options["active"] = mockBool(body["active"])
+	mockWriteJSON(w, http.StatusOK, c.task(s, nil))
+}
+
+// FaaS namespaces
+
+func mockCloudFaaSNamespaceCreate(c *mockCloud, s mockScope, body map[string]interface{}) (map[string]interface{}, error) {
+	name := mockString(body["name"])
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	for _, ns := range c.objects["faas/namespaces"] {
+		if ns["name"] == name && mockInt(ns["project_id"]) == s.project && mockInt(ns["region_id"]) == s.region {
+			return nil, fmt.Errorf("namespace %s already exists", name)
+		}
+	}
+	return map[string]interface{}{
+		"name":        name,
+		"description": mockString(body["description"]),
+		"status":      "active",
+		"envs":        mockMetadataFromBody(body["envs"]),
+		"created_at":  mockCloudTimeZZ,
+		"project_id":  s.project,
+		"region_id":   s.region,
+	}, nil
+}
+
+func mockCloudFaaSNamespaceUpdate(c *mockCloud, s mockScope, obj, body map[string]interface{}) (interface{}, int, error) {
+	if envs, ok := body["envs"]; ok {
+		obj["envs"] = mockMetadataFromBody(envs)
+	}
+	if description, ok := body["description"]; ok {
+		obj["description"] = mockString(description)
+	}
+	return c.task(s, nil), http.StatusOK, nil
+}
+
+func mockCloudFaaSNamespaceRemove(c *mockCloud, obj map[string]interface{}) {
+	prefix := fmt.Sprintf("%d/%d/%s/", mockInt(obj["project_id"]), mockInt(obj["region_id"]), obj["name"])
+	for key := range c.objects["faas_functions"] {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.objects["faas_functions"], key)
+		}
+	}
+}
+
+func mockCloudFaaSNamespaceRender(c *mockCloud, obj, res map[string]interface{}) {
+	prefix := fmt.Sprintf("%d/%d/%s/", mockInt(obj["project_id"]), mockInt(obj["region_id"]), obj["name"])
+	functions := make([]interface{}, 0)
+	total, ready := 0, 0
+	for _, key := range mockSortedKeys(c.objects["faas_functions"]) {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		f := c.objects["faas_functions"][key]
+		functions = append(functions, mockCopy(f))
+		total += mockInt(mockMap(f["deploy_status"])["total"])
+		ready += mockInt(mockMap(f["deploy_status"])["ready"])
+	}
+	res["functions"] = functions
+	res["functions_deploy_status"] = map[string]interface{}{"total": total, "ready": ready}
+}
+
+// LaaS topics
+
+// serveLaaS serves the topics of /v1/laas/{project}/{region}/topics.
+func (c *mockCloud) serveLaaS(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) < 3 || segs[2] != "topics" {
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+		return
+	}
+	topics := c.objects["laas_topics"]
+	prefix := segs[0] + "/" + segs[1] + "/"
+	rest := segs[3:]
+
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			items := make([]interface{}, 0)
+			for _, key := range mockSortedKeys(topics) {
+				if strings.HasPrefix(key, prefix) {
+					items = append(items, topics[key])
+				}
+			}
+			mockWriteJSON(w, http.StatusOK, c.page(items))
+		case http.MethodPut:
+			body, err := mockReadJSON(r)
+			if err != nil {
+				c.fail(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			name := mockString(body["name"])
+			if _, ok := topics[prefix+name]; ok {
+				c.fail(w, http.StatusConflict, "topic %s already exists", name)
+				return
+			}
+			topics[prefix+name] = map[string]interface{}{"name": name}
+			mockWriteJSON(w, http.StatusCreated, topics[prefix+name])
+		default:
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+		return
+	}
+
+	if _, ok := topics[prefix+rest[0]]; !ok || len(rest) != 1 || r.Method != http.MethodDelete {
+		c.fail(w, http.StatusNotFound, "topic %s not found", rest[0])
+		return
+	}
+	delete(topics, prefix+rest[0])
+	mockWriteJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,305 @@
+package gcore
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// mockDNS is the DNS API part of mockAPI. Zones are keyed by name and hold
+// their rrsets keyed by "name/type".
+type mockDNS struct {
+	api   *mockAPI
+	zones map[string]*mockDNSZone
+}
+
+type mockDNSZone struct {
+	id     int
+	name   string
+	rrsets map[string]map[string]interface{}
+	dnssec bool
+}
+
+func newMockDNSZone(id int, name string) *mockDNSZone {
+	return &mockDNSZone{id: id, name: name, rrsets: make(map[string]map[string]interface{})}
+}
+
+func newMockDNS(api *mockAPI) *mockDNS {
+	return &mockDNS{api: api, zones: make(map[string]*mockDNSZone)}
+}
+
+// SeedZone creates a zone and returns its name.
+func (c *mockDNS) SeedZone(name string) string {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	c.zones[name] = newMockDNSZone(c.api.nextID(), name)
+	return name
+}
+
+// DNSSEC reports whether DNSSEC of the zone is enabled.
+func (c *mockDNS) DNSSEC(name string) bool {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	return c.zones[name] != nil && c.zones[name].dnssec
+}
+
+// SeedRRSet adds a rrset to the zone as if it was created by another client,
+// each record is given as its content parts.
+func (c *mockDNS) SeedRRSet(zone, name, rType string, ttl int, records ...[]interface{}) {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	z := c.zones[zone]
+	if z == nil {
+		c.api.t.Fatalf("seed rrset: zone %s not found", zone)
+	}
+	rrs := make([]interface{}, 0, len(records))
+	for _, content := range records {
+		rrs = append(rrs, map[string]interface{}{"content": content, "enabled": true, "meta": map[string]interface{}{}})
+	}
+	z.rrsets[name+"/"+rType] = map[string]interface{}{
+		"ttl":              ttl,
+		"resource_records": rrs,
+		"filters":          []interface{}{},
+	}
+}
+
+// HasZone reports whether the zone exists.
+func (c *mockDNS) HasZone(name string) bool {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	return c.zones[name] != nil
+}
+
+// RRSet returns a copy of the stored rrset, or nil.
+func (c *mockDNS) RRSet(zone, name, rType string) map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	z := c.zones[zone]
+	if z == nil || z.rrsets[name+"/"+rType] == nil {
+		return nil
+	}
+	return mockCopy(z.rrsets[name+"/"+rType])
+}
+
+func (c *mockDNS) fail(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	mockWriteJSON(w, status, map[string]interface{}{"error": fmt.Sprintf(format, args...)})
+}
+
+func (c *mockDNS) serve(w http.ResponseWriter, r *http.Request) {
+	segs := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/dns/"), "/"), "/")
+	if len(segs) < 2 || segs[0] != "v2" || segs[1] != "zones" {
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+		return
+	}
+	segs = segs[2:]
+
+	switch len(segs) {
+	case 0:
+		c.serveZones(w, r)
+	case 1:
+		c.serveZone(w, r, segs[0])
+	case 2:
+		c.serveZoneAction(w, r, segs[0], segs[1])
+	case 3:
+		c.serveRRSet(w, r, segs[0], segs[1], strings.ToUpper(segs[2]))
+	default:
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+	}
+}
+
+func (c *mockDNS) serveZones(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		names := make([]string, 0, len(c.zones))
+		for name := range c.zones {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		zones := make([]interface{}, 0, len(names))
+		for _, name := range names {
+			zones = append(zones, map[string]interface{}{"name": name})
+		}
+		mockWriteJSON(w, http.StatusOK, map[string]interface{}{"zones": zones})
+	case http.MethodPost:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		name := strings.Trim(mockString(body["name"]), ".")
+		if name == "" || !strings.Contains(name, ".") {
+			c.fail(w, http.StatusBadRequest, "invalid zone name %q", name)
+			return
+		}
+		if c.zones[name] != nil {
+			c.fail(w, http.StatusConflict, "zone %s already exists", name)
+			return
+		}
+		z := newMockDNSZone(c.api.nextID(), name)
+		c.zones[name] = z
+		mockWriteJSON(w, http.StatusOK, map[string]interface{}{"id": z.id})
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+func (c *mockDNS) serveZone(w http.ResponseWriter, r *http.Request, name string) {
+	z := c.zones[name]
+	if z == nil {
+		c.fail(w, http.StatusNotFound, "zone %s not found", name)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		records := make([]interface{}, 0, len(z.rrsets))
+		for _, key := range mockSortedKeys(z.rrsets) {
+			rrset := z.rrsets[key]
+			answers := make([]interface{}, 0)
+			for _, rr := range mockList(rrset["resource_records"]) {
+				answers = append(answers, mockDNSContent(mockMap(rr)["content"]))
+			}
+			parts := strings.SplitN(key, "/", 2)
+			records = append(records, map[string]interface{}{
+				"name":          parts[0],
+				"type":          parts[1],
+				"ttl":           mockInt(rrset["ttl"]),
+				"short_answers": answers,
+			})
+		}
+		mockWriteJSON(w, http.StatusOK, map[string]interface{}{"name": z.name, "records": records})
+	case http.MethodDelete:
+		delete(c.zones, name)
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+// serveZoneAction serves the DNSSEC of a zone.
+func (c *mockDNS) serveZoneAction(w http.ResponseWriter, r *http.Request, name, action string) {
+	z := c.zones[name]
+	if z == nil {
+		c.fail(w, http.StatusNotFound, "zone %s not found", name)
+		return
+	}
+	if action != "dnssec" {
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+		return
+	}
+	switch r.Method {
+	case http.MethodPatch:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		z.dnssec = body["enabled"] == true
+		if !z.dnssec {
+			mockWriteJSON(w, http.StatusOK, map[string]interface{}{})
+			return
+		}
+		mockWriteJSON(w, http.StatusOK, mockDNSSECDS(z))
+	case http.MethodGet:
+		if !z.dnssec {
+			c.fail(w, http.StatusBadRequest, "dnssec of zone %s is disabled", name)
+			return
+		}
+		mockWriteJSON(w, http.StatusOK, mockDNSSECDS(z))
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+func mockDNSSECDS(z *mockDNSZone) map[string]interface{} {
+	return map[string]interface{}{
+		"ds":          fmt.Sprintf("%s. 3600 IN DS %d 13 2 ABCDEF", z.name, 10000+z.id),
+		"key_tag":     10000 + z.id,
+		"algorithm":   "13",
+		"digest_type": "2",
+		"digest":      "ABCDEF",
+	}
+}
+
+func (c *mockDNS) serveRRSet(w http.ResponseWriter, r *http.Request, zone, name, rType string) {
+	z := c.zones[zone]
+	if z == nil {
+		c.fail(w, http.StatusNotFound, "zone %s not found", zone)
+		return
+	}
+	if name != zone && !strings.HasSuffix(name, "."+zone) {
+		c.fail(w, http.StatusBadRequest, "record %s is out of zone %s", name, zone)
+		return
+	}
+	key := name + "/" + rType
+	rrset := z.rrsets[key]
+
+	switch r.Method {
+	case http.MethodGet:
+		if rrset == nil {
+			c.fail(w, http.StatusNotFound, "rrset %s %s not found", name, rType)
+			return
+		}
+		mockWriteJSON(w, http.StatusOK, rrset)
+	case http.MethodPost, http.MethodPut:
+		if r.Method == http.MethodPost && rrset != nil {
+			c.fail(w, http.StatusConflict, "rrset %s %s already exists", name, rType)
+			return
+		}
+		if r.Method == http.MethodPut && rrset == nil {
+			c.fail(w, http.StatusNotFound, "rrset %s %s not found", name, rType)
+			return
+		}
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		records := mockList(body["resource_records"])
+		if len(records) == 0 {
+			c.fail(w, http.StatusBadRequest, "resource_records are required")
+			return
+		}
+		for _, raw := range records {
+			rr := mockMap(raw)
+			if len(mockList(rr["content"])) == 0 {
+				c.fail(w, http.StatusBadRequest, "record content is required")
+				return
+			}
+			if rr["meta"] == nil {
+				rr["meta"] = map[string]interface{}{}
+			}
+		}
+		ttl := mockInt(body["ttl"])
+		if ttl == 0 {
+			ttl = 300
+		}
+		filters := mockList(body["filters"])
+		if filters == nil {
+			filters = []interface{}{}
+		}
+		z.rrsets[key] = map[string]interface{}{
+			"ttl":              ttl,
+			"resource_records": records,
+			"filters":          filters,
+		}
+		mockWriteJSON(w, http.StatusOK, map[string]interface{}{})
+	case http.MethodDelete:
+		if rrset == nil {
+			c.fail(w, http.StatusNotFound, "rrset %s %s not found", name, rType)
+			return
+		}
+		delete(z.rrsets, key)
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+func mockDNSContent(v interface{}) string {
+	parts := make([]string, 0)
+	for _, p := range mockList(v) {
+		parts = append(parts, fmt.Sprint(p))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,583 @@
+package gcore
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const mockStorageClientID = 1
+
+// mockStorage is the Storage API part of mockAPI.
+type mockStorage struct {
+	api       *mockAPI
+	storages  map[int]map[string]interface{}
+	keys      map[int]map[string]interface{}
+	buckets   map[int]map[string]map[string]interface{}
+	objects   map[int]map[string]*mockS3Object
+	locations []map[string]interface{}
+}
+
+func newMockStorage(api *mockAPI) *mockStorage {
+	locations := make([]map[string]interface{}, 0)
+	for i, name := range []string{"s-ed1", "s-darz1", "s-ws1", "s-dt2", "s-drc2", "ams", "sin", "fra", "mia"} {
+		typ := "s3"
+		if !strings.HasPrefix(name, "s-") {
+			typ = "sftp"
+		}
+		locations = append(locations, map[string]interface{}{
+			"id":                    i + 1,
+			"name":                  name,
+			"address":               name + ".cloud.example.com",
+			"allow_for_new_storage": "allow",
+			"type":                  typ,
+			"title":                 map[string]interface{}{"en": name},
+		})
+	}
+	return &mockStorage{
+		api:       api,
+		storages:  make(map[int]map[string]interface{}),
+		keys:      make(map[int]map[string]interface{}),
+		buckets:   make(map[int]map[string]map[string]interface{}),
+		objects:   make(map[int]map[string]*mockS3Object),
+		locations: locations,
+	}
+}
+
+// Storage returns a copy of the stored storage, or nil.
+func (c *mockStorage) Storage(id int) map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	if s := c.storages[id]; s != nil {
+		return mockCopy(s)
+	}
+	return nil
+}
+
+// Bucket returns a copy of the stored bucket, or nil.
+func (c *mockStorage) Bucket(storageID int, name string) map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	if b := c.buckets[storageID][name]; b != nil {
+		return mockCopy(b)
+	}
+	return nil
+}
+
+// Key returns a copy of the stored SFTP key, or nil.
+func (c *mockStorage) Key(id int) map[string]interface{} {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	if k := c.keys[id]; k != nil {
+		return mockCopy(k)
+	}
+	return nil
+}
+
+// SeedStorage creates a storage and returns its ID.
+func (c *mockStorage) SeedStorage(typ, location, name string) int {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	s, err := c.createStorage(map[string]interface{}{"type": typ, "location": location, "name": name})
+	if err != nil {
+		c.api.t.Fatalf("seed storage: %s", err)
+	}
+	return mockInt(s["id"])
+}
+
+// SeedLocation adds a location of the type, allow is allow or deny for new
+// storages.
+func (c *mockStorage) SeedLocation(name, typ, allow string) {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	c.locations = append(c.locations, map[string]interface{}{
+		"id":                    len(c.locations) + 1,
+		"name":                  name,
+		"address":               name + ".cloud.example.com",
+		"allow_for_new_storage": allow,
+		"type":                  typ,
+		"title":                 map[string]interface{}{"en": name},
+	})
+}
+
+// SeedBucket creates the bucket of the storage if needed and sets its
+// attributes: lifecycle days, cors origins and policy.
+func (c *mockStorage) SeedBucket(storageID int, name string, attrs map[string]interface{}) {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	buckets := c.buckets[storageID]
+	if buckets == nil {
+		c.api.t.Fatalf("seed bucket: storage %d not found", storageID)
+	}
+	if buckets[name] == nil {
+		buckets[name] = map[string]interface{}{"name": name, "lifecycle": 0, "cors": []interface{}{}, "policy": "private"}
+	}
+	for k, v := range attrs {
+		buckets[name][k] = v
+	}
+}
+
+func (c *mockStorage) fail(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	mockWriteJSON(w, status, map[string]interface{}{"error": fmt.Sprintf(format, args...)})
+}
+
+func (c *mockStorage) serve(w http.ResponseWriter, r *http.Request) {
+	p := strings.Trim(strings.TrimPrefix(r.URL.Path, "/storage/"), "/")
+	segs := strings.Split(p, "/")
+	if len(segs) < 3 || segs[0] != "provisioning" {
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+		return
+	}
+	version, collection, rest := segs[1], segs[2], segs[3:]
+
+	switch {
+	case collection == "location" && len(rest) == 0 && r.Method == http.MethodGet:
+		list := make([]interface{}, 0, len(c.locations))
+		for _, l := range c.locations {
+			list = append(list, l)
+		}
+		mockWriteJSON(w, http.StatusOK, list)
+	case collection == "key":
+		c.serveKeys(w, r, version, rest)
+	case collection == "storage" && len(rest) == 0:
+		c.serveStorages(w, r, version)
+	case collection == "storage":
+		id, err := strconv.Atoi(rest[0])
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, "invalid id %q", rest[0])
+			return
+		}
+		s := c.storages[id]
+		if s == nil || s["deleted_at"] != nil {
+			c.fail(w, http.StatusNotFound, "storage %d not found", id)
+			return
+		}
+		c.serveStorage(w, r, s, rest[1:])
+	default:
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+	}
+}
+
+func (c *mockStorage) createStorage(body map[string]interface{}) (map[string]interface{}, error) {
+	typ := mockString(body["type"])
+	if typ != "s3" && typ != "sftp" {
+		return nil, fmt.Errorf("invalid type %q", typ)
+	}
+	location := mockString(body["location"])
+	known := false
+	for _, l := range c.locations {
+		if l["name"] == location && l["type"] == typ && l["allow_for_new_storage"] != "deny" {
+			known = true
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown location %q", location)
+	}
+	name := mockString(body["name"])
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	fullName := fmt.Sprintf("%d-%s", mockStorageClientID, name)
+	for _, s := range c.storages {
+		if s["name"] == fullName && s["deleted_at"] == nil {
+			return nil, fmt.Errorf("storage %s already exists", fullName)
+		}
+	}
+
+	id := c.api.nextID()
+	credentials := map[string]interface{}{"keys": []interface{}{}}
+	if typ == "s3" {
+		credentials["s3"] = c.s3Keys(id)
+	}
+	if mockBool(body["generate_sftp_password"]) {
+		credentials["sftp_password"] = fmt.Sprintf("generated-%d", id)
+	} else if pw := mockString(body["sftp_password"]); pw != "" {
+		credentials["sftp_password"] = pw
+	}
+
+	s := map[string]interface{}{
+		"id":                  id,
+		"client_id":           mockStorageClientID,
+		"name":                fullName,
+		"type":                typ,
+		"location":            location,
+		"address":             location + ".cloud.example.com",
+		"created_at":          "2021-01-01 00:00:00",
+		"deleted_at":          nil,
+		"provisioning_status": "ok",
+		"server_alias":        "",
+		"expires":             "",
+		"rewrite_rules":       map[string]interface{}{},
+		"credentials":         credentials,
+		"_keys":               []interface{}{},
+	}
+	c.storages[id] = s
+	c.buckets[id] = make(map[string]map[string]interface{})
+	return s, nil
+}
+
+func (c *mockStorage) s3Keys(id int) map[string]interface{} {
+	n := c.api.nextID()
+	return map[string]interface{}{
+		"access_key": fmt.Sprintf("ACCESS%06d%06d", id, n),
+		"secret_key": fmt.Sprintf("secret%06d%06d", id, n),
+	}
+}
+
+// render returns the storage as the API shows it in lists: credentials
+// besides the linked keys are only returned on create and on update of
+// the credentials.
+func (c *mockStorage) render(s map[string]interface{}, withSecrets bool) map[string]interface{} {
+	res := mockCopy(s)
+	keys := make([]interface{}, 0)
+	for _, id := range mockList(s["_keys"]) {
+		if k := c.keys[mockInt(id)]; k != nil {
+			keys = append(keys, mockCopy(k))
+		}
+	}
+	credentials := map[string]interface{}{"keys": keys}
+	if withSecrets {
+		for k, v := range mockMap(s["credentials"]) {
+			if k != "keys" {
+				credentials[k] = v
+			}
+		}
+	}
+	res["credentials"] = credentials
+	return res
+}
+
+func (c *mockStorage) serveStorages(w http.ResponseWriter, r *http.Request, version string) {
+	switch r.Method {
+	case http.MethodPut:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s, err := c.createStorage(body)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		mockWriteJSON(w, http.StatusOK, c.render(s, true))
+	case http.MethodGet:
+		q := r.URL.Query()
+		showDeleted, _ := strconv.ParseBool(q.Get("show_deleted"))
+		ids := make([]int, 0, len(c.storages))
+		for id := range c.storages {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		list := make([]interface{}, 0)
+		for _, id := range ids {
+			s := c.storages[id]
+			if s["deleted_at"] != nil && !showDeleted {
+				continue
+			}
+			if v := q.Get("id"); v != "" && v != strconv.Itoa(id) {
+				continue
+			}
+			// The API matches names partially, so the short name known to
+			// the provider finds the client prefixed storage name.
+			if v := q.Get("name"); v != "" && !strings.Contains(mockString(s["name"]), v) {
+				continue
+			}
+			list = append(list, c.render(s, false))
+		}
+		if version == "v2" {
+			mockWriteJSON(w, http.StatusOK, map[string]interface{}{"data": list})
+			return
+		}
+		mockWriteJSON(w, http.StatusOK, list)
+	default:
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+func (c *mockStorage) serveStorage(w http.ResponseWriter, r *http.Request, s map[string]interface{}, rest []string) {
+	id := mockInt(s["id"])
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			mockWriteJSON(w, http.StatusOK, c.render(s, false))
+		case http.MethodPost:
+			body, err := mockReadJSON(r)
+			if err != nil {
+				c.fail(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if v, ok := body["expires"]; ok {
+				s["expires"] = mockString(v)
+			}
+			if v, ok := body["server_alias"]; ok {
+				s["server_alias"] = mockString(v)
+			}
+			mockWriteJSON(w, http.StatusOK, c.render(s, false))
+		case http.MethodDelete:
+			s["deleted_at"] = "2021-01-02 00:00:00"
+			delete(c.buckets, id)
+			mockWriteJSON(w, http.StatusNoContent, nil)
+		default:
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+		return
+	}
+
+	switch {
+	case rest[0] == "credentials" && r.Method == http.MethodPost:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		credentials := mockMap(s["credentials"])
+		if mockBool(body["generate_s3_keys"]) {
+			if s["type"] != "s3" {
+				c.fail(w, http.StatusBadRequest, "s3 keys are available for s3 storages only")
+				return
+			}
+			credentials["s3"] = c.s3Keys(id)
+		}
+		if mockBool(body["delete_sftp_password"]) {
+			delete(credentials, "sftp_password")
+		}
+		if mockBool(body["generate_sftp_password"]) {
+			credentials["sftp_password"] = fmt.Sprintf("generated-%d", c.api.nextID())
+		} else if pw := mockString(body["sftp_password"]); pw != "" {
+			credentials["sftp_password"] = pw
+		}
+		if mockBool(body["reset_sftp_keys"]) {
+			s["_keys"] = []interface{}{}
+		}
+		mockWriteJSON(w, http.StatusOK, c.render(s, true))
+	case rest[0] == "key" && len(rest) == 3 && r.Method == http.MethodPost:
+		keyID, _ := strconv.Atoi(rest[1])
+		if c.keys[keyID] == nil {
+			c.fail(w, http.StatusNotFound, "key %d not found", keyID)
+			return
+		}
+		keys := make([]interface{}, 0)
+		for _, k := range mockList(s["_keys"]) {
+			if mockInt(k) != keyID {
+				keys = append(keys, k)
+			}
+		}
+		switch rest[2] {
+		case "link":
+			keys = append(keys, keyID)
+		case "unlink":
+		default:
+			c.fail(w, http.StatusNotFound, "unknown key action %s", rest[2])
+			return
+		}
+		s["_keys"] = keys
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	case rest[0] == "s3" && s["type"] != "s3":
+		c.fail(w, http.StatusBadRequest, "buckets are available for s3 storages only")
+	case rest[0] == "s3" && len(rest) == 2 && rest[1] == "buckets" && r.Method == http.MethodGet:
+		buckets := c.buckets[id]
+		names := make([]string, 0, len(buckets))
+		for name := range buckets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		list := make([]interface{}, 0, len(names))
+		for _, name := range names {
+			b := map[string]interface{}{"name": name}
+			if days := mockInt(buckets[name]["lifecycle"]); days > 0 {
+				b["lifecycle"] = days
+			}
+			list = append(list, b)
+		}
+		mockWriteJSON(w, http.StatusOK, map[string]interface{}{"data": list})
+	case rest[0] == "s3" && len(rest) >= 3 && rest[1] == "bucket":
+		c.serveBucket(w, r, id, rest[2], rest[3:])
+	default:
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+	}
+}
+
+func (c *mockStorage) serveBucket(w http.ResponseWriter, r *http.Request, storageID int, name string, rest []string) {
+	buckets := c.buckets[storageID]
+	bucket := buckets[name]
+
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodPost:
+			if bucket != nil {
+				c.fail(w, http.StatusBadRequest, "bucket %s already exists", name)
+				return
+			}
+			if len(name) < 3 {
+				c.fail(w, http.StatusBadRequest, "invalid bucket name %q", name)
+				return
+			}
+			buckets[name] = map[string]interface{}{
+				"name":      name,
+				"lifecycle": 0,
+				"cors":      []interface{}{},
+				"policy":    "private",
+			}
+			mockWriteJSON(w, http.StatusNoContent, nil)
+		case http.MethodDelete:
+			if bucket == nil {
+				c.fail(w, http.StatusNotFound, "bucket %s not found", name)
+				return
+			}
+			delete(buckets, name)
+			mockWriteJSON(w, http.StatusNoContent, nil)
+		default:
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+		return
+	}
+
+	if bucket == nil {
+		c.fail(w, http.StatusNotFound, "bucket %s not found", name)
+		return
+	}
+	switch {
+	case rest[0] == "cors" && r.Method == http.MethodGet:
+		origins := make([]string, 0)
+		for _, o := range mockList(bucket["cors"]) {
+			origins = append(origins, mockString(o))
+		}
+		mockWriteJSON(w, http.StatusOK, map[string]interface{}{"data": strings.Join(origins, ",")})
+	case rest[0] == "cors" && r.Method == http.MethodPost:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		bucket["cors"] = mockStrings(body["allowedOrigins"])
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	case rest[0] == "lifecycle" && r.Method == http.MethodPost:
+		body, err := mockReadJSON(r)
+		if err != nil {
+			c.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		days := mockInt(body["expiration_days"])
+		if days <= 0 {
+			c.fail(w, http.StatusBadRequest, "expiration_days must be positive")
+			return
+		}
+		bucket["lifecycle"] = days
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	case rest[0] == "lifecycle" && r.Method == http.MethodDelete:
+		bucket["lifecycle"] = 0
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	case rest[0] == "policy" && r.Method == http.MethodPost:
+		bucket["policy"] = "public-read"
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	case rest[0] == "policy" && r.Method == http.MethodDelete:
+		bucket["policy"] = "private"
+		mockWriteJSON(w, http.StatusNoContent, nil)
+	default:
+		c.fail(w, http.StatusNotFound, "unknown path %s", r.URL.Path)
+	}
+}
+
+func (c *mockStorage) serveKeys(w http.ResponseWriter, r *http.Request, version string, rest []string) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodPut:
+			body, err := mockReadJSON(r)
+			if err != nil {
+				c.fail(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			name := mockString(body["name"])
+			if name == "" || !strings.HasPrefix(mockString(body["key"]), "ssh-") {
+				c.fail(w, http.StatusBadRequest, "name and a valid ssh public key are required")
+				return
+			}
+			for _, k := range c.keys {
+				if k["name"] == name {
+					c.fail(w, http.StatusConflict, "key %s already exists", name)
+					return
+				}
+			}
+			id := c.api.nextID()
+			c.keys[id] = map[string]interface{}{
+				"id":         id,
+				"name":       name,
+				"created_at": "2021-01-01 00:00:00",
+				"_key":       body["key"],
+			}
+			mockWriteJSON(w, http.StatusOK, mockCopy(c.keys[id]))
+		case http.MethodGet:
+			q := r.URL.Query()
+			ids := make([]int, 0, len(c.keys))
+			for id := range c.keys {
+				ids = append(ids, id)
+			}
+			sort.Ints(ids)
+			list := make([]interface{}, 0)
+			for _, id := range ids {
+				if v := q.Get("id"); v != "" && v != strconv.Itoa(id) {
+					continue
+				}
+				if v := q.Get("name"); v != "" && v != mockString(c.keys[id]["name"]) {
+					continue
+				}
+				list = append(list, mockCopy(c.keys[id]))
+			}
+			if version == "v2" {
+				mockWriteJSON(w, http.StatusOK, map[string]interface{}{"data": list})
+				return
+			}
+			mockWriteJSON(w, http.StatusOK, list)
+		default:
+			c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		}
+		return
+	}
+
+	id, _ := strconv.Atoi(rest[0])
+	if c.keys[id] == nil {
+		c.fail(w, http.StatusNotFound, "key %d not found", id)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		c.fail(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+	delete(c.keys, id)
+	for _, s := range c.storages {
+		keys := make([]interface{}, 0)
+		for _, k := range mockList(s["_keys"]) {
+			if mockInt(k) != id {
+				keys = append(keys, k)
+			}
+		}
+		s["_keys"] = keys
+	}
+	mockWriteJSON(w, http.StatusNoContent, nil)
+}
+
+// unitStorageCheckDestroy checks the storages of resourceType are deleted
+// from the fake API.
+func unitStorageCheckDestroy(api *mockAPI, resourceType string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			id, err := strconv.Atoi(rs.Primary.Attributes[StorageSchemaId])
+			if err != nil {
+				return err
+			}
+			if st := api.storage.Storage(id); st != nil && st["deleted_at"] == nil {
+				return fmt.Errorf("storage %d still exists", id)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,166 @@
+package gcore
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// mockS3Object is an object of a bucket of the S3 API.
+type mockS3Object struct {
+	Body         []byte
+	ContentType  string
+	CacheControl string
+}
+
+// Object returns a copy of the stored object, or nil.
+func (c *mockStorage) Object(storageID int, bucket, key string) *mockS3Object {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	if o := c.objects[storageID][bucket+"/"+key]; o != nil {
+		res := *o
+		res.Body = append([]byte(nil), o.Body...)
+		return &res
+	}
+	return nil
+}
+
+// SeedObject stores the object in the bucket, e.g. to change it behind
+// the provider's back.
+func (c *mockStorage) SeedObject(storageID int, bucket, key string, o mockS3Object) {
+	c.api.mu.Lock()
+	defer c.api.mu.Unlock()
+	if c.objects[storageID] == nil {
+		c.objects[storageID] = make(map[string]*mockS3Object)
+	}
+	c.objects[storageID][bucket+"/"+key] = &o
+}
+
+func (c *mockStorage) s3Fail(w http.ResponseWriter, status int, code, format string, args ...interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}{Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+// serveS3 serves the objects of the buckets of the storages in the path
+// style: /s3/<bucket>/<key>. The requests should be signed by the S3 keys
+// of the storage of the bucket.
+func (c *mockStorage) serveS3(w http.ResponseWriter, r *http.Request) {
+	storageID, code, err := c.s3Authorize(r)
+	if err != nil {
+		c.s3Fail(w, http.StatusForbidden, code, "%s", err)
+		return
+	}
+	segs := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/s3/"), "/", 2)
+	if len(segs) != 2 || segs[0] == "" || segs[1] == "" {
+		c.s3Fail(w, http.StatusBadRequest, "InvalidRequest", "unsupported path %s", r.URL.Path)
+		return
+	}
+	bucket, key := segs[0], segs[1]
+	if c.buckets[storageID][bucket] == nil {
+		c.s3Fail(w, http.StatusNotFound, "NoSuchBucket", "bucket %s not found", bucket)
+		return
+	}
+	if c.objects[storageID] == nil {
+		c.objects[storageID] = make(map[string]*mockS3Object)
+	}
+	objects := c.objects[storageID]
+	id := bucket + "/" + key
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := ioutil.ReadAll(r.Body)
+		hash := sha256.Sum256(body)
+		if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(hash[:]) {
+			c.s3Fail(w, http.StatusBadRequest, "XAmzContentSHA256Mismatch", "the content hash doesn't match the body")
+			return
+		}
+		o := &mockS3Object{Body: body, ContentType: r.Header.Get("Content-Type"), CacheControl: r.Header.Get("Cache-Control")}
+		if o.ContentType == "" {
+			o.ContentType = "binary/octet-stream"
+		}
+		objects[id] = o
+		w.Header().Set("ETag", o.etag())
+		w.WriteHeader(http.StatusOK)
+	case http.MethodHead:
+		o := objects[id]
+		if o == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", o.etag())
+		w.Header().Set("Content-Type", o.ContentType)
+		if o.CacheControl != "" {
+			w.Header().Set("Cache-Control", o.CacheControl)
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(o.Body)))
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(objects, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		c.s3Fail(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "unsupported method %s", r.Method)
+	}
+}
+
+func (o *mockS3Object) etag() string {
+	sum := md5.Sum(o.Body)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// s3Authorize checks the signature of the request by the current S3 keys of
+// the storages and returns the storage of the keys, or the S3 error code.
+func (c *mockStorage) s3Authorize(r *http.Request) (int, string, error) {
+	const prefix = "AWS4-HMAC-SHA256 "
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, prefix) {
+		return 0, "AccessDenied", fmt.Errorf("unsupported authorization %q", auth)
+	}
+	fields := make(map[string]string)
+	for _, f := range strings.Split(strings.TrimPrefix(auth, prefix), ", ") {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) == 2 {
+			fields[kv[0]] = kv[1]
+		}
+	}
+	scope := strings.Split(fields["Credential"], "/")
+	if len(scope) != 5 || scope[3] != "s3" || scope[4] != "aws4_request" {
+		return 0, "AuthorizationHeaderMalformed", fmt.Errorf("malformed credential %q", fields["Credential"])
+	}
+
+	for id, s := range c.storages {
+		keys := mockMap(mockMap(s["credentials"])["s3"])
+		if s["deleted_at"] != nil || mockString(keys["access_key"]) != scope[0] {
+			continue
+		}
+		headers := make(map[string]string)
+		for _, name := range strings.Split(fields["SignedHeaders"], ";") {
+			if name == "host" {
+				headers[name] = r.Host
+				continue
+			}
+			headers[name] = strings.Join(r.Header.Values(name), ",")
+		}
+		amzDate := r.Header.Get("X-Amz-Date")
+		if headers["host"] == "" || headers["x-amz-date"] == "" || !strings.HasPrefix(amzDate, scope[1]) {
+			return 0, "AccessDenied", fmt.Errorf("host and x-amz-date of the scope date should be signed")
+		}
+		signer := storageS3Signer{region: scope[2], accessKey: scope[0], secretKey: mockString(keys["secret_key"])}
+		_, signature := signer.signature(r.Method, r.URL.EscapedPath(), r.URL.Query(), headers, amzDate, r.Header.Get("X-Amz-Content-Sha256"))
+		if signature != fields["Signature"] {
+			return 0, "SignatureDoesNotMatch", fmt.Errorf("the request signature doesn't match")
+		}
+		return id, "", nil
+	}
+	return 0, "InvalidAccessKeyId", fmt.Errorf("access key %s not found", scope[0])
+}
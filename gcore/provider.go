@@ -7,15 +7,16 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sync"
+	"time"
 
 	dnssdk "github.com/G-Core/gcore-dns-sdk-go"
 	storageSDK "github.com/G-Core/gcore-storage-sdk-go"
-	gcdn "github.com/G-Core/gcorelabscdn-go"
-	gcdnProvider "github.com/G-Core/gcorelabscdn-go/gcore/provider"
 	gcorecloud "github.com/G-Core/gcorelabscloud-go"
 	gc "github.com/G-Core/gcorelabscloud-go/gcore"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform/version"
 )
 
@@ -120,43 +121,103 @@ func Provider() *schema.Provider {
 				Description: "Client id",
 				DefaultFunc: schema.EnvDefaultFunc("GCORE_CLIENT_ID", ""),
 			},
+			"project_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"project_name"},
+				Description:   "Default project ID of the cloud resources and data sources that don't set project_id or project_name, changing it replaces those resources",
+				DefaultFunc:   schema.EnvDefaultFunc("GCORE_PROJECT_ID", nil),
+			},
+			"project_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
+				Description:   "Default project name of the cloud resources and data sources that don't set project_id or project_name, changing it replaces those resources",
+				DefaultFunc:   schema.EnvDefaultFunc("GCORE_PROJECT_NAME", nil),
+			},
+			"region_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"region_name"},
+				Description:   "Default region ID of the cloud resources and data sources that don't set region_id or region_name, changing it replaces those resources",
+				DefaultFunc:   schema.EnvDefaultFunc("GCORE_REGION_ID", nil),
+			},
+			"region_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
+				Description:   "Default region name of the cloud resources and data sources that don't set region_id or region_name, changing it replaces those resources",
+				DefaultFunc:   schema.EnvDefaultFunc("GCORE_REGION_NAME", nil),
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Maximum number of retries of an idempotent API request failed with a network error, 429 or 5xx status",
+				DefaultFunc:  schema.EnvDefaultFunc("GCORE_MAX_RETRIES", defaultMaxRetries),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_backoff": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Base delay of the jittered exponential backoff between retries, e.g. `500ms`. The Retry-After header of the API wins over it",
+				DefaultFunc:      schema.EnvDefaultFunc("GCORE_RETRY_BACKOFF", defaultRetryBackoff),
+				ValidateDiagFunc: validateDuration,
+			},
+			"requests_per_second": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Maximum rate of the API requests of all products, 0 means unlimited",
+				DefaultFunc:  schema.EnvDefaultFunc("GCORE_REQUESTS_PER_SECOND", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"hash_sensitive_values": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Keep only SHA-256 hashes of the secrets the API never returns, e.g. passwords and private keys, in the state. The secrets of existing resources are hashed on the next refresh",
+				DefaultFunc: schema.EnvDefaultFunc("GCORE_HASH_SENSITIVE_VALUES", false),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"gcore_volume":            resourceVolume(),
-			"gcore_network":           resourceNetwork(),
-			"gcore_subnet":            resourceSubnet(),
-			"gcore_router":            resourceRouter(),
-			"gcore_instance":          resourceInstance(),
-			"gcore_keypair":           resourceKeypair(),
-			"gcore_reservedfixedip":   resourceReservedFixedIP(),
-			"gcore_floatingip":        resourceFloatingIP(),
-			"gcore_loadbalancer":      resourceLoadBalancer(),
-			"gcore_loadbalancerv2":    resourceLoadBalancerV2(),
-			"gcore_lblistener":        resourceLbListener(),
-			"gcore_lbpool":            resourceLBPool(),
-			"gcore_lbmember":          resourceLBMember(),
-			"gcore_securitygroup":     resourceSecurityGroup(),
-			"gcore_baremetal":         resourceBmInstance(),
-			"gcore_snapshot":          resourceSnapshot(),
-			"gcore_servergroup":       resourceServerGroup(),
-			"gcore_k8s":               resourceK8s(),
-			"gcore_k8s_pool":          resourceK8sPool(),
-			"gcore_secret":            resourceSecret(),
-			"gcore_laas_topic":        resourceLaaSTopic(),
-			"gcore_faas_namespace":    resourceFaaSNamespace(),
-			"gcore_faas_function":     resourceFaaSFunction(),
-			"gcore_storage_s3":        resourceStorageS3(),
-			"gcore_storage_s3_bucket": resourceStorageS3Bucket(),
-			DNSZoneResource:           resourceDNSZone(),
-			DNSZoneRecordResource:     resourceDNSZoneRecord(),
-			"gcore_storage_sftp":      resourceStorageSFTP(),
-			"gcore_storage_sftp_key":  resourceStorageSFTPKey(),
-			"gcore_cdn_resource":      resourceCDNResource(),
-			"gcore_cdn_origingroup":   resourceCDNOriginGroup(),
-			"gcore_cdn_rule":          resourceCDNRule(),
-			"gcore_cdn_sslcert":       resourceCDNCert(),
-			lifecyclePolicyResource:   resourceLifecyclePolicy(),
-			"gcore_ddos_protection":   resourceDDoSProtection(),
+			"gcore_volume":                    resourceVolume(),
+			"gcore_network":                   resourceNetwork(),
+			"gcore_subnet":                    resourceSubnet(),
+			"gcore_router":                    resourceRouter(),
+			"gcore_instance":                  resourceInstance(),
+			"gcore_keypair":                   resourceKeypair(),
+			"gcore_reservedfixedip":           resourceReservedFixedIP(),
+			"gcore_reservedfixedip_vip_ports": resourceReservedFixedIPVIPPorts(),
+			"gcore_floatingip":                resourceFloatingIP(),
+			"gcore_loadbalancer":              resourceLoadBalancer(),
+			"gcore_loadbalancerv2":            resourceLoadBalancerV2(),
+			"gcore_lblistener":                resourceLbListener(),
+			"gcore_lbpool":                    resourceLBPool(),
+			"gcore_lbmember":                  resourceLBMember(),
+			"gcore_securitygroup":             resourceSecurityGroup(),
+			"gcore_securitygroup_rule":        resourceSecurityGroupRule(),
+			"gcore_baremetal":                 resourceBmInstance(),
+			"gcore_snapshot":                  resourceSnapshot(),
+			"gcore_servergroup":               resourceServerGroup(),
+			"gcore_k8s":                       resourceK8s(),
+			"gcore_k8s_pool":                  resourceK8sPool(),
+			"gcore_secret":                    resourceSecret(),
+			"gcore_laas_topic":                resourceLaaSTopic(),
+			"gcore_faas_namespace":            resourceFaaSNamespace(),
+			"gcore_faas_function":             resourceFaaSFunction(),
+			"gcore_storage_s3":                resourceStorageS3(),
+			"gcore_storage_s3_bucket":         resourceStorageS3Bucket(),
+			"gcore_storage_s3_object":         resourceStorageS3Object(),
+			DNSZoneResource:                   resourceDNSZone(),
+			DNSZoneRecordResource:             resourceDNSZoneRecord(),
+			"gcore_storage_sftp":              resourceStorageSFTP(),
+			"gcore_storage_sftp_key":          resourceStorageSFTPKey(),
+			"gcore_cdn_resource":              resourceCDNResource(),
+			"gcore_cdn_origingroup":           resourceCDNOriginGroup(),
+			"gcore_cdn_rule":                  resourceCDNRule(),
+			"gcore_cdn_sslcert":               resourceCDNCert(),
+			"gcore_cdn_purge":                 resourceCDNPurge(),
+			"gcore_cdn_prefetch":              resourceCDNPrefetch(),
+			lifecyclePolicyResource:           resourceLifecyclePolicy(),
+			"gcore_ddos_protection":           resourceDDoSProtection(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"gcore_project":               dataSourceProject(),
@@ -175,23 +236,105 @@ func Provider() *schema.Provider {
 			"gcore_floatingip":            dataSourceFloatingIP(),
 			"gcore_storage_s3":            dataSourceStorageS3(),
 			"gcore_storage_s3_bucket":     dataSourceStorageS3Bucket(),
+			"gcore_storage_locations":     dataSourceStorageLocations(),
 			"gcore_storage_sftp":          dataSourceStorageSFTP(),
 			"gcore_storage_sftp_key":      dataSourceStorageSFTPKey(),
 			"gcore_reservedfixedip":       dataSourceReservedFixedIP(),
 			"gcore_servergroup":           dataSourceServerGroup(),
 			"gcore_k8s":                   dataSourceK8s(),
 			"gcore_k8s_pool":              dataSourceK8sPool(),
+			"gcore_k8s_config":            dataSourceK8sConfig(),
 			"gcore_secret":                dataSourceSecret(),
 			"gcore_laas_hosts":            dataSourceLaaSHosts(),
 			"gcore_laas_status":           dataSourceLaaSStatus(),
 			"gcore_faas_namespace":        dataSourceFaaSNamespace(),
 			"gcore_faas_function":         dataSourceFaaSFunction(),
 			"gcore_ddos_profile_template": dataSourceDDoSProfileTemplate(),
+			"gcore_cdn_rules":             dataSourceCDNRules(),
+			DNSZoneFileDataSource:         dataSourceDNSZoneFile(),
+			DNSZoneDataSource:             dataSourceDNSZone(),
+			DNSZoneRecordsDataSource:      dataSourceDNSZoneRecords(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
 }
 
+// authSessionKey identifies the credentials and the endpoints of an auth
+// session.
+type authSessionKey struct {
+	cloudAPI       string
+	platformAPI    string
+	clientID       string
+	username       string
+	password       string
+	permanentToken string
+	policy         retryPolicy
+}
+
+// authSession is an authenticated client with the cache of the lookups done
+// through it. The retry policy is a part of the session, so the clients
+// sharing the session share its rate limit.
+type authSession struct {
+	provider *gcorecloud.ProviderClient
+	cache    *clientCache
+	// transport applies the retry policy of the session to the requests
+	// of all API clients.
+	transport http.RoundTripper
+}
+
+// authSessionRegistry shares the auth sessions between the configurations
+// of the provider, so aliases with the same credentials, e.g. one per
+// region, authenticate once. The provider client serializes its token
+// updates, so it's safe to use from several configurations.
+type authSessionRegistry struct {
+	mu       sync.Mutex
+	sessions map[authSessionKey]*authSession
+}
+
+var authSessions = &authSessionRegistry{sessions: make(map[authSessionKey]*authSession)}
+
+// get returns the session for the key, it authenticates when there is no
+// such session yet. A failed authentication isn't kept, so the error is
+// returned with a session of an unauthenticated client.
+func (r *authSessionRegistry) get(key authSessionKey) (*authSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if session, ok := r.sessions[key]; ok {
+		return session, nil
+	}
+
+	transport := newRetryTransport(http.DefaultTransport, key.policy)
+	provider, err := gc.NewGCoreClient(key.cloudAPI)
+	if err == nil {
+		// The transport is set before the authentication, so the login is
+		// retried too.
+		provider.HTTPClient.Transport = transport
+		if key.permanentToken != "" {
+			err = provider.SetAPIToken(gcorecloud.APITokenOptions{
+				APIURL:   key.cloudAPI,
+				APIToken: key.permanentToken,
+			})
+		} else {
+			err = gc.Authenticate(provider, gcorecloud.AuthOptions{
+				APIURL:      key.cloudAPI,
+				AuthURL:     key.platformAPI,
+				Username:    key.username,
+				Password:    key.password,
+				AllowReauth: true,
+				ClientID:    key.clientID,
+			})
+		}
+	}
+	if err != nil {
+		return &authSession{provider: &gcorecloud.ProviderClient{}, cache: newClientCache(), transport: transport}, err
+	}
+
+	session := &authSession{provider: provider, cache: newClientCache(), transport: transport}
+	r.sessions[key] = session
+	return session, nil
+}
+
 func providerConfigure(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	username := d.Get("user_name").(string)
 	password := d.Get("password").(string)
@@ -231,57 +374,81 @@ func providerConfigure(_ context.Context, d *schema.ResourceData) (interface{},
 
 	clientID := d.Get("gcore_client_id").(string)
 
+	backoff, err := time.ParseDuration(d.Get("retry_backoff").(string))
+	if err != nil {
+		return nil, diag.FromErr(fmt.Errorf("retry_backoff: %w", err))
+	}
+	policy := retryPolicy{
+		maxRetries:        d.Get("max_retries").(int),
+		backoff:           backoff,
+		requestsPerSecond: d.Get("requests_per_second").(int),
+	}
+
 	var diags diag.Diagnostics
 
-	var err error
-	var provider *gcorecloud.ProviderClient
-	if permanentToken != "" {
-		provider, err = gc.APITokenClient(gcorecloud.APITokenOptions{
-			APIURL:   cloudApi,
-			APIToken: permanentToken,
-		})
-	} else {
-		provider, err = gc.AuthenticatedClient(gcorecloud.AuthOptions{
-			APIURL:      cloudApi,
-			AuthURL:     platform,
-			Username:    username,
-			Password:    password,
-			AllowReauth: true,
-			ClientID:    clientID,
-		})
-	}
+	session, err := authSessions.get(authSessionKey{
+		cloudAPI:       cloudApi,
+		platformAPI:    platform,
+		clientID:       clientID,
+		username:       username,
+		password:       password,
+		permanentToken: permanentToken,
+		policy:         policy,
+	})
 	if err != nil {
-		provider = &gcorecloud.ProviderClient{}
 		log.Printf("[WARN] init auth client: %s\n", err)
 	}
+	provider := session.provider
 
-	cdnProvider := gcdnProvider.NewClient(cdnAPI, gcdnProvider.WithSignerFunc(func(req *http.Request) error {
-		for k, v := range provider.AuthenticatedHeaders() {
-			req.Header.Set(k, v)
-		}
+	cdnProvider := &cdnRequester{
+		client:  &http.Client{Timeout: time.Minute, Transport: session.transport},
+		baseURL: cdnAPI,
+		signer: func(req *http.Request) error {
+			for k, v := range provider.AuthenticatedHeaders() {
+				req.Header.Set(k, v)
+			}
 
-		return nil
-	}))
-	cdnService := gcdn.NewService(cdnProvider)
+			return nil
+		},
+	}
 
 	config := Config{
-		Provider:  provider,
-		CDNClient: cdnService,
+		Provider:            provider,
+		CDNClient:           newCDNClient(cdnProvider),
+		DefaultProjectID:    d.Get("project_id").(int),
+		DefaultProjectName:  d.Get("project_name").(string),
+		DefaultRegionID:     d.Get("region_id").(int),
+		DefaultRegionName:   d.Get("region_name").(string),
+		HashSensitiveValues: d.Get("hash_sensitive_values").(bool),
+		cache:               session.cache,
 	}
 
 	userAgent := fmt.Sprintf("terraform/%s", version.Version)
+	config.httpClient = &http.Client{
+		Transport: userAgentTransport{next: session.transport, userAgent: userAgent},
+	}
 	if storageAPI != "" {
 		stHost, stPath, err := ExtractHostAndPath(storageAPI)
 		if err != nil {
 			return nil, diag.FromErr(fmt.Errorf("storage api url: %w", err))
 		}
-		config.StorageClient = storageSDK.NewSDK(
-			stHost,
-			stPath,
-			storageSDK.WithBearerAuth(provider.AccessToken),
-			storageSDK.WithPermanentTokenAuth(func() string { return permanentToken }),
-			storageSDK.WithUserAgent(userAgent),
-		)
+		config.StorageClient = &StorageClient{
+			SDK: storageSDK.NewSDK(
+				stHost,
+				stPath,
+				storageSDK.WithBearerAuth(provider.AccessToken),
+				storageSDK.WithPermanentTokenAuth(func() string { return permanentToken }),
+				storageSDK.WithUserAgent(userAgent),
+			),
+			httpClient: config.httpClient,
+			apiURL:     storageAPI,
+			authorization: func() string {
+				if permanentToken != "" {
+					return "APIKey " + permanentToken
+				}
+				return "Bearer " + provider.AccessToken()
+			},
+		}
 	}
 	if dnsAPI != "" {
 		baseUrl, err := url.Parse(dnsAPI)
@@ -297,6 +464,7 @@ func providerConfigure(_ context.Context, d *schema.ResourceData) (interface{},
 			func(client *dnssdk.Client) {
 				client.BaseURL = baseUrl
 				client.Debug = os.Getenv("TF_LOG") == "DEBUG"
+				client.HTTPClient.Transport = session.transport
 			},
 			func(client *dnssdk.Client) {
 				client.UserAgent = userAgent
@@ -1,20 +1,22 @@
 package gcore
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
 
 	dnssdk "github.com/G-Core/gcore-dns-sdk-go"
 	storageSDK "github.com/G-Core/gcore-storage-sdk-go"
-	gcdn "github.com/G-Core/gcorelabscdn-go"
 	gcdnProvider "github.com/G-Core/gcorelabscdn-go/gcore/provider"
 	gcorecloud "github.com/G-Core/gcorelabscloud-go"
 	gc "github.com/G-Core/gcorelabscloud-go/gcore"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -228,13 +230,16 @@ func createTestConfig() (*Config, error) {
 		req.Header.Set("Authorization", "Bearer "+provider.AccessToken())
 		return nil
 	}))
-	cdnService := gcdn.NewService(cdnProvider)
 
 	storageAPI := GCORE_STORAGE_API
 	stHost, stPath, err := ExtractHostAndPath(storageAPI)
-	var storageClient *storageSDK.SDK
+	var storageClient *StorageClient
 	if err == nil {
-		storageClient = storageSDK.NewSDK(stHost, stPath, storageSDK.WithBearerAuth(provider.AccessToken))
+		storageClient = &StorageClient{
+			SDK:           storageSDK.NewSDK(stHost, stPath, storageSDK.WithBearerAuth(provider.AccessToken)),
+			apiURL:        storageAPI,
+			authorization: func() string { return "Bearer " + provider.AccessToken() },
+		}
 	}
 
 	var dnsClient *dnssdk.Client
@@ -251,7 +256,7 @@ func createTestConfig() (*Config, error) {
 
 	config := Config{
 		Provider:      provider,
-		CDNClient:     cdnService,
+		CDNClient:     newCDNClient(cdnProvider),
 		StorageClient: storageClient,
 		DNSClient:     dnsClient,
 	}
@@ -273,3 +278,272 @@ func testAccCheckResourceExists(resourceName string) resource.TestCheckFunc {
 		return nil
 	}
 }
+
+// unitCheckState calls check with the state of the resource.
+func unitCheckState(resourceName string, check func(*terraform.InstanceState) error) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+		return check(rs.Primary)
+	}
+}
+
+// unitImportID imports the resource by the ID built from its state.
+func unitImportID(resourceName string, id func(*terraform.InstanceState) string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("not found: %s", resourceName)
+		}
+		return id(rs.Primary), nil
+	}
+}
+
+// unitProviderConfig configures the provider against the fake API with the
+// extra provider arguments and returns its meta.
+func unitProviderConfig(t *testing.T, api *mockAPI, attrs map[string]interface{}) *Config {
+	t.Helper()
+
+	raw := api.providerConfig()
+	for k, v := range attrs {
+		raw[k] = v
+	}
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(raw)); diags.HasError() {
+		t.Fatalf("configure provider: %s", unitDiagsError(diags))
+	}
+	return p.Meta().(*Config)
+}
+
+func unitDiagsError(diags diag.Diagnostics) error {
+	var msgs []string
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			continue
+		}
+		msg := d.Summary
+		if d.Detail != "" {
+			msg += ": " + d.Detail
+		}
+		msgs = append(msgs, msg)
+	}
+	return fmt.Errorf("%s", strings.Join(msgs, "; "))
+}
+
+func TestUnitProviderDefaultScope(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_network.test"
+	provider := api.providerHCL(fmt.Sprintf(`project_name = "%s"
+  region_name  = "%s"`, mockProjectName, mockRegionName))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_network", "networks"),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + `
+resource "gcore_network" "test" {
+  name = "network"
+  type = "vxlan"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "project_id", strconv.Itoa(mockProjectID)),
+					resource.TestCheckResourceAttr(fullName, "region_id", strconv.Itoa(mockRegionID)),
+				),
+			},
+			{
+				Config: provider + `
+resource "gcore_network" "test" {
+  name = "network-renamed"
+  type = "vxlan"
+}
+`,
+				Check: resource.TestCheckResourceAttr(fullName, "name", "network-renamed"),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "create_router"},
+			},
+		},
+	})
+}
+
+func TestUnitProviderDefaultScopeOverride(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_network.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_network", "networks"),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL(mockCloudScope) + `
+resource "gcore_network" "test" {
+  name        = "network"
+  region_name = "Frankfurt"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "region_id", "2"),
+					func(s *terraform.State) error {
+						if n := len(api.Requests(fmt.Sprintf("POST /cloud/v1/networks/%d/2", mockProjectID))); n != 1 {
+							return fmt.Errorf("expected the network to be created in region 2, got %d requests", n)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestUnitProviderDefaultScopeChange(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_network.test"
+	config := `
+resource "gcore_network" "test" {
+  name = "network"
+}
+`
+
+	var networkID string
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_network", "networks"),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL(mockCloudScope) + config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "region_id", strconv.Itoa(mockRegionID)),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						networkID = s.ID
+						return nil
+					}),
+				),
+			},
+			{
+				Config: api.providerHCL(fmt.Sprintf(`project_id  = %d
+  region_name = "Frankfurt"`, mockProjectID)) + config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "region_id", "2"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if s.ID == networkID {
+							return fmt.Errorf("expected the network to be replaced in the new default region")
+						}
+						if api.cloud.Get("networks", networkID) != nil {
+							return fmt.Errorf("network %s in the old region was not deleted", networkID)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitProviderNoScope(t *testing.T) {
+	api := newMockAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + `
+resource "gcore_network" "test" {
+  name = "network"
+}
+`,
+				ExpectError: regexp.MustCompile("project_id or project_name must be set"),
+			},
+		},
+	})
+}
+
+func TestUnitProviderSharedSession(t *testing.T) {
+	api := newMockAPI(t)
+	credentials := func(region int) map[string]interface{} {
+		return map[string]interface{}{
+			ProviderOptPermanentToken: "",
+			"user_name":               "user",
+			"password":                "secret",
+			"region_id":               region,
+		}
+	}
+
+	first := unitProviderConfig(t, api, credentials(mockRegionID))
+	second := unitProviderConfig(t, api, credentials(2))
+	if first.Provider != second.Provider {
+		t.Fatalf("expected aliases with the same credentials to share the provider client")
+	}
+	if second.DefaultRegionID != 2 {
+		t.Fatalf("expected the alias to keep its own default region, got %d", second.DefaultRegionID)
+	}
+	if n := len(api.Requests("POST /auth/jwt/login")); n != 1 {
+		t.Fatalf("expected one login, got %d", n)
+	}
+
+	other := credentials(mockRegionID)
+	other["password"] = "other"
+	if unitProviderConfig(t, api, other).Provider == first.Provider {
+		t.Fatalf("expected other credentials to get their own provider client")
+	}
+}
+
+func TestUnitRegionValidation(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_network.test"
+	provider := api.providerHCL(fmt.Sprintf("project_id = %d", mockProjectID))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_network", "networks"),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + `
+resource "gcore_network" "test" {
+  name        = "network"
+  region_name = "Nowhere"
+}
+`,
+				ExpectError: regexp.MustCompile(`region with name Nowhere not found, available regions: Luxembourg \(1\), Frankfurt \(2\)`),
+			},
+			{
+				Config: provider + `
+resource "gcore_network" "test" {
+  name      = "network"
+  region_id = 99
+}
+`,
+				ExpectError: regexp.MustCompile(`region with ID 99 not found`),
+			},
+			{
+				Config: provider + `
+resource "gcore_network" "test" {
+  name      = "network"
+  region_id = 2
+}
+`,
+				Check: resource.TestCheckResourceAttr(fullName, "region_id", "2"),
+			},
+			{
+				ResourceName: fullName,
+				ImportState:  true,
+				ImportStateIdFunc: unitImportID(fullName, func(s *terraform.InstanceState) string {
+					return fmt.Sprintf("%d:2:%s", mockProjectID, s.ID)
+				}),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "create_router"},
+			},
+		},
+	})
+
+	if n := len(api.Requests("GET /cloud/v1/regions")); n != 1 {
+		t.Fatalf("expected regions to be listed once, got %d", n)
+	}
+}
@@ -31,9 +31,12 @@ func resourceBmInstance() *schema.Resource {
 		ReadContext:   resourceBmInstanceRead,
 		UpdateContext: resourceBmInstanceUpdate,
 		DeleteContext: resourceBmInstanceDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent baremetal instance",
 		Timeouts: &schema.ResourceTimeout{
 			Create: &bmCreateTimeout,
+			Update: schema.DefaultTimeout(time.Duration(InstanceCreatingTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(BmInstanceDeleting) * time.Second),
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -52,36 +55,26 @@ func resourceBmInstance() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"flavor_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -182,8 +175,11 @@ func resourceBmInstance() *schema.Resource {
 				Optional: true,
 			},
 			"password": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressSensitiveHashDiff,
+				Description:      "Only its hash is kept in the state when the provider sets hash_sensitive_values",
 			},
 			"username": &schema.Schema{
 				Type:     schema.TypeString,
@@ -272,9 +268,8 @@ func resourceBmInstanceCreate(ctx context.Context, d *schema.ResourceData, m int
 	log.Println("[DEBUG] Start BaremetalInstance creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, BmInstancePoint, versionPointV1)
+	client, err := CreateClient(config, d, BmInstancePoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -354,7 +349,7 @@ func resourceBmInstanceCreate(ctx context.Context, d *schema.ResourceData, m int
 
 	taskID := results.Tasks[0]
 
-	InstanceID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, BmInstanceCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
+	InstanceID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -382,11 +377,10 @@ func resourceBmInstanceRead(ctx context.Context, d *schema.ResourceData, m inter
 	log.Println("[DEBUG] Start Baremetal Instance reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	instanceID := d.Id()
 	log.Printf("[DEBUG] Instance id = %s", instanceID)
 
-	client, err := CreateClient(provider, d, InstancePoint, versionPointV1)
+	client, err := CreateClient(config, d, InstancePoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -553,6 +547,7 @@ func resourceBmInstanceRead(ctx context.Context, d *schema.ResourceData, m inter
 
 	fields := []string{"user_data", "app_config"}
 	revertState(d, &fields)
+	hashSensitiveFields(d, config, "password")
 
 	log.Println("[DEBUG] Finish Instance reading")
 	return diags
@@ -563,8 +558,7 @@ func resourceBmInstanceUpdate(ctx context.Context, d *schema.ResourceData, m int
 	instanceID := d.Id()
 	log.Printf("[DEBUG] Instance id = %s", instanceID)
 	config := m.(*Config)
-	provider := config.Provider
-	client, err := CreateClient(provider, d, InstancePoint, versionPointV1)
+	client, err := CreateClient(config, d, InstancePoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -641,90 +635,9 @@ func resourceBmInstanceUpdate(ctx context.Context, d *schema.ResourceData, m int
 	}
 
 	if d.HasChange("interface") {
-		ifsOldRaw, ifsNewRaw := d.GetChange("interface")
-
-		ifsOld := ifsOldRaw.([]interface{})
-		ifsNew := ifsNewRaw.([]interface{})
-
-		for _, i := range ifsOld {
-			iface := i.(map[string]interface{})
-			if isInterfaceContains(iface, ifsNew) {
-				log.Println("[DEBUG] Skipped, dont need detach")
-				continue
-			}
-
-			if iface["is_parent"].(bool) {
-				return diag.Errorf("could not detach trunk interface")
-			}
-
-			var opts instances.InterfaceOpts
-			opts.PortID = iface["port_id"].(string)
-			opts.IpAddress = iface["ip_address"].(string)
-
-			log.Printf("[DEBUG] detach interface: %+v", opts)
-			results, err := instances.DetachInterface(client, instanceID, opts).Extract()
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			taskID := results.Tasks[0]
-			_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, InstanceCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
-				taskInfo, err := tasks.Get(client, string(task)).Extract()
-				if err != nil {
-					return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w, task: %+v", task, err, taskInfo)
-				}
-				return nil, nil
-			},
-			)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-		}
-
-		currentIfs, err := instances.ListInterfacesAll(client, d.Id())
-		if err != nil {
+		if err := updateInstanceInterfaces(ctx, config, client, d); err != nil {
 			return diag.FromErr(err)
 		}
-
-		sort.Sort(instanceInterfaces(ifsNew))
-		for _, i := range ifsNew {
-			iface := i.(map[string]interface{})
-			if isInterfaceContains(iface, ifsOld) {
-				log.Println("[DEBUG] Skipped, dont need attach")
-				continue
-			}
-			if isInterfaceAttached(currentIfs, iface) {
-				continue
-			}
-
-			iType := types.InterfaceType(iface["type"].(string))
-			opts := instances.InterfaceOpts{Type: iType}
-			switch iType {
-			case types.SubnetInterfaceType:
-				opts.SubnetID = iface["subnet_id"].(string)
-			case types.AnySubnetInterfaceType:
-				opts.NetworkID = iface["network_id"].(string)
-			case types.ReservedFixedIpType:
-				opts.PortID = iface["port_id"].(string)
-			}
-
-			log.Printf("[DEBUG] attach interface: %+v", opts)
-			results, err := instances.AttachInterface(client, instanceID, opts).Extract()
-			if err != nil {
-				return diag.Errorf("cannot attach interface: %s. Error: %s", iType, err)
-			}
-			taskID := results.Tasks[0]
-			_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, InstanceCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
-				taskInfo, err := tasks.Get(client, string(task)).Extract()
-				if err != nil {
-					return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w, task: %+v", task, err, taskInfo)
-				}
-				return nil, nil
-			},
-			)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-		}
 	}
 
 	d.Set("last_updated", time.Now().Format(time.RFC850))
@@ -736,11 +649,10 @@ func resourceBmInstanceDelete(ctx context.Context, d *schema.ResourceData, m int
 	log.Println("[DEBUG] Start Baremetal Instance deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	instanceID := d.Id()
 	log.Printf("[DEBUG] Instance id = %s", instanceID)
 
-	client, err := CreateClient(provider, d, InstancePoint, versionPointV1)
+	client, err := CreateClient(config, d, InstancePoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -754,7 +666,7 @@ func resourceBmInstanceDelete(ctx context.Context, d *schema.ResourceData, m int
 	}
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, BmInstanceDeleting, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := instances.Get(client, instanceID).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete instance with ID: %s", instanceID)
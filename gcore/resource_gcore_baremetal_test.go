@@ -66,3 +66,79 @@ func testAccBaremetalDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitBaremetal(t *testing.T) {
+	api := newMockAPI(t)
+	networkID := api.cloud.Seed("networks", map[string]interface{}{"name": "network"})
+	subnetID := api.cloud.Seed("subnets", map[string]interface{}{
+		"name":       "subnet",
+		"cidr":       "192.168.10.0/24",
+		"network_id": networkID,
+	})
+	fullName := "gcore_baremetal.test"
+
+	template := func(name, env string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_baremetal" "test" {
+  %s
+  name      = "%s"
+  flavor_id = "bm1-infrastructure-small"
+  image_id  = "1ee7ccee-5003-48c9-8ae0-d96063af75b2"
+
+  interface {
+    type       = "subnet"
+    network_id = "%s"
+    subnet_id  = "%s"
+  }
+
+  metadata_map = {
+    env = "%s"
+  }
+}
+`, mockCloudScope, name, networkID, subnetID, env)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_baremetal", "instances"),
+		Steps: []resource.TestStep{
+			{
+				Config: template("bm", "test"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "bm"),
+					resource.TestCheckResourceAttr(fullName, "flavor_id", "bm1-infrastructure-small"),
+					resource.TestCheckResourceAttr(fullName, "vm_state", "active"),
+					resource.TestCheckResourceAttr(fullName, "interface.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "interface.0.subnet_id", subnetID),
+					resource.TestCheckResourceAttr(fullName, "interface.0.ip_address", "192.168.10.2"),
+					resource.TestCheckResourceAttrSet(fullName, "interface.0.port_id"),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.env", "test"),
+					resource.TestCheckResourceAttr(fullName, "addresses.0.net.0.addr", "192.168.10.2"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if api.cloud.Get("instances", s.ID) == nil {
+							return fmt.Errorf("server %s not found", s.ID)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: template("bm-renamed", "prod"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "bm-renamed"),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.env", "prod"),
+				),
+			},
+			{
+				ResourceName:      fullName,
+				ImportState:       true,
+				ImportStateIdFunc: unitCloudImportID(fullName),
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"last_updated", "project_name", "region_name",
+					"image_id", "interface", "metadata_map",
+				},
+			},
+		},
+	})
+}
@@ -6,6 +6,7 @@ package gcore
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -98,3 +99,63 @@ func or(checks ...resource.TestCheckFunc) resource.TestCheckFunc {
 		return errors.New(composed)
 	}
 }
+
+func TestUnitCDNOriginGroup(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_cdn_origingroup.test"
+
+	template := func(source string, enabled bool) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_cdn_origingroup" "test" {
+  name     = "origin-group"
+  use_next = true
+
+  origin {
+    source  = "%s"
+    enabled = %t
+  }
+
+  origin {
+    source  = "backup.example.com"
+    enabled = true
+    backup  = true
+  }
+}
+`, source, enabled)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCDNCheckDestroy("gcore_cdn_origingroup", api.cdn.OriginGroup),
+		Steps: []resource.TestStep{
+			{
+				Config: template("example.com", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "origin-group"),
+					resource.TestCheckResourceAttr(fullName, "use_next", "true"),
+					resource.TestCheckResourceAttr(fullName, "origin.#", "2"),
+				),
+			},
+			{
+				Config: template("example.org", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "origin.#", "2"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						id, _ := strconv.Atoi(s.ID)
+						for _, o := range mockList(api.cdn.OriginGroup(id)["origin_ids"]) {
+							if mockString(mockMap(o)["source"]) == "example.org" && !mockBool(mockMap(o)["enabled"]) {
+								return nil
+							}
+						}
+						return fmt.Errorf("origin example.org was not updated")
+					}),
+				),
+			},
+			{
+				ResourceName:      fullName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,67 @@
+package gcore
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCDNPrefetch() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"resource_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the CDN resource to load the files to the cache of.",
+			},
+			"paths": func() *schema.Schema {
+				s := cdnCachePathSchema("Paths of the files to load to the cache, e.g. /video/intro.mp4.")
+				s.Optional, s.Required, s.MinItems = false, true, 1
+				return s
+			}(),
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values, a change of any of them loads the files again, e.g. the version of the deployed site.",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+		},
+		CreateContext: resourceCDNPrefetchCreate,
+		ReadContext:   resourceCDNCacheTaskRead,
+		DeleteContext: resourceCDNCacheTaskDelete,
+		Description:   "Loads files of a CDN resource to the cache on create and when the triggers change, and waits for the prefetch to complete. Deleting it does nothing.",
+	}
+}
+
+func resourceCDNPrefetchCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start CDN Prefetch creating")
+	config := m.(*Config)
+	client := config.CDNClient
+
+	resourceID := int64(d.Get("resource_id").(int))
+	paths := cdnCacheBatches(cdnCachePaths(d, "paths"))
+
+	err := runCDNCacheTask(ctx, client, resourceID, CDNPrefetch, paths, d.Timeout(schema.TimeoutCreate), func() error {
+		for _, batch := range paths {
+			if err := client.Prefetch(ctx, resourceID, batch); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(cdnCacheTaskID(resourceID))
+
+	log.Printf("[DEBUG] Finish CDN Prefetch creating (id=%s)\n", d.Id())
+	return nil
+}
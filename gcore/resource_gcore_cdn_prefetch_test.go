@@ -0,0 +1,47 @@
+//go:build !cloud
+// +build !cloud
+
+package gcore
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestUnitCDNPrefetch(t *testing.T) {
+	api := newMockAPI(t)
+	resourceID := api.cdn.SeedResource("cdn.example.com", api.cdn.SeedOriginGroup("origin-group", "example.com"))
+
+	paths := make([]string, 0, 150)
+	for i := 0; i < 150; i++ {
+		paths = append(paths, fmt.Sprintf("/video/%03d.ts", i))
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_cdn_prefetch" "test" {
+  resource_id = %d
+  paths       = ["%s"]
+}
+`, resourceID, strings.Join(paths, `", "`)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gcore_cdn_prefetch.test", "paths.#", "150"),
+					func(*terraform.State) error {
+						tasks := api.cdn.CacheTasks()
+						if len(tasks) != 2 || len(mockList(tasks[0]["paths"])) != 100 || len(mockList(tasks[1]["paths"])) != 50 {
+							return fmt.Errorf("expected prefetches of 100 and 50 paths, got %v", tasks)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
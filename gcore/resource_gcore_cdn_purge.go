@@ -0,0 +1,242 @@
+package gcore
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	// cdnCacheBatchSize is the limit of the paths of one purge or prefetch.
+	cdnCacheBatchSize = 100
+	// cdnCacheTaskClockSkew is the margin of the creation time of the tasks
+	// listed after a purge or a prefetch.
+	cdnCacheTaskClockSkew = 5 * time.Minute
+)
+
+// cdnCachePathSchema is a set of paths of the files of a resource.
+func cdnCachePathSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringMatch(regexp.MustCompile("^/"), "path should start with /"),
+		},
+		Description: description,
+	}
+}
+
+func resourceCDNPurge() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"resource_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the CDN resource to purge the cache of.",
+			},
+			"urls": func() *schema.Schema {
+				s := cdnCachePathSchema("Paths of the files to purge, e.g. /static/app.js. A path with a query string purges only that version of the file.")
+				s.ConflictsWith = []string{"paths"}
+				return s
+			}(),
+			"paths": cdnCachePathSchema("Patterns of the files to purge, * matches any characters, e.g. /static/*. The cache of the whole resource is purged when neither urls nor paths are set."),
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values, a change of any of them purges the cache again, e.g. the version of the deployed site.",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+		},
+		CreateContext: resourceCDNPurgeCreate,
+		ReadContext:   resourceCDNCacheTaskRead,
+		DeleteContext: resourceCDNCacheTaskDelete,
+		Description:   "Purges the cache of a CDN resource on create and when the triggers change, and waits for the purge to complete. Deleting it does nothing.",
+	}
+}
+
+func resourceCDNPurgeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start CDN Purge creating")
+	config := m.(*Config)
+	client := config.CDNClient
+
+	resourceID := int64(d.Get("resource_id").(int))
+	urls := cdnCachePaths(d, "urls")
+	patterns := cdnCachePaths(d, "paths")
+
+	taskType, paths := CDNPurgeAll, [][]string{nil}
+	switch {
+	case len(urls) > 0:
+		taskType, paths = CDNPurgeByURL, cdnCacheBatches(urls)
+	case len(patterns) > 0:
+		taskType, paths = CDNPurgeByPattern, cdnCacheBatches(patterns)
+	}
+
+	err := runCDNCacheTask(ctx, client, resourceID, taskType, paths, d.Timeout(schema.TimeoutCreate), func() error {
+		for _, batch := range paths {
+			var err error
+			if taskType == CDNPurgeByURL {
+				err = client.Purge(ctx, resourceID, batch, nil)
+			} else {
+				err = client.Purge(ctx, resourceID, nil, batch)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(cdnCacheTaskID(resourceID))
+
+	log.Printf("[DEBUG] Finish CDN Purge creating (id=%s)\n", d.Id())
+	return nil
+}
+
+// resourceCDNCacheTaskRead keeps the state, a purge or a prefetch is not an
+// object of the API.
+func resourceCDNCacheTaskRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return nil
+}
+
+// resourceCDNCacheTaskDelete only removes the purge or the prefetch from
+// the state, the cache can't be restored.
+func resourceCDNCacheTaskDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
+
+// runCDNCacheTask sends the requests of a purge or a prefetch of the type,
+// one per batch of paths, and waits for the API to complete all of them.
+func runCDNCacheTask(ctx context.Context, client *CDNClient, resourceID int64, taskType string, batches [][]string, timeout time.Duration, send func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resource, err := client.Resources().Get(ctx, resourceID)
+	if err != nil {
+		return err
+	}
+	// The API stamps the tasks with its own clock, the margin covers its
+	// skew. The tasks of the same paths created within the margin before
+	// the requests are counted first to tell the new ones apart.
+	from := time.Now().Add(-cdnCacheTaskClockSkew)
+	tasks, err := client.CacheTasks(ctx, resource.Cname, taskType, from)
+	if err != nil {
+		return err
+	}
+	want := make(map[string]int)
+	for _, batch := range batches {
+		want[cdnCacheTaskKey(batch)]++
+	}
+	before := countCDNCacheTasks(tasks, want)
+	if err := send(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(taskPollInterval)
+	defer ticker.Stop()
+	for {
+		tasks, err := client.CacheTasks(ctx, resource.Cname, taskType, from)
+		if err != nil {
+			return err
+		}
+		listed, inProgress := 0, 0
+		for key, count := range countCDNCacheTasks(tasks, want) {
+			if count.failed > before[key].failed {
+				return fmt.Errorf("%s of resource %d failed: %s", taskType, resourceID, strings.ReplaceAll(key, "\n", ", "))
+			}
+			// The API may list the requests with a delay.
+			if n := count.total - before[key].total; n < want[key] {
+				listed += n
+			} else {
+				listed += want[key]
+			}
+			inProgress += count.inProgress
+		}
+		if listed == len(batches) && inProgress == 0 {
+			return nil
+		}
+		log.Printf("[DEBUG] %d of %d %s requests of resource %d are listed, %d are in progress", listed, len(batches), taskType, resourceID, inProgress)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timeout while waiting for %s of resource %d after %s", taskType, resourceID, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// cdnCacheTaskCount is the number of the listed tasks of the same paths.
+type cdnCacheTaskCount struct {
+	total, inProgress, failed int
+}
+
+// countCDNCacheTasks counts the tasks by their paths, only the paths of want
+// are counted.
+func countCDNCacheTasks(tasks []CDNCacheTask, want map[string]int) map[string]cdnCacheTaskCount {
+	counts := make(map[string]cdnCacheTaskCount)
+	for _, task := range tasks {
+		key := cdnCacheTaskKey(task.Paths)
+		if _, ok := want[key]; !ok {
+			continue
+		}
+		count := counts[key]
+		count.total++
+		switch task.Status {
+		case CDNCacheTaskFailed:
+			count.failed++
+		case CDNCacheTaskInProgress:
+			count.inProgress++
+		}
+		counts[key] = count
+	}
+	return counts
+}
+
+// cdnCacheTaskKey identifies the paths of a task regardless of their order.
+func cdnCacheTaskKey(paths []string) string {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "\n")
+}
+
+// cdnCachePaths returns the sorted paths of the set.
+func cdnCachePaths(d *schema.ResourceData, key string) []string {
+	var paths []string
+	for _, p := range d.Get(key).(*schema.Set).List() {
+		paths = append(paths, p.(string))
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// cdnCacheBatches splits the paths into the requests the API accepts.
+func cdnCacheBatches(paths []string) [][]string {
+	var batches [][]string
+	for len(paths) > cdnCacheBatchSize {
+		batches = append(batches, paths[:cdnCacheBatchSize])
+		paths = paths[cdnCacheBatchSize:]
+	}
+	return append(batches, paths)
+}
+
+func cdnCacheTaskID(resourceID int64) string {
+	return strconv.FormatInt(resourceID, 10) + ":" + strconv.FormatInt(time.Now().UnixNano(), 10)
+}
@@ -0,0 +1,129 @@
+//go:build !cloud
+// +build !cloud
+
+package gcore
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestUnitCDNPurge(t *testing.T) {
+	api := newMockAPI(t)
+	resourceID := api.cdn.SeedResource("cdn.example.com", api.cdn.SeedOriginGroup("origin-group", "example.com"))
+
+	template := func(version, paths string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_cdn_purge" "test" {
+  resource_id = %d
+  %s
+
+  triggers = {
+    version = "%s"
+  }
+}
+`, resourceID, paths, version)
+	}
+	checkTasks := func(want ...string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			tasks := api.cdn.CacheTasks()
+			if len(tasks) != len(want) {
+				return fmt.Errorf("expected %d purges, got %d", len(want), len(tasks))
+			}
+			for i, task := range tasks {
+				if task["purge_type"] != want[i] || task["status"] != "Successful" {
+					return fmt.Errorf("unexpected purge %d: %v", i, task)
+				}
+			}
+			return nil
+		}
+	}
+	urls := `urls = ["/app.js", "/app.css"]`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: template("1", urls),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gcore_cdn_purge.test", "urls.#", "2"),
+					resource.TestCheckResourceAttr("gcore_cdn_purge.test", "triggers.version", "1"),
+					checkTasks("purge_by_url"),
+				),
+			},
+			{
+				// The same triggers don't purge the cache again.
+				Config: template("1", urls),
+				Check:  checkTasks("purge_by_url"),
+			},
+			{
+				Config: template("2", urls),
+				Check:  checkTasks("purge_by_url", "purge_by_url"),
+			},
+			{
+				Config: template("2", `paths = ["/static/*"]`),
+				Check:  checkTasks("purge_by_url", "purge_by_url", "purge_by_pattern"),
+			},
+			{
+				Config: template("2", ""),
+				Check:  checkTasks("purge_by_url", "purge_by_url", "purge_by_pattern", "purge_all"),
+			},
+			{
+				Config:      template("3", `urls = ["/broken.js"]`),
+				ExpectError: regexp.MustCompile(fmt.Sprintf("purge_by_url of resource %d failed: /broken.js", resourceID)),
+			},
+			{
+				// Only the new purges of the same paths are waited for.
+				PreConfig: func() {
+					api.cdn.SeedCacheTask(resourceID, "purge_by_url", "Failed", "/app.js")
+					api.cdn.SeedCacheTask(resourceID, "purge_by_url", "Failed", "/other.js")
+				},
+				Config: template("4", `urls = ["/app.js"]`),
+				Check: func(*terraform.State) error {
+					tasks := api.cdn.CacheTasks()
+					if last := tasks[len(tasks)-1]; last["status"] != "Successful" || len(mockList(last["paths"])) != 1 {
+						return fmt.Errorf("unexpected purge: %v", last)
+					}
+					return nil
+				},
+			},
+			{
+				Config:      template("3", `urls = ["app.js"]`),
+				ExpectError: regexp.MustCompile("path should start with /"),
+			},
+			{
+				Config: template("4", `urls = ["/app.js"]`),
+			},
+		},
+	})
+}
+
+// TestUnitCDNCacheTaskWait checks the tasks of a purge are told apart from
+// the earlier tasks listed within the clock skew margin.
+func TestUnitCDNCacheTaskWait(t *testing.T) {
+	api := newMockAPI(t)
+	config := unitProviderConfig(t, api, nil)
+	client := config.CDNClient
+	resourceID := api.cdn.SeedResource("cdn.example.com", api.cdn.SeedOriginGroup("origin-group", "example.com"))
+	api.cdn.SeedCacheTask(resourceID, CDNPurgeByURL, CDNCacheTaskFailed, "/app.js")
+	api.cdn.SeedCacheTask(resourceID, CDNPurgeByURL, CDNCacheTaskFailed, "/other.js")
+
+	purge := func(paths ...string) error {
+		return runCDNCacheTask(context.Background(), client, int64(resourceID), CDNPurgeByURL, [][]string{paths}, time.Minute, func() error {
+			return client.Purge(context.Background(), int64(resourceID), paths, nil)
+		})
+	}
+	if err := purge("/app.js"); err != nil {
+		t.Fatalf("purge after an earlier failed purge of the same paths: %v", err)
+	}
+	if err := purge("/broken.js"); err == nil || !strings.Contains(err.Error(), "failed: /broken.js") {
+		t.Fatalf("expected the purge to fail, got %v", err)
+	}
+}
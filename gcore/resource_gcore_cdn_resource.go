@@ -6,14 +6,19 @@ import (
 	"log"
 	"reflect"
 	"strconv"
+	"strings"
+	"unicode"
 
 	gcdn "github.com/G-Core/gcorelabscdn-go/gcore"
 	"github.com/G-Core/gcorelabscdn-go/resources"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 var (
+	// optionsSchema maps every option of gcdn.Options, the round-trip test
+	// fails when the SDK gains one the schema lacks.
 	optionsSchema = &schema.Schema{
 		Type:        schema.TypeList,
 		MaxItems:    1,
@@ -153,14 +158,446 @@ var (
 								Elem:     &schema.Schema{Type: schema.TypeString},
 								Required: true,
 							},
+							"always": {
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Default:     false,
+								Description: "Adds the CORS header to the responses with any code, not only 200, 201, 204, 206, 301, 302, 303, 304, 307 and 308.",
+							},
+						},
+					},
+				},
+				"rewrite": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"body": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"flag": {
+								Type:     schema.TypeString,
+								Optional: true,
+								Default:  "break",
+							},
+						},
+					},
+				},
+				"webp": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"jpg_quality": {
+								Type:     schema.TypeInt,
+								Required: true,
+							},
+							"png_quality": {
+								Type:     schema.TypeInt,
+								Required: true,
+							},
+							"png_lossless": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  false,
+							},
+						},
+					},
+				},
+				"sni": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Computed:    true,
+					Description: "",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"sni_type": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "Available values 'dynamic' or 'custom'",
+							},
+							"custom_hostname": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "Required to set custom hostname in case sni-type='custom'",
+							},
+						},
+					},
+				},
+				"ignore_query_string": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeBool,
+								Required: true,
+							},
+						},
+					},
+				},
+				"query_params_whitelist": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeSet,
+								Elem:     &schema.Schema{Type: schema.TypeString},
+								Required: true,
+							},
+						},
+					},
+				},
+				"query_params_blacklist": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeSet,
+								Elem:     &schema.Schema{Type: schema.TypeString},
+								Required: true,
+							},
+						},
+					},
+				},
+				"static_request_headers": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeMap,
+								Elem:     &schema.Schema{Type: schema.TypeString},
+								Required: true,
+							},
+						},
+					},
+				},
+				"static_headers": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeMap,
+								Elem:     &schema.Schema{Type: schema.TypeString},
+								Required: true,
+							},
+						},
+					},
+				},
+				"cache_http_headers": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Headers of the origin response CDN servers cache and pass to clients, other headers are not cached.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeSet,
+								Elem:     &schema.Schema{Type: schema.TypeString},
+								Required: true,
+							},
+						},
+					},
+				},
+				"websockets": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeBool,
+								Required: true,
+							},
+						},
+					},
+				},
+				"tls_versions": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeSet,
+								Elem:     &schema.Schema{Type: schema.TypeString},
+								Required: true,
+							},
+						},
+					},
+				},
+				"allowed_http_methods": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "HTTP methods CDN servers accept from clients, requests with other methods are answered with the 405 code.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeSet,
+								Elem:     &schema.Schema{Type: schema.TypeString},
+								Required: true,
+							},
+						},
+					},
+				},
+				"brotli_compression": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Compresses the content of the listed types with Brotli on CDN servers. The origin shouldn't compress the content.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:        schema.TypeSet,
+								Elem:        &schema.Schema{Type: schema.TypeString},
+								Required:    true,
+								Description: "Content types compressed, e.g. 'text/html'.",
+							},
+						},
+					},
+				},
+				"country_acl": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Allows or denies access to the content by the country of the client.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"policy_type": {
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringInSlice([]string{"allow", "deny"}, false),
+								Description:  "Available values 'allow' or 'deny'. With 'allow' the requests are allowed except for the excepted_values, with 'deny' they are denied except for them.",
+							},
+							"excepted_values": {
+								Type:        schema.TypeSet,
+								Elem:        &schema.Schema{Type: schema.TypeString},
+								Required:    true,
+								Description: "The country codes (ISO 3166-1 alpha-2) the policy makes an exception for.",
+							},
+						},
+					},
+				},
+				"disable_cache": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Disables caching of the content on CDN servers.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeBool,
+								Required: true,
+							},
+						},
+					},
+				},
+				"disable_proxy_force_ranges": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Lets CDN servers answer range requests with the 206 code when the origin doesn't support ranges.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeBool,
+								Required: true,
+							},
+						},
+					},
+				},
+				"fetch_compressed": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "CDN servers request compressed content from the origin and pass it to clients as is.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeBool,
+								Required: true,
+							},
+						},
+					},
+				},
+				"follow_origin_redirect": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "CDN servers follow the redirects of the origin with the listed codes and cache the content at the target.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"codes": {
+								Type:        schema.TypeSet,
+								Elem:        &schema.Schema{Type: schema.TypeInt},
+								Required:    true,
+								Description: "Redirect codes to follow, 301, 302, 303, 307 or 308.",
+							},
+						},
+					},
+				},
+				"force_return": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "CDN servers answer the requests with the code and the body instead of the content.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"code": {
+								Type:     schema.TypeInt,
+								Required: true,
+							},
+							"body": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Default:     "",
+								Description: "The response body, or the target URL of redirect codes.",
+							},
+						},
+					},
+				},
+				"forward_host_header": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Forwards the Host header of the client request to the origin.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeBool,
+								Required: true,
+							},
 						},
 					},
 				},
-				"rewrite": {
+				"http3_enabled": {
 					Type:        schema.TypeList,
 					MaxItems:    1,
 					Optional:    true,
-					Description: "",
+					Description: "Enables HTTP/3 with QUIC between clients and CDN servers.",
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"enabled": {
@@ -168,23 +605,37 @@ var (
 								Optional: true,
 								Default:  true,
 							},
-							"body": {
-								Type:     schema.TypeString,
+							"value": {
+								Type:     schema.TypeBool,
 								Required: true,
 							},
-							"flag": {
-								Type:     schema.TypeString,
+						},
+					},
+				},
+				"ignore_cookie": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Caches the content regardless of the cookies of the requests.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
 								Optional: true,
-								Default:  "break",
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeBool,
+								Required: true,
 							},
 						},
 					},
 				},
-				"webp": {
+				"image_stack": {
 					Type:        schema.TypeList,
 					MaxItems:    1,
 					Optional:    true,
-					Description: "",
+					Description: "Converts the images to WebP or AVIF on CDN servers and compresses them.",
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"enabled": {
@@ -192,13 +643,20 @@ var (
 								Optional: true,
 								Default:  true,
 							},
-							"jpg_quality": {
-								Type:     schema.TypeInt,
-								Required: true,
+							"avif_enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  false,
 							},
-							"png_quality": {
-								Type:     schema.TypeInt,
-								Required: true,
+							"webp_enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  false,
+							},
+							"quality": {
+								Type:        schema.TypeInt,
+								Optional:    true,
+								Description: "Quality of the compressed JPG and PNG images, from 1 to 100.",
 							},
 							"png_lossless": {
 								Type:     schema.TypeBool,
@@ -208,12 +666,11 @@ var (
 						},
 					},
 				},
-				"sni": {
+				"ip_address_acl": {
 					Type:        schema.TypeList,
 					MaxItems:    1,
 					Optional:    true,
-					Computed:    true,
-					Description: "",
+					Description: "Allows or denies access to the content by the IP address of the client.",
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"enabled": {
@@ -221,24 +678,56 @@ var (
 								Optional: true,
 								Default:  true,
 							},
-							"sni_type": {
+							"policy_type": {
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringInSlice([]string{"allow", "deny"}, false),
+								Description:  "Available values 'allow' or 'deny'. With 'allow' the requests are allowed except for the excepted_values, with 'deny' they are denied except for them.",
+							},
+							"excepted_values": {
+								Type:        schema.TypeSet,
+								Elem:        &schema.Schema{Type: schema.TypeString},
+								Required:    true,
+								Description: "The IP addresses and subnets the policy makes an exception for.",
+							},
+						},
+					},
+				},
+				"limit_bandwidth": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Limits the download speed of the clients.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"limit_type": {
 								Type:        schema.TypeString,
+								Required:    true,
+								Description: "Available values 'static' or 'dynamic'.",
+							},
+							"speed": {
+								Type:        schema.TypeInt,
 								Optional:    true,
-								Description: "Available values 'dynamic' or 'custom'",
+								Description: "Download speed in KB/s, for the static limit.",
 							},
-							"custom_hostname": {
-								Type:        schema.TypeString,
+							"buffer": {
+								Type:        schema.TypeInt,
 								Optional:    true,
-								Description: "Required to set custom hostname in case sni-type='custom'",
+								Description: "Amount of the content in KB sent at full speed before the limit, for the static limit.",
 							},
 						},
 					},
 				},
-				"ignore_query_string": {
+				"proxy_cache_methods_set": {
 					Type:        schema.TypeList,
 					MaxItems:    1,
 					Optional:    true,
-					Description: "",
+					Description: "Caches the responses to POST requests.",
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"enabled": {
@@ -253,11 +742,11 @@ var (
 						},
 					},
 				},
-				"query_params_whitelist": {
+				"redirect_https_to_http": {
 					Type:        schema.TypeList,
 					MaxItems:    1,
 					Optional:    true,
-					Description: "",
+					Description: "Sets redirect from HTTPS protocol to HTTP for all resource requests.",
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"enabled": {
@@ -266,18 +755,17 @@ var (
 								Default:  true,
 							},
 							"value": {
-								Type:     schema.TypeSet,
-								Elem:     &schema.Schema{Type: schema.TypeString},
+								Type:     schema.TypeBool,
 								Required: true,
 							},
 						},
 					},
 				},
-				"query_params_blacklist": {
+				"referrer_acl": {
 					Type:        schema.TypeList,
 					MaxItems:    1,
 					Optional:    true,
-					Description: "",
+					Description: "Allows or denies access to the content by the Referer header of the request.",
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"enabled": {
@@ -285,19 +773,110 @@ var (
 								Optional: true,
 								Default:  true,
 							},
-							"value": {
+							"policy_type": {
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringInSlice([]string{"allow", "deny"}, false),
+								Description:  "Available values 'allow' or 'deny'. With 'allow' the requests are allowed except for the excepted_values, with 'deny' they are denied except for them.",
+							},
+							"excepted_values": {
+								Type:        schema.TypeSet,
+								Elem:        &schema.Schema{Type: schema.TypeString},
+								Required:    true,
+								Description: "The domains the policy makes an exception for.",
+							},
+						},
+					},
+				},
+				"request_limiter": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Limits the rate of the requests from a client IP address.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"rate": {
+								Type:     schema.TypeInt,
+								Required: true,
+							},
+							"burst": {
+								Type:     schema.TypeInt,
+								Required: true,
+							},
+							"rate_unit": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Default:     "r/s",
+								Description: "Available values 'r/s' or 'r/m'.",
+							},
+							"delay": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+						},
+					},
+				},
+				"response_headers_hiding_policy": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Hides the headers of the origin response from the clients.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"mode": {
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringInSlice([]string{"hide", "show"}, false),
+								Description:  "Available values 'hide' to hide the headers except for the excepted ones, or 'show' to show the excepted ones only.",
+							},
+							"excepted": {
 								Type:     schema.TypeSet,
 								Elem:     &schema.Schema{Type: schema.TypeString},
-								Required: true,
+								Optional: true,
 							},
 						},
 					},
 				},
-				"static_request_headers": {
+				"secure_key": {
 					Type:        schema.TypeList,
 					MaxItems:    1,
 					Optional:    true,
-					Description: "",
+					Description: "Protects the content with signed links.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"key": {
+								Type:      schema.TypeString,
+								Required:  true,
+								Sensitive: true,
+							},
+							"type": {
+								Type:        schema.TypeInt,
+								Required:    true,
+								Description: "Type of the signed links, 0 with the client IP address in the signature, 2 without it.",
+							},
+						},
+					},
+				},
+				"slice": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Requests the content from the origin in 10 MB parts, for the large files.",
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"enabled": {
@@ -306,18 +885,17 @@ var (
 								Default:  true,
 							},
 							"value": {
-								Type:     schema.TypeMap,
-								Elem:     &schema.Schema{Type: schema.TypeString},
+								Type:     schema.TypeBool,
 								Required: true,
 							},
 						},
 					},
 				},
-				"static_headers": {
+				"stale": {
 					Type:        schema.TypeList,
 					MaxItems:    1,
 					Optional:    true,
-					Description: "",
+					Description: "Serves the stale cached content when the origin returns the listed errors.",
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"enabled": {
@@ -326,18 +904,57 @@ var (
 								Default:  true,
 							},
 							"value": {
-								Type:     schema.TypeMap,
-								Elem:     &schema.Schema{Type: schema.TypeString},
+								Type:        schema.TypeSet,
+								Elem:        &schema.Schema{Type: schema.TypeString},
+								Required:    true,
+								Description: "Errors, e.g. 'error', 'timeout', 'http_500', 'updating'.",
+							},
+						},
+					},
+				},
+				"static_response_headers": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Headers CDN servers add to the responses.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"value": {
+								Type:     schema.TypeList,
 								Required: true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"name": {
+											Type:     schema.TypeString,
+											Required: true,
+										},
+										"value": {
+											Type:     schema.TypeList,
+											Elem:     &schema.Schema{Type: schema.TypeString},
+											Required: true,
+										},
+										"always": {
+											Type:        schema.TypeBool,
+											Optional:    true,
+											Default:     false,
+											Description: "Adds the header to the responses with any code, not only 200, 201, 204, 206, 301, 302, 303, 304, 307 and 308.",
+										},
+									},
+								},
 							},
 						},
 					},
 				},
-				"websockets": {
+				"use_default_le_chain": {
 					Type:        schema.TypeList,
 					MaxItems:    1,
 					Optional:    true,
-					Description: "",
+					Description: "Uses the default Let's Encrypt certificate chain for the certificate issued automatically.",
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"enabled": {
@@ -352,11 +969,38 @@ var (
 						},
 					},
 				},
-				"tls_versions": {
+				"user_agent_acl": {
 					Type:        schema.TypeList,
 					MaxItems:    1,
 					Optional:    true,
-					Description: "",
+					Description: "Allows or denies access to the content by the User-Agent header of the request.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+							"policy_type": {
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringInSlice([]string{"allow", "deny"}, false),
+								Description:  "Available values 'allow' or 'deny'. With 'allow' the requests are allowed except for the excepted_values, with 'deny' they are denied except for them.",
+							},
+							"excepted_values": {
+								Type:        schema.TypeSet,
+								Elem:        &schema.Schema{Type: schema.TypeString},
+								Required:    true,
+								Description: "The user agents the policy makes an exception for.",
+							},
+						},
+					},
+				},
+				"use_rsa_le_cert": {
+					Type:        schema.TypeList,
+					MaxItems:    1,
+					Optional:    true,
+					Description: "Issues an RSA Let's Encrypt certificate instead of an ECDSA one.",
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"enabled": {
@@ -365,8 +1009,7 @@ var (
 								Default:  true,
 							},
 							"value": {
-								Type:     schema.TypeSet,
-								Elem:     &schema.Schema{Type: schema.TypeString},
+								Type:     schema.TypeBool,
 								Required: true,
 							},
 						},
@@ -602,185 +1245,77 @@ func listToOptions(l []interface{}) *gcdn.Options {
 
 	var opts gcdn.Options
 	fields := l[0].(map[string]interface{})
-	if opt, ok := getOptByName(fields, "edge_cache_settings"); ok {
-		rawCustomVals := opt["custom_values"].(map[string]interface{})
-		customVals := make(map[string]string, len(rawCustomVals))
-		for key, value := range rawCustomVals {
-			customVals[key] = value.(string)
-		}
-
-		opts.EdgeCacheSettings = &gcdn.EdgeCacheSettings{
-			Enabled:      opt["enabled"].(bool),
-			Value:        opt["value"].(string),
-			CustomValues: customVals,
-			Default:      opt["default"].(string),
-		}
-	}
-	if opt, ok := getOptByName(fields, "browser_cache_settings"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.BrowserCacheSettings = &gcdn.BrowserCacheSettings{
-			Enabled: enabled,
-			Value:   opt["value"].(string),
-		}
-	}
-	if opt, ok := getOptByName(fields, "host_header"); ok {
-		opts.HostHeader = &gcdn.HostHeader{
-			Enabled: opt["enabled"].(bool),
-			Value:   opt["value"].(string),
-		}
-	}
-	if opt, ok := getOptByName(fields, "redirect_http_to_https"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.RedirectHttpToHttps = &gcdn.RedirectHttpToHttps{
-			Enabled: enabled,
-			Value:   opt["value"].(bool),
-		}
-	}
-	if opt, ok := getOptByName(fields, "gzip_on"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.GzipOn = &gcdn.GzipOn{
-			Enabled: enabled,
-			Value:   opt["value"].(bool),
-		}
-	}
-	if opt, ok := getOptByName(fields, "cors"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.Cors = &gcdn.Cors{
-			Enabled: enabled,
-		}
-		for _, v := range opt["value"].(*schema.Set).List() {
-			opts.Cors.Value = append(opts.Cors.Value, v.(string))
-		}
-	}
-	if opt, ok := getOptByName(fields, "rewrite"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.Rewrite = &gcdn.Rewrite{
-			Enabled: enabled,
-			Body:    opt["body"].(string),
-			Flag:    opt["flag"].(string),
-		}
-	}
-	if opt, ok := getOptByName(fields, "webp"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.Webp = &gcdn.Webp{
-			Enabled:     enabled,
-			JPGQuality:  opt["jpg_quality"].(int),
-			PNGQuality:  opt["png_quality"].(int),
-			PNGLossless: opt["png_lossless"].(bool),
-		}
-	}
-	if opt, ok := getOptByName(fields, "sni"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.SNI = &gcdn.SNIOption{
-			Enabled:        enabled,
-			SNIType:        opt["sni_type"].(string),
-			CustomHostname: opt["custom_hostname"].(string),
-		}
-	}
-	if opt, ok := getOptByName(fields, "ignore_query_string"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.IgnoreQueryString = &gcdn.IgnoreQueryString{
-			Enabled: enabled,
-			Value:   opt["value"].(bool),
-		}
-	}
-	if opt, ok := getOptByName(fields, "query_params_whitelist"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.QueryParamsWhitelist = &gcdn.QueryParamsWhitelist{
-			Enabled: enabled,
-		}
-		for _, v := range opt["value"].(*schema.Set).List() {
-			opts.QueryParamsWhitelist.Value = append(opts.QueryParamsWhitelist.Value, v.(string))
-		}
-	}
-	if opt, ok := getOptByName(fields, "query_params_blacklist"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.QueryParamsBlacklist = &gcdn.QueryParamsBlacklist{
-			Enabled: enabled,
-		}
-		for _, v := range opt["value"].(*schema.Set).List() {
-			opts.QueryParamsBlacklist.Value = append(opts.QueryParamsBlacklist.Value, v.(string))
-		}
-	}
-	if opt, ok := getOptByName(fields, "static_request_headers"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.StaticRequestHeaders = &gcdn.StaticRequestHeaders{
-			Enabled: enabled,
-			Value:   map[string]string{},
-		}
-		for k, v := range opt["value"].(map[string]interface{}) {
-			opts.StaticRequestHeaders.Value[k] = v.(string)
-		}
-	}
-	if opt, ok := getOptByName(fields, "static_headers"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.StaticHeaders = &gcdn.StaticHeaders{
-			Enabled: enabled,
-			Value:   map[string]string{},
-		}
-		for k, v := range opt["value"].(map[string]interface{}) {
-			opts.StaticHeaders.Value[k] = v.(string)
+	v := reflect.ValueOf(&opts).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		opt, ok := getOptByName(fields, cdnOptionName(v.Type().Field(i)))
+		if !ok {
+			continue
 		}
+		option := reflect.New(v.Field(i).Type().Elem())
+		setOptionFields(option.Elem(), opt)
+		v.Field(i).Set(option)
 	}
-	if opt, ok := getOptByName(fields, "websockets"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
-		}
-		opts.WebSockets = &gcdn.WebSockets{
-			Enabled: enabled,
-			Value:   opt["value"].(bool),
+	return &opts
+}
+
+// cdnOptionName returns the schema name of the option field of gcdn.Options,
+// the API names some options in camel case.
+func cdnOptionName(field reflect.StructField) string {
+	var name strings.Builder
+	for i, r := range strings.Split(field.Tag.Get("json"), ",")[0] {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				name.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
 		}
+		name.WriteRune(r)
 	}
-	if opt, ok := getOptByName(fields, "tls_versions"); ok {
-		enabled := true
-		if _, ok := opt["enabled"]; ok {
-			enabled = opt["enabled"].(bool)
+	return name.String()
+}
+
+// setOptionFields sets the fields of the option struct from the schema
+// values, the option is enabled unless it is disabled explicitly.
+func setOptionFields(option reflect.Value, opt map[string]interface{}) {
+	for i := 0; i < option.NumField(); i++ {
+		field := option.Field(i)
+		value, ok := opt[option.Type().Field(i).Tag.Get("json")]
+		if !ok && option.Type().Field(i).Name == "Enabled" {
+			field.SetBool(true)
+			continue
 		}
-		opts.TLSVersions = &gcdn.TLSVersions{
-			Enabled: enabled,
+		if !ok || value == nil {
+			continue
 		}
-		for _, v := range opt["value"].(*schema.Set).List() {
-			opts.TLSVersions.Value = append(opts.TLSVersions.Value, v.(string))
+
+		switch field.Kind() {
+		case reflect.Slice:
+			items, ok := value.([]interface{})
+			if set, isSet := value.(*schema.Set); isSet {
+				items, ok = set.List(), true
+			}
+			if !ok {
+				continue
+			}
+			for _, item := range items {
+				if field.Type().Elem().Kind() == reflect.Struct {
+					elem := reflect.New(field.Type().Elem()).Elem()
+					setOptionFields(elem, item.(map[string]interface{}))
+					field.Set(reflect.Append(field, elem))
+					continue
+				}
+				field.Set(reflect.Append(field, reflect.ValueOf(item).Convert(field.Type().Elem())))
+			}
+		case reflect.Map:
+			m := reflect.MakeMap(field.Type())
+			for k, item := range value.(map[string]interface{}) {
+				m.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(item).Convert(field.Type().Elem()))
+			}
+			field.Set(m)
+		default:
+			field.Set(reflect.ValueOf(value).Convert(field.Type()))
 		}
 	}
-	return &opts
 }
 
 func getOptByName(fields map[string]interface{}, name string) (map[string]interface{}, bool) {
@@ -807,69 +1342,16 @@ func getOptByName(fields map[string]interface{}, name string) (map[string]interf
 
 func optionsToList(options *gcdn.Options) []interface{} {
 	result := make(map[string][]interface{})
-	if options.EdgeCacheSettings != nil {
-		m := structToMap(options.EdgeCacheSettings)
-		result["edge_cache_settings"] = []interface{}{m}
+	if options == nil {
+		return []interface{}{result}
 	}
-	if options.BrowserCacheSettings != nil {
-		m := structToMap(options.BrowserCacheSettings)
-		result["browser_cache_settings"] = []interface{}{m}
-	}
-	if options.HostHeader != nil {
-		m := structToMap(options.HostHeader)
-		result["host_header"] = []interface{}{m}
-	}
-	if options.RedirectHttpToHttps != nil {
-		m := structToMap(options.RedirectHttpToHttps)
-		result["redirect_http_to_https"] = []interface{}{m}
-	}
-	if options.GzipOn != nil {
-		m := structToMap(options.GzipOn)
-		result["gzip_on"] = []interface{}{m}
-	}
-	if options.Cors != nil {
-		m := structToMap(options.Cors)
-		result["cors"] = []interface{}{m}
-	}
-	if options.Rewrite != nil {
-		m := structToMap(options.Rewrite)
-		result["rewrite"] = []interface{}{m}
-	}
-	if options.Webp != nil {
-		m := structToMap(options.Webp)
-		result["webp"] = []interface{}{m}
-	}
-	if options.SNI != nil {
-		m := structToMap(options.SNI)
-		result["sni"] = []interface{}{m}
-	}
-	if options.IgnoreQueryString != nil {
-		m := structToMap(options.IgnoreQueryString)
-		result["ignore_query_string"] = []interface{}{m}
-	}
-	if options.QueryParamsWhitelist != nil {
-		m := structToMap(options.QueryParamsWhitelist)
-		result["query_params_whitelist"] = []interface{}{m}
-	}
-	if options.QueryParamsBlacklist != nil {
-		m := structToMap(options.QueryParamsBlacklist)
-		result["query_params_blacklist"] = []interface{}{m}
-	}
-	if options.StaticRequestHeaders != nil {
-		m := structToMap(options.StaticRequestHeaders)
-		result["static_request_headers"] = []interface{}{m}
-	}
-	if options.StaticHeaders != nil {
-		m := structToMap(options.StaticHeaders)
-		result["static_headers"] = []interface{}{m}
-	}
-	if options.WebSockets != nil {
-		m := structToMap(options.WebSockets)
-		result["websockets"] = []interface{}{m}
-	}
-	if options.TLSVersions != nil {
-		m := structToMap(options.TLSVersions)
-		result["tls_versions"] = []interface{}{m}
+	v := reflect.ValueOf(options).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).IsNil() {
+			continue
+		}
+		m := structToMap(v.Field(i).Interface())
+		result[cdnOptionName(v.Type().Field(i))] = []interface{}{m}
 	}
 	return []interface{}{result}
 }
@@ -891,9 +1373,16 @@ func structToMap(item interface{}) map[string]interface{} {
 		tag := v.Field(i).Tag.Get("json")
 		field := reflectValue.Field(i).Interface()
 		if tag != "" && tag != "-" {
-			if v.Field(i).Type.Kind() == reflect.Struct {
+			switch t := v.Field(i).Type; {
+			case t.Kind() == reflect.Struct:
 				res[tag] = structToMap(field)
-			} else {
+			case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct:
+				items := make([]interface{}, 0, reflectValue.Field(i).Len())
+				for j := 0; j < reflectValue.Field(i).Len(); j++ {
+					items = append(items, structToMap(reflectValue.Field(i).Index(j).Interface()))
+				}
+				res[tag] = items
+			default:
 				res[tag] = field
 			}
 		}
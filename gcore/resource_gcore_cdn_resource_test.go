@@ -5,10 +5,15 @@ package gcore
 
 import (
 	"fmt"
+	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
+	gcdn "github.com/G-Core/gcorelabscdn-go/gcore"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccCDNResource(t *testing.T) {
@@ -60,3 +65,170 @@ resource "gcore_cdn_resource" "acctest" {
 		},
 	})
 }
+
+func TestUnitCDNResource(t *testing.T) {
+	api := newMockAPI(t)
+	groupID := api.cdn.SeedOriginGroup("origin-group", "example.com")
+	fullName := "gcore_cdn_resource.test"
+
+	template := func(description, options string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_cdn_resource" "test" {
+  cname               = "cdn.example.com"
+  description         = "%s"
+  origin_group        = %d
+  origin_protocol     = "HTTPS"
+  secondary_hostnames = ["cdn2.example.com"]
+
+  options {
+%s
+  }
+}
+`, description, groupID, options)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCDNCheckDestroy("gcore_cdn_resource", api.cdn.Resource),
+		Steps: []resource.TestStep{
+			{
+				Config: template("first", `
+    edge_cache_settings {
+      value = "10m"
+    }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "cname", "cdn.example.com"),
+					resource.TestCheckResourceAttr(fullName, "description", "first"),
+					resource.TestCheckResourceAttr(fullName, "origin_group", strconv.Itoa(groupID)),
+					resource.TestCheckResourceAttr(fullName, "origin_protocol", "HTTPS"),
+					resource.TestCheckResourceAttr(fullName, "secondary_hostnames.#", "1"),
+					resource.TestCheckResourceAttrSet(fullName, "status"),
+					resource.TestCheckResourceAttr(fullName, "options.0.edge_cache_settings.0.value", "10m"),
+				),
+			},
+			{
+				Config: template("second", `
+    edge_cache_settings {
+      value = "1h"
+    }
+    host_header {
+      value = "origin.example.com"
+    }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "description", "second"),
+					resource.TestCheckResourceAttr(fullName, "options.0.edge_cache_settings.0.value", "1h"),
+					resource.TestCheckResourceAttr(fullName, "options.0.host_header.0.value", "origin.example.com"),
+				),
+			},
+			{
+				Config: template("second", `
+    edge_cache_settings {
+      value = "1h"
+    }
+    country_acl {
+      policy_type     = "deny"
+      excepted_values = ["GB", "DE"]
+    }
+    follow_origin_redirect {
+      codes = [301, 302]
+    }
+    static_response_headers {
+      value {
+        name  = "X-Frame-Options"
+        value = ["DENY"]
+      }
+    }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "options.0.host_header.#", "0"),
+					resource.TestCheckResourceAttr(fullName, "options.0.country_acl.0.policy_type", "deny"),
+					resource.TestCheckResourceAttr(fullName, "options.0.country_acl.0.excepted_values.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "options.0.follow_origin_redirect.0.codes.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "options.0.static_response_headers.0.value.0.name", "X-Frame-Options"),
+					resource.TestCheckResourceAttr(fullName, "options.0.static_response_headers.0.value.0.always", "false"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						id, _ := strconv.Atoi(s.ID)
+						options := mockMap(api.cdn.Resource(id)["options"])
+						acl := mockMap(options["country_acl"])
+						headers := mockList(mockMap(options["static_response_headers"])["value"])
+						if acl["policy_type"] != "deny" || len(mockList(acl["excepted_values"])) != 2 || len(headers) != 1 {
+							return fmt.Errorf("options were not sent to the API: %v", options)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"origin", "ssl_automated"},
+			},
+		},
+	})
+}
+
+// TestUnitCDNOptionsRoundTrip sets every option of the SDK through the
+// options schema of the resource and of the rule, it fails when the SDK
+// gains an option the schema lacks.
+func TestUnitCDNOptionsRoundTrip(t *testing.T) {
+	elem := optionsSchema.Elem.(*schema.Resource).Schema
+
+	var want gcdn.Options
+	v := reflect.ValueOf(&want).Elem()
+	sdkNames := make(map[string]bool)
+	for i := 0; i < v.NumField(); i++ {
+		name := cdnOptionName(v.Type().Field(i))
+		sdkNames[name] = true
+		if elem[name] == nil {
+			t.Errorf("option %s of the SDK is missing in the options schema", name)
+			continue
+		}
+		option := reflect.New(v.Field(i).Type().Elem()).Elem()
+		unitFillOption(t, option, name)
+		v.Field(i).Set(option.Addr())
+	}
+	for name := range elem {
+		if !sdkNames[name] {
+			t.Errorf("option %s of the options schema is unknown to the SDK", name)
+		}
+	}
+
+	for _, r := range []string{"gcore_cdn_resource", "gcore_cdn_rule"} {
+		d := Provider().ResourcesMap[r].TestResourceData()
+		if err := d.Set("options", optionsToList(&want)); err != nil {
+			t.Fatalf("%s: set options: %v", r, err)
+		}
+		got := reflect.ValueOf(listToOptions(d.Get("options").([]interface{}))).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if !reflect.DeepEqual(got.Field(i).Interface(), v.Field(i).Interface()) {
+				t.Errorf("%s: option %s: got %+v, want %+v", r, cdnOptionName(v.Type().Field(i)),
+					got.Field(i).Elem().Interface(), v.Field(i).Elem().Interface())
+			}
+		}
+	}
+}
+
+// unitFillOption sets every field of the option struct to a non-zero value,
+// strings are set to their json names.
+func unitFillOption(t *testing.T, value reflect.Value, name string) {
+	switch value.Kind() {
+	case reflect.Bool:
+		value.SetBool(true)
+	case reflect.Int:
+		value.SetInt(42)
+	case reflect.String:
+		value.SetString(name)
+	case reflect.Slice:
+		item := reflect.New(value.Type().Elem()).Elem()
+		unitFillOption(t, item, name)
+		value.Set(reflect.Append(value, item))
+	case reflect.Map:
+		value.Set(reflect.ValueOf(map[string]string{"key": "value"}))
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			unitFillOption(t, value.Field(i), value.Type().Field(i).Tag.Get("json"))
+		}
+	default:
+		t.Fatalf("unsupported field %s of kind %s", name, value.Kind())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 
 	"github.com/AlekSi/pointer"
 	"github.com/G-Core/gcorelabscdn-go/rules"
@@ -15,12 +16,14 @@ import (
 func resourceCDNRule() *schema.Resource {
 	return &schema.Resource{
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceCDNRuleImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"resource_id": {
-				Type:     schema.TypeInt,
-				Required: true,
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the CDN resource the rule belongs to.",
 			},
 			"name": {
 				Type:        schema.TypeString,
@@ -62,6 +65,26 @@ func resourceCDNRule() *schema.Resource {
 	}
 }
 
+// resourceCDNRuleImport imports a rule by "resource_id:rule_id", the API
+// needs both IDs.
+func resourceCDNRuleImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("format must be as resource_id:rule_id, got %q", d.Id())
+	}
+	resourceID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource_id %q: %w", parts[0], err)
+	}
+	if _, err := strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid rule_id %q: %w", parts[1], err)
+	}
+	_ = d.Set("resource_id", resourceID)
+	d.SetId(parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceCDNRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start CDN Rule creating")
 	config := m.(*Config)
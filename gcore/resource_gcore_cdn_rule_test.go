@@ -4,10 +4,13 @@
 package gcore
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccCDNRule(t *testing.T) {
@@ -75,3 +78,109 @@ resource "gcore_cdn_rule" "acctest" {
 		},
 	})
 }
+
+func TestUnitCDNRule(t *testing.T) {
+	api := newMockAPI(t)
+	groupID := api.cdn.SeedOriginGroup("origin-group", "example.com")
+	resourceID := api.cdn.SeedResource("cdn.example.com", groupID)
+	otherResourceID := api.cdn.SeedResource("cdn.example.org", groupID)
+	fullName := "gcore_cdn_rule.test"
+
+	template := func(resourceID int, pattern string, weight int) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_cdn_rule" "test" {
+  resource_id     = %d
+  name            = "rule"
+  rule            = "%s"
+  rule_type       = 0
+  weight          = %d
+  origin_protocol = "HTTPS"
+
+  options {
+    edge_cache_settings {
+      value = "5m"
+    }
+  }
+}
+`, resourceID, pattern, weight)
+	}
+
+	var firstID string
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			for _, rs := range s.RootModule().Resources {
+				if rs.Type != "gcore_cdn_rule" {
+					continue
+				}
+				id, _ := strconv.Atoi(rs.Primary.ID)
+				if api.cdn.Rule(otherResourceID, id) != nil {
+					return fmt.Errorf("rule %s still exists", rs.Primary.ID)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: template(resourceID, "/images/*", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "rule"),
+					resource.TestCheckResourceAttr(fullName, "rule", "/images/*"),
+					resource.TestCheckResourceAttr(fullName, "weight", "1"),
+					resource.TestCheckResourceAttr(fullName, "options.0.edge_cache_settings.0.value", "5m"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						firstID = s.ID
+						return nil
+					}),
+				),
+			},
+			{
+				Config: template(resourceID, "/static/*", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "rule", "/static/*"),
+					resource.TestCheckResourceAttr(fullName, "weight", "2"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if s.ID != firstID {
+							return fmt.Errorf("rule was replaced on update: %s -> %s", firstID, s.ID)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				// A rule can't move to another resource.
+				Config: template(otherResourceID, "/static/*", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "resource_id", strconv.Itoa(otherResourceID)),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						id, _ := strconv.Atoi(firstID)
+						if api.cdn.Rule(resourceID, id) != nil {
+							return fmt.Errorf("rule %s of resource %d still exists", firstID, resourceID)
+						}
+						id, _ = strconv.Atoi(s.ID)
+						if api.cdn.Rule(otherResourceID, id) == nil {
+							return fmt.Errorf("rule %s of resource %d not found", s.ID, otherResourceID)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName: fullName,
+				ImportState:  true,
+				ImportStateIdFunc: unitImportID(fullName, func(s *terraform.InstanceState) string {
+					return s.Attributes["resource_id"] + ":" + s.ID
+				}),
+				ImportStateVerify: true,
+			},
+		},
+	})
+
+	for _, id := range []string{"12", "cdn:12", "1:rule"} {
+		d := resourceCDNRule().TestResourceData()
+		d.SetId(id)
+		if _, err := resourceCDNRuleImport(context.Background(), d, nil); err == nil {
+			t.Errorf("import of %q should fail", id)
+		}
+	}
+}
@@ -24,7 +24,13 @@ func resourceDDoSProtection() *schema.Resource {
 		ReadContext:   resourceDDoSProtectionRead,
 		UpdateContext: resourceDDoSProtectionUpdate,
 		DeleteContext: resourceDDoSProtectionDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represents DDoS protection profile",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Duration(ddosProfileCreatingTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(ddosProfileUpdatingTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(ddosProfileDeletingTimeout) * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, profileID, err := ImportStringParser(d.Id())
@@ -41,36 +47,26 @@ func resourceDDoSProtection() *schema.Resource {
 		},
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"ip_address": {
 				Type:        schema.TypeString,
@@ -193,9 +189,8 @@ func resourceDDoSProtectionCreate(ctx context.Context, d *schema.ResourceData, m
 	log.Println("[DEBUG] Start DDoS protection profile creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, ddosProfilePoint, versionPointV1)
+	client, err := CreateClient(config, d, ddosProfilePoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -222,7 +217,7 @@ func resourceDDoSProtectionCreate(ctx context.Context, d *schema.ResourceData, m
 
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	profileID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, ddosProfileCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
+	profileID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -248,8 +243,7 @@ func resourceDDoSProtectionCreate(ctx context.Context, d *schema.ResourceData, m
 		}
 
 		taskID = results.Tasks[0]
-		if err = tasks.WaitForStatus(client, string(taskID),
-			tasks.TaskStateFinished, ddosProfileCreatingTimeout, true); err != nil {
+		if _, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), nil); err != nil {
 			log.Printf("[DEBUG] failed to activate/disactivate profile: %v", err)
 		}
 	}
@@ -262,8 +256,7 @@ func resourceDDoSProtectionCreate(ctx context.Context, d *schema.ResourceData, m
 		}
 
 		taskID = results.Tasks[0]
-		if err = tasks.WaitForStatus(client, string(taskID),
-			tasks.TaskStateFinished, ddosProfileCreatingTimeout, true); err != nil {
+		if _, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), nil); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -281,7 +274,6 @@ func resourceDDoSProtectionRead(ctx context.Context, d *schema.ResourceData, m i
 	log.Printf("[DEBUG] Start DDoS protection profile reading %s", d.State())
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	profileID, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -289,7 +281,7 @@ func resourceDDoSProtectionRead(ctx context.Context, d *schema.ResourceData, m i
 
 	log.Printf("[DEBUG] DDoS profile id = %d", profileID)
 
-	client, err := CreateClient(provider, d, ddosProfilePoint, versionPointV1)
+	client, err := CreateClient(config, d, ddosProfilePoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -366,8 +358,7 @@ func resourceDDoSProtectionUpdate(ctx context.Context, d *schema.ResourceData, m
 
 	log.Printf("[DEBUG] DDoS protection profile id = %d", profileID)
 	config := m.(*Config)
-	provider := config.Provider
-	client, err := CreateClient(provider, d, ddosProfilePoint, versionPointV1)
+	client, err := CreateClient(config, d, ddosProfilePoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -423,8 +414,7 @@ func resourceDDoSProtectionUpdate(ctx context.Context, d *schema.ResourceData, m
 
 		taskID := results.Tasks[0]
 		log.Printf("[DEBUG] Task id (%s)", taskID)
-		if err := tasks.WaitForStatus(client, string(taskID),
-			tasks.TaskStateFinished, ddosProfileUpdatingTimeout, true); err != nil {
+		if _, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), nil); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -438,8 +428,7 @@ func resourceDDoSProtectionUpdate(ctx context.Context, d *schema.ResourceData, m
 
 		taskID := results.Tasks[0]
 		log.Printf("[DEBUG] Task id (%s)", taskID)
-		if err := tasks.WaitForStatus(client, string(taskID),
-			tasks.TaskStateFinished, ddosProfileUpdatingTimeout, true); err != nil {
+		if _, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), nil); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -453,14 +442,13 @@ func resourceDDoSProtectionDelete(ctx context.Context, d *schema.ResourceData, m
 	log.Println("[DEBUG] Start DDoS protection profile deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	profileID, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] DDoS profile id = %d", profileID)
 
-	client, err := CreateClient(provider, d, ddosProfilePoint, versionPointV1)
+	client, err := CreateClient(config, d, ddosProfilePoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -471,7 +459,7 @@ func resourceDDoSProtectionDelete(ctx context.Context, d *schema.ResourceData, m
 	}
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	err = tasks.WaitForStatus(client, string(taskID), tasks.TaskStateFinished, ddosProfileDeletingTimeout, true)
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), nil)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -126,3 +126,88 @@ func checkDestroyDDoSProtectionProfile(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitDDoSProtection(t *testing.T) {
+	api := newMockAPI(t)
+	networkID := api.cloud.Seed("networks", map[string]interface{}{"name": "network"})
+	subnetID := api.cloud.Seed("subnets", map[string]interface{}{
+		"name":       "subnet",
+		"cidr":       "192.168.10.0/24",
+		"network_id": networkID,
+	})
+	bmID := api.cloud.Seed("bminstances", map[string]interface{}{
+		"names":    []interface{}{"bm"},
+		"flavor":   "bm1-infrastructure-small",
+		"image_id": "1ee7ccee-5003-48c9-8ae0-d96063af75b2",
+		"interfaces": []interface{}{
+			map[string]interface{}{"type": "subnet", "network_id": networkID, "subnet_id": subnetID},
+		},
+	})
+	fullName := "gcore_ddos_protection.test"
+
+	template := func(active bool, value string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_ddos_protection" "test" {
+  %s
+  ip_address       = "192.168.10.2"
+  profile_template = 63
+  bm_instance_id   = "%s"
+  active           = %t
+
+  fields {
+    base_field = 118
+    value      = "%s"
+  }
+}
+`, mockCloudScope, bmID, active, value)
+	}
+	profile := func(s *terraform.InstanceState) map[string]interface{} {
+		return api.cloud.Get("ddos/profiles", s.ID)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_ddos_protection", "ddos/profiles"),
+		Steps: []resource.TestStep{
+			{
+				Config: template(true, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "ip_address", "192.168.10.2"),
+					resource.TestCheckResourceAttr(fullName, "profile_template", "63"),
+					resource.TestCheckResourceAttr(fullName, "active", "true"),
+					resource.TestCheckResourceAttr(fullName, "bgp", "true"),
+					resource.TestCheckResourceAttr(fullName, "site", "ED"),
+					resource.TestCheckResourceAttr(fullName, "fields.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "fields.0.base_field", "118"),
+					resource.TestCheckResourceAttr(fullName, "fields.0.value", "1"),
+					resource.TestCheckResourceAttr(fullName, "protocols.0.port", "80"),
+					resource.TestCheckResourceAttr(fullName, "protocols.0.protocols.0", "TCP"),
+				),
+			},
+			{
+				Config: template(false, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "active", "false"),
+					resource.TestCheckResourceAttr(fullName, "fields.0.value", "2"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						p := profile(s)
+						if mockBool(mockMap(p["options"])["active"]) {
+							return fmt.Errorf("profile %s wasn't deactivated", s.ID)
+						}
+						if v := mockMap(mockList(p["fields"])[0])["value"]; v != "2" {
+							return fmt.Errorf("field wasn't updated: %v", v)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "project_name", "region_name", "bm_instance_id"},
+			},
+		},
+	})
+}
@@ -13,8 +13,11 @@ import (
 )
 
 const (
-	DNSZoneResource   = "gcore_dns_zone"
-	DNSZoneSchemaName = "name"
+	DNSZoneResource       = "gcore_dns_zone"
+	DNSZoneSchemaName     = "name"
+	DNSZoneSchemaZoneFile = "zone_file"
+	DNSZoneSchemaDNSSEC   = "dnssec"
+	DNSZoneSchemaDNSSECDS = "dnssec_ds"
 )
 
 func resourceDNSZone() *schema.Resource {
@@ -33,12 +36,29 @@ func resourceDNSZone() *schema.Resource {
 				},
 				Description: "A name of DNS Zone resource.",
 			},
+			DNSZoneSchemaZoneFile: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Records of the zone in BIND zone file format, e.g. to migrate it to another DNS host.",
+			},
+			DNSZoneSchemaDNSSEC: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the zone is signed by DNSSEC. It isn't read back from the API, a change made outside of Terraform is not detected.",
+			},
+			DNSZoneSchemaDNSSECDS: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A DS record of the zone to publish at the registrar when dnssec is enabled.",
+			},
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 		CreateContext: checkDNSDependency(resourceDNSZoneCreate),
+		UpdateContext: checkDNSDependency(resourceDNSZoneUpdate),
 		ReadContext:   checkDNSDependency(resourceDNSZoneRead),
 		DeleteContext: checkDNSDependency(resourceDNSZoneDelete),
 		Description:   "Represent DNS zone resource. https://dns.gcorelabs.com/zones",
@@ -75,6 +95,32 @@ func resourceDNSZoneCreate(ctx context.Context, d *schema.ResourceData, m interf
 	}
 	d.SetId(name)
 
+	if d.Get(DNSZoneSchemaDNSSEC).(bool) {
+		if _, err := client.ToggleDnssec(ctx, name, true); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceDNSZoneRead(ctx, d, m)
+}
+
+func resourceDNSZoneUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	zoneName := dnsZoneResourceID(d)
+	log.Printf("[DEBUG] Start DNS Zone Resource updating (id=%s)\n", zoneName)
+	defer log.Println("[DEBUG] Finish DNS Zone Resource updating")
+
+	config := m.(*Config)
+	client := config.DNSClient
+
+	if d.HasChange(DNSZoneSchemaDNSSEC) {
+		if _, err := client.ToggleDnssec(ctx, zoneName, d.Get(DNSZoneSchemaDNSSEC).(bool)); err != nil {
+			// The zone keeps the old value.
+			old, _ := d.GetChange(DNSZoneSchemaDNSSEC)
+			_ = d.Set(DNSZoneSchemaDNSSEC, old)
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceDNSZoneRead(ctx, d, m)
 }
 
@@ -92,6 +138,20 @@ func resourceDNSZoneRead(ctx context.Context, d *schema.ResourceData, m interfac
 	}
 	d.SetId(result.Name)
 	_ = d.Set(DNSZoneSchemaName, result.Name)
+	_ = d.Set(DNSZoneSchemaZoneFile, renderDNSZoneFile(result.Name, dnsZoneFileRRSetsFromZone(result)))
+
+	// dnssec keeps the value the zone was set to, an imported zone is
+	// assumed unsigned.
+	dnssec, ds := d.Get(DNSZoneSchemaDNSSEC).(bool), ""
+	if dnssec {
+		res, err := client.DNSSecDS(ctx, result.Name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		ds = res.Ds
+	}
+	_ = d.Set(DNSZoneSchemaDNSSEC, dnssec)
+	_ = d.Set(DNSZoneSchemaDNSSECDS, ds)
 
 	return nil
 }
@@ -56,6 +56,9 @@ func resourceDNSZoneRecord() *schema.Resource {
 					}
 					return nil
 				},
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return suppressDNSNameDiff(old, new)
+				},
 				Description: "A zone of DNS Zone Record resource.",
 			},
 			DNSZoneRecordSchemaDomain: {
@@ -69,6 +72,9 @@ func resourceDNSZoneRecord() *schema.Resource {
 					}
 					return nil
 				},
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return suppressDNSNameDiff(old, new)
+				},
 				Description: "A domain of DNS Zone Record resource.",
 			},
 			DNSZoneRecordSchemaType: {
@@ -76,17 +82,13 @@ func resourceDNSZoneRecord() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 				ValidateDiagFunc: func(i interface{}, path cty.Path) diag.Diagnostics {
-					val := strings.TrimSpace(i.(string))
-					types := []string{"A", "AAAA", "MX", "CNAME", "TXT", "CAA", "NS", "SRV"}
-					for _, t := range types {
-						if strings.EqualFold(t, val) {
-							return nil
-						}
+					val := strings.ToUpper(strings.TrimSpace(i.(string)))
+					if _, ok := dnsRecordTypes[val]; !ok {
+						return diag.Errorf("dns record type should be one of %v", dnsRecordTypeNames())
 					}
-					return diag.Errorf("dns record type should be one of %v", types)
-
+					return nil
 				},
-				Description: "A type of DNS Zone Record resource.",
+				Description: fmt.Sprintf("A type of DNS Zone Record resource, one of %s.", strings.Join(dnsRecordTypeNames(), ", ")),
 			},
 			DNSZoneRecordSchemaTTL: {
 				Type:     schema.TypeInt,
@@ -141,7 +143,7 @@ func resourceDNSZoneRecord() *schema.Resource {
 						DNSZoneRecordSchemaContent: {
 							Type:        schema.TypeString,
 							Required:    true,
-							Description: `A content of DNS Zone Record resource. (TXT: 'anyString', MX: '50 mail.company.io.', CAA: '0 issue "company.org; account=12345"')`,
+							Description: `A content of DNS Zone Record resource, validated for the type at plan time. Names are compared case-insensitively with or without the trailing dot. (A: '192.0.2.1', AAAA: '2001:db8::1', CNAME, NS, PTR: 'host.company.io.', TXT: 'anyString', MX: '50 mail.company.io.', SRV: '10 5 5060 sip.company.io.', CAA: '0 issue "company.org; account=12345"', HTTPS, SVCB: '1 . alpn=h2,h3 port=443 ipv4hint=192.0.2.1')`,
 						},
 						DNSZoneRecordSchemaEnabled: {
 							Type:        schema.TypeBool,
@@ -237,6 +239,7 @@ func resourceDNSZoneRecord() *schema.Resource {
 			Create: schema.DefaultTimeout(5 * time.Minute),
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
+		CustomizeDiff: resourceDNSZoneRecordCustomizeDiff,
 		CreateContext: checkDNSDependency(resourceDNSZoneRecordCreate),
 		UpdateContext: checkDNSDependency(resourceDNSZoneRecordUpdate),
 		ReadContext:   checkDNSDependency(resourceDNSZoneRecordRead),
@@ -356,20 +359,8 @@ func resourceDNSZoneRecordRead(ctx context.Context, d *schema.ResourceData, m in
 		_ = d.Set(DNSZoneRecordSchemaFilter, filters)
 	}
 
-	rr := make([]map[string]interface{}, 0)
-	for _, rec := range result.Records {
-		r := map[string]interface{}{}
-		r[DNSZoneRecordSchemaEnabled] = rec.Enabled
-		r[DNSZoneRecordSchemaContent] = rec.ContentToString()
-		meta := map[string]interface{}{}
-		for key, val := range rec.Meta {
-			meta[key] = val
-		}
-		if len(meta) > 0 {
-			r[DNSZoneRecordSchemaMeta] = []map[string]interface{}{meta}
-		}
-		rr = append(rr, r)
-	}
+	rr := flattenDNSResourceRecords(result.Records)
+	keepDNSResourceRecordsContent(rType, rr, d.Get(DNSZoneRecordSchemaResourceRecord).(*schema.Set).List())
 	if len(rr) > 0 {
 		_ = d.Set(DNSZoneRecordSchemaResourceRecord, rr)
 	}
@@ -400,6 +391,65 @@ func resourceDNSZoneRecordDelete(ctx context.Context, d *schema.ResourceData, m
 	return nil
 }
 
+// flattenDNSResourceRecords converts records to resource_record elements.
+func flattenDNSResourceRecords(records []dnssdk.ResourceRecord) []map[string]interface{} {
+	rr := make([]map[string]interface{}, 0, len(records))
+	for _, rec := range records {
+		r := map[string]interface{}{}
+		r[DNSZoneRecordSchemaEnabled] = rec.Enabled
+		r[DNSZoneRecordSchemaContent] = rec.ContentToString()
+		meta := map[string]interface{}{}
+		for key, val := range rec.Meta {
+			meta[key] = val
+		}
+		if len(meta) > 0 {
+			r[DNSZoneRecordSchemaMeta] = []map[string]interface{}{meta}
+		}
+		rr = append(rr, r)
+	}
+	return rr
+}
+
+// keepDNSResourceRecordsContent keeps the content of the current records
+// equal to the read one once normalised, so that a name without the trailing
+// dot or an IPv6 address spelled otherwise doesn't show up as a change. Other
+// read content is normalised, e.g. on import.
+func keepDNSResourceRecordsContent(rType string, rr []map[string]interface{}, current []interface{}) {
+	contents := make(map[string]string)
+	for _, c := range current {
+		content := c.(map[string]interface{})[DNSZoneRecordSchemaContent].(string)
+		if normalized, err := normalizeDNSRecordContent(rType, content); err == nil {
+			contents[normalized] = content
+		}
+	}
+	for _, r := range rr {
+		normalized, err := normalizeDNSRecordContent(rType, r[DNSZoneRecordSchemaContent].(string))
+		if err != nil {
+			continue
+		}
+		if content, ok := contents[normalized]; ok {
+			normalized = content
+		}
+		r[DNSZoneRecordSchemaContent] = normalized
+	}
+}
+
+// resourceDNSZoneRecordCustomizeDiff validates the content of the records for
+// the type at plan time, values unknown yet are checked on apply.
+func resourceDNSZoneRecordCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown(DNSZoneRecordSchemaType) || !d.NewValueKnown(DNSZoneRecordSchemaResourceRecord) {
+		return nil
+	}
+	rType := strings.TrimSpace(d.Get(DNSZoneRecordSchemaType).(string))
+	for _, resource := range d.Get(DNSZoneRecordSchemaResourceRecord).(*schema.Set).List() {
+		content := resource.(map[string]interface{})[DNSZoneRecordSchemaContent].(string)
+		if _, err := normalizeDNSRecordContent(rType, content); err != nil {
+			return fmt.Errorf("invalid %s content %q: %w", strings.ToUpper(rType), content, err)
+		}
+	}
+	return nil
+}
+
 func fillRRSet(d *schema.ResourceData, rType string, rrSet *dnssdk.RRSet) error {
 	// set filters
 	for _, resource := range d.Get(DNSZoneRecordSchemaFilter).(*schema.Set).List() {
@@ -420,8 +470,11 @@ func fillRRSet(d *schema.ResourceData, rType string, rrSet *dnssdk.RRSet) error
 	// set meta
 	for _, resource := range d.Get(DNSZoneRecordSchemaResourceRecord).(*schema.Set).List() {
 		data := resource.(map[string]interface{})
-		content := data[DNSZoneRecordSchemaContent].(string)
-		rr := (&dnssdk.ResourceRecord{}).SetContent(rType, content)
+		content, err := normalizeDNSRecordContent(rType, data[DNSZoneRecordSchemaContent].(string))
+		if err != nil {
+			return fmt.Errorf("invalid content %q: %w", data[DNSZoneRecordSchemaContent], err)
+		}
+		rr := newDNSResourceRecord(rType, content)
 		enabled := data[DNSZoneRecordSchemaEnabled].(bool)
 		rr.Enabled = enabled
 		metaErrs := make([]error, 0)
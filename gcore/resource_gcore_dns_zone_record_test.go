@@ -5,10 +5,15 @@ package gcore
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccDnsZoneRecord(t *testing.T) {
@@ -184,3 +189,213 @@ resource "%s" "%s" {
 		},
 	})
 }
+
+func TestUnitDNSZoneRecord(t *testing.T) {
+	api := newMockAPI(t)
+	zone := api.dns.SeedZone("example.com")
+	fullName := DNSZoneRecordResource + ".test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(*terraform.State) error {
+			if api.dns.RRSet(zone, "www."+zone, "A") != nil {
+				return fmt.Errorf("rrset still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + `
+resource "gcore_dns_zone_record" "test" {
+  zone   = "example.com"
+  domain = "www.example.com"
+  type   = "A"
+  ttl    = 120
+
+  resource_record {
+    content = "127.0.0.100"
+  }
+
+  resource_record {
+    content = "127.0.0.200"
+    enabled = false
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "zone", zone),
+					resource.TestCheckResourceAttr(fullName, "domain", "www."+zone),
+					resource.TestCheckResourceAttr(fullName, "type", "A"),
+					resource.TestCheckResourceAttr(fullName, "ttl", "120"),
+					resource.TestCheckResourceAttr(fullName, "resource_record.#", "2"),
+				),
+			},
+			{
+				Config: api.providerHCL("") + `
+resource "gcore_dns_zone_record" "test" {
+  zone   = "example.com"
+  domain = "www.example.com"
+  type   = "A"
+  ttl    = 300
+
+  resource_record {
+    content = "127.0.0.100"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "ttl", "300"),
+					resource.TestCheckResourceAttr(fullName, "resource_record.#", "1"),
+					func(*terraform.State) error {
+						rrset := api.dns.RRSet(zone, "www."+zone, "A")
+						if len(mockList(rrset["resource_records"])) != 1 {
+							return fmt.Errorf("rrset was not updated: %v", rrset)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      fullName,
+				ImportState:       true,
+				ImportStateId:     zone + ":www." + zone + ":A",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestUnitDNSZoneRecordContent(t *testing.T) {
+	api := newMockAPI(t)
+	zone := api.dns.SeedZone("example.com")
+	fullName := DNSZoneRecordResource + ".test"
+
+	template := func(contents ...string) string {
+		var records string
+		for _, c := range contents {
+			records += fmt.Sprintf(`
+  resource_record {
+    content = "%s"
+  }
+`, c)
+		}
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_dns_zone_record" "test" {
+  zone   = "example.com"
+  domain = "example.com."
+  type   = "MX"
+  ttl    = 300
+%s}
+`, records)
+	}
+	valid := template("10 Mail.Example.com", "20 mx2.example.com.")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: valid,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "resource_record.#", "2"),
+					func(*terraform.State) error {
+						rrset := api.dns.RRSet(zone, zone, "MX")
+						var got []string
+						for _, rr := range mockList(rrset["resource_records"]) {
+							got = append(got, fmt.Sprint(mockMap(rr)["content"]))
+						}
+						sort.Strings(got)
+						if expected := []string{"[10 mail.example.com.]", "[20 mx2.example.com.]"}; !reflect.DeepEqual(got, expected) {
+							return fmt.Errorf("expected normalised content %v, got %v", expected, got)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// The API returning names without the trailing dot is no change.
+				PreConfig: func() {
+					api.dns.SeedRRSet(zone, zone, "MX", 300, []interface{}{10, "mail.example.com"}, []interface{}{20, "MX2.example.com"})
+				},
+				Config:   valid,
+				PlanOnly: true,
+			},
+			{
+				Config:      template("mail.example.com"),
+				ExpectError: regexp.MustCompile(`invalid MX content "mail.example.com": "mail.example.com" should be preference name`),
+			},
+			{
+				Config:      template("70000 mail.example.com"),
+				ExpectError: regexp.MustCompile(`invalid preference "70000", it should be 0-65535`),
+			},
+			{
+				Config: valid,
+			},
+			{
+				ResourceName:      fullName,
+				ImportState:       true,
+				ImportStateId:     zone + ":" + zone + ".:MX",
+				ImportStateVerify: true,
+				// The import reads the normalised content, not the spelling
+				// of the config.
+				ImportStateVerifyIgnore: []string{"resource_record"},
+			},
+		},
+	})
+}
+
+func TestUnitNormalizeDNSRecordContent(t *testing.T) {
+	for _, tc := range []struct {
+		rType, content, expected, err string
+	}{
+		{rType: "A", content: " 192.0.2.1 ", expected: "192.0.2.1"},
+		{rType: "A", content: "2001:db8::1", err: "expected an IPv4 address"},
+		{rType: "a", content: "192.0.2.300", err: "expected an IPv4 address"},
+		{rType: "AAAA", content: "2001:DB8:0:0:0:0:0:1", expected: "2001:db8::1"},
+		{rType: "AAAA", content: "192.0.2.1", err: "expected an IPv6 address"},
+		{rType: "CNAME", content: "WWW.Example.com", expected: "www.example.com."},
+		{rType: "CNAME", content: "www..example.com", err: "invalid name"},
+		{rType: "NS", content: "ns1.gcorelabs.net.", expected: "ns1.gcorelabs.net."},
+		{rType: "PTR", content: "host.example.com", expected: "host.example.com."},
+		{rType: "PTR", content: "host example.com", err: "should be name"},
+		{rType: "MX", content: "010  mail.example.com", expected: "10 mail.example.com."},
+		{rType: "MX", content: "-1 mail.example.com", err: "invalid preference"},
+		{rType: "SRV", content: "10 60 5060 _sip._tcp.example.com", expected: "10 60 5060 _sip._tcp.example.com."},
+		{rType: "SRV", content: "10 60 70000 sip.example.com", err: "invalid port"},
+		{rType: "SRV", content: "10 sip.example.com", err: "should be priority weight port name"},
+		{rType: "TXT", content: "v=spf1 -all", expected: "v=spf1 -all"},
+		{rType: "TXT", content: " ", err: "text is empty"},
+		{rType: "CAA", content: "0 ISSUE letsencrypt.org", expected: `0 issue "letsencrypt.org"`},
+		{rType: "CAA", content: `128 issue "company.org; account=12345"`, expected: `128 issue "company.org; account=12345"`},
+		{rType: "CAA", content: "256 issue letsencrypt.org", err: "invalid flags"},
+		{rType: "CAA", content: "0 unknown letsencrypt.org", err: "invalid tag"},
+		{rType: "HTTPS", content: `1 . ALPN="h2,h3" port=443 ipv6hint=2001:db8::1`, expected: "1 . alpn=h2,h3 port=443 ipv6hint=2001:db8::1"},
+		{rType: "HTTPS", content: "0 cdn.example.com", expected: "0 cdn.example.com."},
+		{rType: "HTTPS", content: "0 cdn.example.com alpn=h2", err: "alias form"},
+		{rType: "SVCB", content: "1 svc.example.com no-default-alpn key65000=x", expected: "1 svc.example.com. no-default-alpn key65000=x"},
+		{rType: "SVCB", content: "1 . ipv4hint=2001:db8::1", err: "invalid ipv4hint address"},
+		{rType: "SVCB", content: "1 . color=red", err: "invalid parameter"},
+		{rType: "HINFO", content: "PC Linux", err: "dns record type should be one of"},
+	} {
+		got, err := normalizeDNSRecordContent(tc.rType, tc.content)
+		switch {
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("%s %q: expected error %q, got %q, %v", tc.rType, tc.content, tc.err, got, err)
+		case tc.err == "" && (err != nil || got != tc.expected):
+			t.Errorf("%s %q: expected %q, got %q, %v", tc.rType, tc.content, tc.expected, got, err)
+		}
+	}
+
+	for rType := range dnsRecordTypes {
+		if _, ok := dnsZoneFileTypes[rType]; !ok {
+			t.Errorf("record type %s is missing in zone files", rType)
+		}
+	}
+	rr := newDNSResourceRecord("HTTPS", "1 . alpn=h2,h3 port=443")
+	if expected := []interface{}{int64(1), ".", "alpn=h2,h3", "port=443"}; !reflect.DeepEqual(rr.Content, expected) {
+		t.Errorf("expected HTTPS content %v, got %v", expected, rr.Content)
+	}
+	rr = newDNSResourceRecord("CAA", `0 issue "company.org; account=12345"`)
+	if expected := []interface{}{int64(0), "issue", `"company.org; account=12345"`}; !reflect.DeepEqual(rr.Content, expected) {
+		t.Errorf("expected CAA content %v, got %v", expected, rr.Content)
+	}
+}
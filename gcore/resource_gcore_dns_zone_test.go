@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccDnsZone(t *testing.T) {
@@ -42,3 +43,142 @@ resource "%s" "%s" {
 		},
 	})
 }
+
+func unitDNSCheckZoneDestroy(api *mockAPI) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type == DNSZoneResource && api.dns.HasZone(rs.Primary.ID) {
+				return fmt.Errorf("zone %s still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func TestUnitDNSZone(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := DNSZoneResource + ".test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitDNSCheckZoneDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + `
+resource "gcore_dns_zone" "test" {
+  name = "example.com"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "id", "example.com"),
+					resource.TestCheckResourceAttr(fullName, "name", "example.com"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if !api.dns.HasZone("example.com") {
+							return fmt.Errorf("zone was not created")
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:      fullName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestUnitDNSZoneDNSSEC(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := DNSZoneResource + ".test"
+
+	template := func(dnssec bool) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_dns_zone" "test" {
+  name   = "example.com"
+  dnssec = %t
+}
+`, dnssec)
+	}
+	checkDNSSEC := func(enabled bool) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			if api.dns.DNSSEC("example.com") != enabled {
+				return fmt.Errorf("expected dnssec of the zone %t", enabled)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitDNSCheckZoneDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: template(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "dnssec", "false"),
+					resource.TestCheckResourceAttr(fullName, "dnssec_ds", ""),
+					checkDNSSEC(false),
+				),
+			},
+			{
+				Config: template(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "dnssec", "true"),
+					resource.TestCheckResourceAttr(fullName, "dnssec_ds", "example.com. 3600 IN DS 10001 13 2 ABCDEF"),
+					checkDNSSEC(true),
+					func(*terraform.State) error {
+						if reqs := api.Requests("POST /dns/"); len(reqs) != 1 {
+							return fmt.Errorf("expected the zone to be updated in place, got %v", reqs)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: template(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "dnssec", "false"),
+					resource.TestCheckResourceAttr(fullName, "dnssec_ds", ""),
+					checkDNSSEC(false),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitDNSZoneFileExport(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := DNSZoneResource + ".test"
+	config := api.providerHCL("") + `
+resource "gcore_dns_zone" "test" {
+  name = "example.com"
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitDNSCheckZoneDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "zone_file", "$ORIGIN example.com.\n"),
+					func(*terraform.State) error {
+						api.dns.SeedRRSet("example.com", "example.com", "MX", 300, []interface{}{10, "mail.example.com"})
+						api.dns.SeedRRSet("example.com", "www.example.com", "TXT", 600, []interface{}{`say "hi"`})
+						return nil
+					},
+				),
+			},
+			{
+				// A refresh exports the records created meanwhile.
+				Config: config,
+				Check: resource.TestCheckResourceAttr(fullName, "zone_file", "$ORIGIN example.com.\n"+
+					"@\t300\tIN\tMX\t10 mail.example.com.\n"+
+					"www\t600\tIN\tTXT\t\"say \\\"hi\\\"\"\n"),
+			},
+		},
+	})
+}
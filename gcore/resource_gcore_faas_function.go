@@ -1,15 +1,24 @@
 package gcore
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	gcorecloud "github.com/G-Core/gcorelabscloud-go"
 	"github.com/G-Core/gcorelabscloud-go/gcore/faas/v1/faas"
 	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -24,7 +33,13 @@ func resourceFaaSFunction() *schema.Resource {
 		ReadContext:   resourceFaaSFunctionRead,
 		UpdateContext: resourceFaaSFunctionUpdate,
 		DeleteContext: resourceFaaSFunctionDelete,
+		CustomizeDiff: customdiff.All(cloudScopeDiff, resourceFaaSFunctionCustomizeDiff),
 		Description:   "Represent FaaS function",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Duration(faaSFunctionCreateTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(faaSFunctionCreateTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(faaSFunctionDeleteTimeout) * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, nsName, fName, err := ImportStringParserExtended(d.Id())
@@ -45,40 +60,30 @@ func resourceFaaSFunction() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -108,8 +113,28 @@ func resourceFaaSFunction() *schema.Resource {
 				ForceNew: true,
 			},
 			"code_text": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"code_text", "code_file", "code_archive"},
+				Description:  "Code of the function",
+			},
+			"code_file": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"code_text", "code_file", "code_archive"},
+				Description:  "Path to a local file with the code of the function",
+			},
+			"code_archive": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"code_text", "code_file", "code_archive"},
+				Description:  "Path to a local directory or zip archive with the code of the function and its dependencies. The API has no field for archives, the zip is sent base64 encoded in code_text and the runtime of the function must accept code in that form",
+			},
+			"source_code_hash": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Base64 encoded SHA256 hash of the code sent to the API, a change redeploys the function. It's computed from the code when not set",
 			},
 			"timeout": &schema.Schema{
 				Type:     schema.TypeInt,
@@ -169,9 +194,8 @@ func resourceFaaSFunctionCreate(ctx context.Context, d *schema.ResourceData, m i
 	log.Println("[DEBUG] Start FaaS function creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, faasPoint, versionPointV1)
+	client, err := CreateClient(config, d, faasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -188,10 +212,14 @@ func resourceFaaSFunctionCreate(ctx context.Context, d *schema.ResourceData, m i
 			MinInstances: d.Get("min_instances").(int),
 			MaxInstances: d.Get("max_instances").(int),
 		},
-		CodeText:   d.Get("code_text").(string),
 		MainMethod: d.Get("main_method").(string),
 		Envs:       map[string]string{},
 	}
+	code, err := faaSFunctionCode(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	opts.CodeText = code
 	envsRaw := d.Get("envs").(map[string]interface{})
 	if len(envsRaw) > 0 {
 		envs := make(map[string]string, len(envsRaw))
@@ -207,7 +235,7 @@ func resourceFaaSFunctionCreate(ctx context.Context, d *schema.ResourceData, m i
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, faaSFunctionCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		_, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -220,6 +248,11 @@ func resourceFaaSFunctionCreate(ctx context.Context, d *schema.ResourceData, m i
 
 	d.SetId(funcID(fName, nsName))
 
+	if err := waitFaaSFunctionBuild(ctx, client, nsName, fName, d.Timeout(schema.TimeoutCreate)); err != nil {
+		resourceFaaSFunctionRead(ctx, d, m)
+		return diag.FromErr(err)
+	}
+
 	resourceFaaSFunctionRead(ctx, d, m)
 
 	log.Printf("[DEBUG] Finish FaaS function creating (%s)", fName)
@@ -230,12 +263,11 @@ func resourceFaaSFunctionRead(ctx context.Context, d *schema.ResourceData, m int
 	log.Println("[DEBUG] Start FaaS function reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	fName := d.Get("name").(string)
 	nsName := d.Get("namespace").(string)
 	log.Printf("[DEBUG] function = %s in %s", fName, nsName)
 
-	client, err := CreateClient(provider, d, faasPoint, versionPointV1)
+	client, err := CreateClient(config, d, faasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -255,9 +287,8 @@ func resourceFaaSFunctionRead(ctx context.Context, d *schema.ResourceData, m int
 func resourceFaaSFunctionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start FaaS function updating")
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, faasPoint, versionPointV1)
+	client, err := CreateClient(config, d, faasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -283,9 +314,14 @@ func resourceFaaSFunctionUpdate(ctx context.Context, d *schema.ResourceData, m i
 		needUpdate = true
 	}
 
-	if d.HasChange("code_text") {
-		opts.CodeText = d.Get("code_text").(string)
-		needUpdate = true
+	var redeploy bool
+	if d.HasChanges("code_text", "code_file", "code_archive", "source_code_hash") {
+		code, err := faaSFunctionCode(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		opts.CodeText = code
+		needUpdate, redeploy = true, true
 	}
 
 	if d.HasChange("timeout") {
@@ -318,7 +354,7 @@ func resourceFaaSFunctionUpdate(ctx context.Context, d *schema.ResourceData, m i
 		}
 
 		taskID := results.Tasks[0]
-		_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, faaSNamespaceCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+		_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
 			_, err := tasks.Get(client, string(task)).Extract()
 			if err != nil {
 				return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -330,6 +366,15 @@ func resourceFaaSFunctionUpdate(ctx context.Context, d *schema.ResourceData, m i
 		}
 	}
 
+	if redeploy {
+		if err := waitFaaSFunctionBuild(ctx, client, nsName, fName, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			// Keep the old hash so the next apply deploys the code again.
+			oldHash, _ := d.GetChange("source_code_hash")
+			d.Set("source_code_hash", oldHash)
+			return diag.FromErr(err)
+		}
+	}
+
 	log.Println("[DEBUG] Finish FaaS function updating")
 	return resourceFaaSFunctionRead(ctx, d, m)
 }
@@ -338,14 +383,13 @@ func resourceFaaSFunctionDelete(ctx context.Context, d *schema.ResourceData, m i
 	log.Println("[DEBUG] Start FaaS function deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
 	fName := d.Get("name").(string)
 	nsName := d.Get("namespace").(string)
 
 	log.Printf("[DEBUG] function = %s", fName)
 
-	client, err := CreateClient(provider, d, faasPoint, versionPointV1)
+	client, err := CreateClient(config, d, faasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -356,7 +400,7 @@ func resourceFaaSFunctionDelete(ctx context.Context, d *schema.ResourceData, m i
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, faaSFunctionDeleteTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := faas.GetFunction(client, nsName, fName).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete function: %s", fName)
@@ -385,7 +429,10 @@ func faaSSetState(d *schema.ResourceData, function *faas.Function) error {
 	d.Set("name", function.Name)
 	d.Set("description", function.Description)
 	d.Set("runtime", function.Runtime)
-	d.Set("code_text", function.CodeText)
+	// The code of a file or an archive isn't kept in the state.
+	if d.Get("code_file").(string) == "" && d.Get("code_archive").(string) == "" {
+		d.Set("code_text", function.CodeText)
+	}
 	d.Set("timeout", function.Timeout)
 	d.Set("max_instances", function.Autoscaling.MaxInstances)
 	d.Set("min_instances", function.Autoscaling.MinInstances)
@@ -410,3 +457,146 @@ func faaSSetState(d *schema.ResourceData, function *faas.Function) error {
 
 	return nil
 }
+
+// faaSFunctionCode returns the code_text sent to the API: the text of
+// code_text or code_file, or the base64 encoded zip archive of code_archive.
+// code_text is the only field of the API for the code, so an archive relies
+// on the runtime decoding it, nothing on the API side tells the two apart.
+func faaSFunctionCode(d interface{ Get(string) interface{} }) (string, error) {
+	if path := d.Get("code_file").(string); path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("cannot read code_file: %w", err)
+		}
+		return string(data), nil
+	}
+	if path := d.Get("code_archive").(string); path != "" {
+		data, err := faaSCodeArchive(path)
+		if err != nil {
+			return "", fmt.Errorf("cannot package code_archive: %w", err)
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	}
+	return d.Get("code_text").(string), nil
+}
+
+// faaSCodeArchive returns the content of a zip file, or zips a directory.
+// Files are added in lexical order with a fixed modification time, so the
+// archive and its hash only change with the content.
+func faaSCodeArchive(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return ioutil.ReadFile(path)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(path, file)
+		if err != nil {
+			return err
+		}
+		header := &zip.FileHeader{
+			Name:     filepath.ToSlash(rel),
+			Method:   zip.Deflate,
+			Modified: time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+		header.SetMode(info.Mode())
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func faaSSourceCodeHash(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// resourceFaaSFunctionCustomizeDiff plans source_code_hash from the local
+// code when the config doesn't set it, so a change of a file redeploys the
+// function.
+func resourceFaaSFunctionCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if raw := d.GetRawConfig(); raw.IsKnown() && !raw.IsNull() && !raw.GetAttr("source_code_hash").IsNull() {
+		return nil
+	}
+	for _, field := range []string{"code_text", "code_file", "code_archive"} {
+		if !d.NewValueKnown(field) {
+			return d.SetNewComputed("source_code_hash")
+		}
+	}
+	code, err := faaSFunctionCode(d)
+	if err != nil {
+		return err
+	}
+	if hash := faaSSourceCodeHash(code); hash != d.Get("source_code_hash").(string) {
+		return d.SetNew("source_code_hash", hash)
+	}
+	return nil
+}
+
+// faaSBuildStatuses are the build_status values the wait knows, mapped to
+// whether they report a failed build. Neither the SDK nor the API reference
+// lists them, so any other value fails the wait with the raw status instead
+// of being waited on or taken for a finished build.
+var faaSBuildStatuses = map[string]bool{
+	"":        false,
+	"success": false,
+	"failed":  true,
+	"error":   true,
+}
+
+// waitFaaSFunctionBuild waits until all the instances of the function are
+// deployed, i.e. ready equals a non zero total, and its build didn't fail.
+// A failed build is reported with its build_message.
+func waitFaaSFunctionBuild(ctx context.Context, client *gcorecloud.ServiceClient, nsName, fName string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(taskPollInterval)
+	defer ticker.Stop()
+	for {
+		function, err := faas.GetFunction(client, nsName, fName).Extract()
+		if err != nil {
+			return err
+		}
+
+		failed, known := faaSBuildStatuses[strings.ToLower(function.BuildStatus)]
+		switch {
+		case !known:
+			return fmt.Errorf("unknown build status %q of function %s: %s", function.BuildStatus, fName, function.BuildMessage)
+		case failed:
+			return fmt.Errorf("build of function %s failed: %s", fName, function.BuildMessage)
+		case function.DeployStatus.Total > 0 && function.DeployStatus.Ready == function.DeployStatus.Total:
+			return nil
+		}
+		log.Printf("[DEBUG] Function %s build status is %q, %d of %d instances are ready", fName, function.BuildStatus, function.DeployStatus.Ready, function.DeployStatus.Total)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timeout while waiting for function %s to be deployed after %s, build status is %q: %s, %d of %d instances are ready",
+				fName, timeout, function.BuildStatus, function.BuildMessage, function.DeployStatus.Ready, function.DeployStatus.Total)
+		case <-ticker.C:
+		}
+	}
+}
@@ -4,10 +4,22 @@
 package gcore
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	gc "github.com/G-Core/gcorelabscloud-go/gcore"
 	"github.com/G-Core/gcorelabscloud-go/gcore/faas/v1/faas"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -148,3 +160,226 @@ func CheckDestroyFaaSFunction(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitFaaSFunctionBuildWait(t *testing.T) {
+	api := newMockAPI(t)
+	api.cloud.Seed("faas/namespaces", map[string]interface{}{"name": "ns"})
+	client, err := gc.ClientServiceFromProvider(unitProviderConfig(t, api, nil).Provider, gcorecloud.EndpointOpts{
+		Name:    faasPoint,
+		Region:  mockRegionID,
+		Project: mockProjectID,
+		Version: versionPointV1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := func(buildStatus string, total, ready int) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		api.cloud.objects["faas_functions"][fmt.Sprintf("%d/%d/ns/function", mockProjectID, mockRegionID)] = map[string]interface{}{
+			"name":          "function",
+			"build_status":  buildStatus,
+			"build_message": "message",
+			"deploy_status": map[string]interface{}{"total": total, "ready": ready},
+			"created_at":    mockCloudTimeZZ,
+		}
+	}
+
+	for _, status := range []string{"success", ""} {
+		seed(status, 1, 1)
+		if err := waitFaaSFunctionBuild(context.Background(), client, "ns", "function", time.Minute); err != nil {
+			t.Fatalf("build status %q: %v", status, err)
+		}
+	}
+
+	seed("failed", 1, 0)
+	if err := waitFaaSFunctionBuild(context.Background(), client, "ns", "function", time.Minute); err == nil || !strings.Contains(err.Error(), "failed: message") {
+		t.Fatalf("expected a build failure, got %v", err)
+	}
+
+	// An unknown status fails at once, even with the instances ready.
+	seed("unknown", 1, 1)
+	if err := waitFaaSFunctionBuild(context.Background(), client, "ns", "function", time.Minute); err == nil || !strings.Contains(err.Error(), `unknown build status "unknown"`) {
+		t.Fatalf("expected an unknown status error, got %v", err)
+	}
+
+	// Nothing deployed yet isn't a finished deploy.
+	for _, ds := range [][2]int{{0, 0}, {2, 1}} {
+		seed("success", ds[0], ds[1])
+		if err := waitFaaSFunctionBuild(context.Background(), client, "ns", "function", 10*time.Millisecond); err == nil || !strings.Contains(err.Error(), "timeout") {
+			t.Fatalf("deploy status %v: expected a timeout, got %v", ds, err)
+		}
+	}
+}
+
+func TestUnitFaaSFunctionCode(t *testing.T) {
+	api := newMockAPI(t)
+	api.cloud.Seed("faas/namespaces", map[string]interface{}{"name": "ns"})
+	fullName := "gcore_faas_function.test"
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("main.py", "from lib import util\n\ndef main(event):\n    return util.hello()\n")
+	writeFile("lib/util.py", "def hello():\n    return 'hello'\n")
+
+	// checkCode checks the archive the API got and the hash of it.
+	checkCode := func(main string) func(*terraform.InstanceState) error {
+		return func(s *terraform.InstanceState) error {
+			code := mockString(api.cloud.FaaSFunction("ns", "fn")["code_text"])
+			if got := s.Attributes["source_code_hash"]; got != faaSSourceCodeHash(code) {
+				return fmt.Errorf("expected source_code_hash of the deployed code, got %s", got)
+			}
+			data, err := base64.StdEncoding.DecodeString(code)
+			if err != nil {
+				return err
+			}
+			zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				return err
+			}
+			var names []string
+			for _, f := range zr.File {
+				names = append(names, f.Name)
+				if f.Name != "main.py" {
+					continue
+				}
+				rc, err := f.Open()
+				if err != nil {
+					return err
+				}
+				content, err := ioutil.ReadAll(rc)
+				rc.Close()
+				if err != nil {
+					return err
+				}
+				if string(content) != main {
+					return fmt.Errorf("expected main.py %q, got %q", main, content)
+				}
+			}
+			if got := strings.Join(names, ","); got != "lib/util.py,main.py" {
+				return fmt.Errorf("expected archive with lib/util.py,main.py, got %s", got)
+			}
+			return nil
+		}
+	}
+
+	config := api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_faas_function" "test" {
+  %s
+  name          = "fn"
+  namespace     = "ns"
+  runtime       = "python3.7"
+  code_archive  = "%s"
+  main_method   = "main"
+  flavor        = "80mCPU-128MB"
+  timeout       = 5
+  min_instances = 2
+  max_instances = 3
+}
+`, mockCloudScope, dir)
+	updated := "def main(event):\n    return 'updated'\n"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			if api.cloud.FaaSFunction("ns", "fn") != nil {
+				return fmt.Errorf("function fn still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "build_status", "success"),
+					resource.TestCheckResourceAttr(fullName, "deploy_status.total", "2"),
+					resource.TestCheckResourceAttr(fullName, "deploy_status.ready", "2"),
+					unitCheckState(fullName, checkCode("from lib import util\n\ndef main(event):\n    return util.hello()\n")),
+				),
+			},
+			{
+				// A changed file redeploys the function.
+				PreConfig: func() {
+					api.ResetRequests()
+					writeFile("main.py", updated)
+				},
+				Config: config,
+				Check: unitCheckState(fullName, func(s *terraform.InstanceState) error {
+					if got := api.Requests("PATCH /cloud/v1/faas/"); len(got) != 1 {
+						return fmt.Errorf("expected the function redeployed once, got %v", got)
+					}
+					return checkCode(updated)(s)
+				}),
+			},
+			{
+				PreConfig: func() {
+					writeFile("main.py", "def main(event) BUILD_FAILURE\n")
+				},
+				Config:      config,
+				ExpectError: regexp.MustCompile("build of function fn failed: SyntaxError"),
+			},
+			{
+				PreConfig: func() {
+					writeFile("main.py", updated)
+				},
+				Config: config,
+			},
+			{
+				ResourceName: fullName,
+				ImportState:  true,
+				ImportStateIdFunc: unitImportID(fullName, func(s *terraform.InstanceState) string {
+					return fmt.Sprintf("%d:%d:ns:fn", mockProjectID, mockRegionID)
+				}),
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"project_name", "region_name", "code_text", "code_archive", "source_code_hash",
+					"build_status", "build_message", "status", "deploy_status",
+				},
+			},
+		},
+	})
+}
+
+func TestUnitFaaSCodeArchive(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.py"), []byte("pass\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	first, err := faaSCodeArchive(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Touching a file keeps the archive, so the hash only follows the content.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "main.py"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	second, err := faaSCodeArchive(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatalf("expected the same archive after a touch")
+	}
+
+	// A zip file is sent as is.
+	path := filepath.Join(t.TempDir(), "code.zip")
+	if err := ioutil.WriteFile(path, first, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	third, err := faaSCodeArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, third) {
+		t.Fatalf("expected the zip file content")
+	}
+}
@@ -26,7 +26,13 @@ func resourceFaaSNamespace() *schema.Resource {
 		ReadContext:   resourceFaaSNamespaceRead,
 		UpdateContext: resourceFaaSNamespaceUpdate,
 		DeleteContext: resourceFaaSNamespaceDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent FaaS namespace",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Duration(faaSNamespaceCreateTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(faaSNamespaceCreateTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(faaSNamespaceDeleteTimeout) * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, nsName, err := ImportStringParser(d.Id())
@@ -44,40 +50,30 @@ func resourceFaaSNamespace() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -111,9 +107,8 @@ func resourceFaaSNamespaceCreate(ctx context.Context, d *schema.ResourceData, m
 	log.Println("[DEBUG] Start FaaS namespace creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, faasPoint, versionPointV1)
+	client, err := CreateClient(config, d, faasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -139,7 +134,7 @@ func resourceFaaSNamespaceCreate(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, faaSNamespaceCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		_, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -161,11 +156,10 @@ func resourceFaaSNamespaceRead(ctx context.Context, d *schema.ResourceData, m in
 	log.Println("[DEBUG] Start FaaS namespace reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	nsName := d.Id()
 	log.Printf("[DEBUG] namespace = %s", nsName)
 
-	client, err := CreateClient(provider, d, faasPoint, versionPointV1)
+	client, err := CreateClient(config, d, faasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -198,9 +192,8 @@ func resourceFaaSNamespaceRead(ctx context.Context, d *schema.ResourceData, m in
 func resourceFaaSNamespaceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start FaaS namespace updating")
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, faasPoint, versionPointV1)
+	client, err := CreateClient(config, d, faasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -231,7 +224,7 @@ func resourceFaaSNamespaceUpdate(ctx context.Context, d *schema.ResourceData, m
 		}
 
 		taskID := results.Tasks[0]
-		_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, faaSNamespaceCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+		_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
 			_, err := tasks.Get(client, string(task)).Extract()
 			if err != nil {
 				return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -251,11 +244,10 @@ func resourceFaaSNamespaceDelete(ctx context.Context, d *schema.ResourceData, m
 	log.Println("[DEBUG] Start FaaS namespace deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	nsName := d.Id()
 	log.Printf("[DEBUG] Namespace = %s", nsName)
 
-	client, err := CreateClient(provider, d, faasPoint, versionPointV1)
+	client, err := CreateClient(config, d, faasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -266,9 +258,15 @@ func resourceFaaSNamespaceDelete(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, faaSNamespaceDeleteTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		// workaround because of cloud-api
-		time.Sleep(time.Second * 5)
+		timer := time.NewTimer(time.Second * 5)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for namespace %s deletion: %w", nsName, ctx.Err())
+		case <-timer.C:
+		}
 		_, err := faas.GetNamespace(client, nsName).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete namespace: %s", nsName)
@@ -87,3 +87,58 @@ func CheckDestroyFaaSNamespace(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitFaaSNamespace(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_faas_namespace.test"
+
+	template := func(description, env string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_faas_namespace" "test" {
+  %s
+  name        = "ns"
+  description = "%s"
+  envs = {
+    ENV = "%s"
+  }
+}
+`, mockCloudScope, description, env)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_faas_namespace", "faas/namespaces"),
+		Steps: []resource.TestStep{
+			{
+				Config: template("functions", "test"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "ns"),
+					resource.TestCheckResourceAttr(fullName, "description", "functions"),
+					resource.TestCheckResourceAttr(fullName, "envs.ENV", "test"),
+					resource.TestCheckResourceAttr(fullName, "status", "active"),
+					resource.TestCheckResourceAttrSet(fullName, "created_at"),
+				),
+			},
+			{
+				Config: template("functions of prod", "prod"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "description", "functions of prod"),
+					resource.TestCheckResourceAttr(fullName, "envs.ENV", "prod"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if envs := mockMap(api.cloud.Get("faas/namespaces", s.ID)["envs"]); envs["ENV"] != "prod" {
+							return fmt.Errorf("envs weren't updated: %v", envs)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"project_name", "region_name"},
+			},
+		},
+	})
+}
@@ -29,7 +29,13 @@ func resourceFloatingIP() *schema.Resource {
 		ReadContext:   resourceFloatingIPRead,
 		UpdateContext: resourceFloatingIPUpdate,
 		DeleteContext: resourceFloatingIPDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "A floating IP is a static IP address that points to one of your Instances. It allows you to redirect network traffic to any of your Instances in the same datacenter.",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Duration(FloatingIPCreateTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(FloatingIPCreateTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(FloatingIPCreateTimeout) * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, fipID, err := ImportStringParser(d.Id())
@@ -47,36 +53,26 @@ func resourceFloatingIP() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"status": &schema.Schema{
 				Type:     schema.TypeString,
@@ -157,9 +153,8 @@ func resourceFloatingIPCreate(ctx context.Context, d *schema.ResourceData, m int
 	log.Println("[DEBUG] Start FloatingIP creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, floatingIPsPoint, versionPointV1)
+	client, err := CreateClient(config, d, floatingIPsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -183,7 +178,7 @@ func resourceFloatingIPCreate(ctx context.Context, d *schema.ResourceData, m int
 	}
 
 	taskID := results.Tasks[0]
-	floatingIPID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, FloatingIPCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	floatingIPID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -211,9 +206,8 @@ func resourceFloatingIPRead(ctx context.Context, d *schema.ResourceData, m inter
 	log.Println("[DEBUG] Start FloatingIP reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, floatingIPsPoint, versionPointV1)
+	client, err := CreateClient(config, d, floatingIPsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -273,9 +267,8 @@ func resourceFloatingIPRead(ctx context.Context, d *schema.ResourceData, m inter
 func resourceFloatingIPUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start FloatingIP updating")
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, floatingIPsPoint, versionPointV1)
+	client, err := CreateClient(config, d, floatingIPsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -325,9 +318,8 @@ func resourceFloatingIPDelete(ctx context.Context, d *schema.ResourceData, m int
 	log.Println("[DEBUG] Start FloatingIP deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, floatingIPsPoint, versionPointV1)
+	client, err := CreateClient(config, d, floatingIPsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -339,7 +331,7 @@ func resourceFloatingIPDelete(ctx context.Context, d *schema.ResourceData, m int
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, FloatingIPCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := floatingips.Get(client, id).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete floating ip with ID: %s", id)
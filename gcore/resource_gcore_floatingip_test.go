@@ -89,3 +89,74 @@ func testAccFloatingIPDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitFloatingIP(t *testing.T) {
+	api := newMockAPI(t)
+	networkID := api.cloud.Seed("networks", map[string]interface{}{"name": "network"})
+	subnetID := api.cloud.Seed("subnets", map[string]interface{}{
+		"name":       "subnet",
+		"cidr":       "192.168.10.0/24",
+		"network_id": networkID,
+	})
+	portID := api.cloud.Seed("reserved_fixed_ips", map[string]interface{}{
+		"type": "subnet", "subnet_id": subnetID,
+	})
+	fixedIP := mockString(api.cloud.Get("reserved_fixed_ips", portID)["fixed_ip_address"])
+	fullName := "gcore_floatingip.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_floatingip", "floatingips"),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_floatingip" "test" {
+  %s
+  metadata_map = {
+    env = "test"
+  }
+}
+`, mockCloudScope),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(fullName, "floating_ip_address"),
+					resource.TestCheckResourceAttr(fullName, "status", "DOWN"),
+					resource.TestCheckResourceAttr(fullName, "port_id", ""),
+					resource.TestCheckResourceAttr(fullName, "fixed_ip_address", ""),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.env", "test"),
+					resource.TestCheckResourceAttr(fullName, "metadata_read_only.#", "1"),
+				),
+			},
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_floatingip" "test" {
+  %s
+  port_id          = "%s"
+  fixed_ip_address = "%s"
+  metadata_map = {
+    env = "prod"
+  }
+}
+`, mockCloudScope, portID, fixedIP),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "status", "ACTIVE"),
+					resource.TestCheckResourceAttr(fullName, "port_id", portID),
+					resource.TestCheckResourceAttr(fullName, "fixed_ip_address", fixedIP),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.env", "prod"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if fip := api.cloud.Get("floatingips", s.ID); fip["port_id"] != portID {
+							return fmt.Errorf("floating ip wasn't assigned to %s: %v", portID, fip["port_id"])
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "project_name", "region_name"},
+			},
+		},
+	})
+}
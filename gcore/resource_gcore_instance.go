@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"sort"
 	"strconv"
 	"time"
 
 	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/floatingip/v1/floatingips"
 	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
 	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/types"
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/securitygroups"
 	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
 	"github.com/G-Core/gcorelabscloud-go/gcore/volume/v1/volumes"
 	"github.com/hashicorp/go-cty/cty"
@@ -35,7 +38,13 @@ func resourceInstance() *schema.Resource {
 		ReadContext:   resourceInstanceRead,
 		UpdateContext: resourceInstanceUpdate,
 		DeleteContext: resourceInstanceDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent instance",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Duration(InstanceCreatingTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(InstanceCreatingTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(InstanceDeleting) * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, InstanceID, err := ImportStringParser(d.Id())
@@ -53,36 +62,26 @@ func resourceInstance() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"flavor_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -249,8 +248,11 @@ func resourceInstance() *schema.Resource {
 				},
 			},
 			"password": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressSensitiveHashDiff,
+				Description:      "Only its hash is kept in the state when the provider sets hash_sensitive_values",
 			},
 			"username": &schema.Schema{
 				Type:     schema.TypeString,
@@ -371,13 +373,12 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 	log.Println("[DEBUG] Start Instance creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	clientv1, err := CreateClient(provider, d, InstancePoint, versionPointV1)
+	clientv1, err := CreateClient(config, d, InstancePoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	clientv2, err := CreateClient(provider, d, InstancePoint, versionPointV2)
+	clientv2, err := CreateClient(config, d, InstancePoint, versionPointV2)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -466,7 +467,7 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	InstanceID, err := tasks.WaitTaskAndReturnResult(clientv1, taskID, true, InstanceCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
+	InstanceID, err := waitTask(ctx, clientv1, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(clientv1, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -494,11 +495,10 @@ func resourceInstanceRead(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Println("[DEBUG] Start Instance reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	instanceID := d.Id()
 	log.Printf("[DEBUG] Instance id = %s", instanceID)
 
-	client, err := CreateClient(provider, d, InstancePoint, versionPointV1)
+	client, err := CreateClient(config, d, InstancePoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -602,8 +602,13 @@ func resourceInstanceRead(ctx context.Context, d *schema.ResourceData, m interfa
 			i["port_id"] = iface.PortID
 			i["order"] = orderedIOpts.Order
 			if len(iface.FloatingIPDetails) > 0 {
-				i["fip_source"] = types.ExistingFloatingIP
-				i["existing_fip_id"] = iface.FloatingIPDetails[0].ID
+				// a floating ip created with the interface stays a new one
+				if iOpts.FloatingIP != nil && iOpts.FloatingIP.Source == types.NewFloatingIP {
+					i["fip_source"] = types.NewFloatingIP
+				} else {
+					i["fip_source"] = types.ExistingFloatingIP
+					i["existing_fip_id"] = iface.FloatingIPDetails[0].ID
+				}
 			}
 			i["ip_address"] = assignment.IPAddress.String()
 
@@ -668,6 +673,7 @@ func resourceInstanceRead(ctx context.Context, d *schema.ResourceData, m interfa
 	if err := d.Set("addresses", addresses); err != nil {
 		return diag.FromErr(err)
 	}
+	hashSensitiveFields(d, config, "password")
 
 	log.Println("[DEBUG] Finish Instance reading")
 	return diags
@@ -678,8 +684,7 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m inter
 	instanceID := d.Id()
 	log.Printf("[DEBUG] Instance id = %s", instanceID)
 	config := m.(*Config)
-	provider := config.Provider
-	client, err := CreateClient(provider, d, InstancePoint, versionPointV1)
+	client, err := CreateClient(config, d, InstancePoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -705,7 +710,7 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m inter
 		}
 		taskID := results.Tasks[0]
 		log.Printf("[DEBUG] Task id (%s)", taskID)
-		taskState, err := tasks.WaitTaskAndReturnResult(client, taskID, true, InstanceCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
+		taskState, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
 			taskInfo, err := tasks.Get(client, string(task)).Extract()
 			if err != nil {
 				return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -778,77 +783,13 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m inter
 	}
 
 	if d.HasChange("interface") {
-		ifsOldRaw, ifsNewRaw := d.GetChange("interface")
-
-		ifsOld := ifsOldRaw.([]interface{})
-		ifsNew := ifsNewRaw.([]interface{})
-
-		for _, i := range ifsOld {
-			iface := i.(map[string]interface{})
-			var opts instances.InterfaceOpts
-			opts.PortID = iface["port_id"].(string)
-			opts.IpAddress = iface["ip_address"].(string)
-
-			log.Printf("[DEBUG] detach interface: %+v", opts)
-			results, err := instances.DetachInterface(client, instanceID, opts).Extract()
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			taskID := results.Tasks[0]
-			_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, InstanceCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
-				taskInfo, err := tasks.Get(client, string(task)).Extract()
-				if err != nil {
-					return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w, task: %+v", task, err, taskInfo)
-				}
-				return nil, nil
-			},
-			)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-		}
-
-		sort.Sort(instanceInterfaces(ifsNew))
-		for _, i := range ifsNew {
-			iface := i.(map[string]interface{})
-
-			iType := types.InterfaceType(iface["type"].(string))
-			opts := instances.InterfaceInstanceCreateOpts{
-				InterfaceOpts: instances.InterfaceOpts{Type: iType},
-			}
-
-			switch iType {
-			case types.SubnetInterfaceType:
-				opts.SubnetID = iface["subnet_id"].(string)
-			case types.AnySubnetInterfaceType:
-				opts.NetworkID = iface["network_id"].(string)
-			case types.ReservedFixedIpType:
-				opts.PortID = iface["port_id"].(string)
-			}
-
-			rawSgsID := iface["security_groups"].([]interface{})
-			sgs := make([]gcorecloud.ItemID, len(rawSgsID))
-			for i, sgID := range rawSgsID {
-				sgs[i] = gcorecloud.ItemID{ID: sgID.(string)}
-			}
-			opts.SecurityGroups = sgs
-
-			log.Printf("[DEBUG] attach interface: %+v", opts)
-			results, err := instances.AttachInterface(client, instanceID, opts).Extract()
-			if err != nil {
-				return diag.Errorf("cannot attach interface: %s. Error: %s", iType, err)
-			}
-
-			taskID := results.Tasks[0]
-			log.Printf("[DEBUG] attach interface taskID: %s", taskID)
-			if err = tasks.WaitForStatus(client, string(taskID), tasks.TaskStateFinished, InstanceCreatingTimeout, true); err != nil {
-				return diag.FromErr(err)
-			}
+		if err := updateInstanceInterfaces(ctx, config, client, d); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("volume") {
-		vClient, err := CreateClient(provider, d, volumesPoint, versionPointV1)
+		vClient, err := CreateClient(config, d, volumesPoint, versionPointV1)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -889,7 +830,7 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m inter
 			startStateConf := &resource.StateChangeConf{
 				Target:     []string{InstanceVMStateActive},
 				Refresh:    ServerV2StateRefreshFunc(client, instanceID),
-				Timeout:    d.Timeout(schema.TimeoutCreate),
+				Timeout:    d.Timeout(schema.TimeoutUpdate),
 				Delay:      10 * time.Second,
 				MinTimeout: 3 * time.Second,
 			}
@@ -904,7 +845,7 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m inter
 			stopStateConf := &resource.StateChangeConf{
 				Target:     []string{InstanceVMStateStopped},
 				Refresh:    ServerV2StateRefreshFunc(client, instanceID),
-				Timeout:    d.Timeout(schema.TimeoutCreate),
+				Timeout:    d.Timeout(schema.TimeoutUpdate),
 				Delay:      10 * time.Second,
 				MinTimeout: 3 * time.Second,
 			}
@@ -920,15 +861,248 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m inter
 	return resourceInstanceRead(ctx, d, m)
 }
 
+// updateInstanceInterfaces applies the change of the interface list of an
+// instance or a bare metal server. Only removed interfaces are detached and
+// only added ones are attached, security groups and floating IPs of the kept
+// ports are updated in place.
+func updateInstanceInterfaces(ctx context.Context, config *Config, client *gcorecloud.ServiceClient, d *schema.ResourceData) error {
+	instanceID := d.Id()
+	ifsOldRaw, ifsNewRaw := d.GetChange("interface")
+	detach, attach, kept := diffInstanceInterfaces(ifsOldRaw.([]interface{}), ifsNewRaw.([]interface{}))
+
+	// the API assigns the address of an attached interface, it can't be changed
+	for _, pair := range kept {
+		if ip, _ := pair.New["ip_address"].(string); ip != "" && ip != pair.Old["ip_address"] {
+			return fmt.Errorf("cannot change ip_address of the interface with port %s from %s to %s", pair.Old["port_id"], pair.Old["ip_address"], ip)
+		}
+	}
+
+	for _, i := range detach {
+		iface := i.(map[string]interface{})
+		if isParent, _ := iface["is_parent"].(bool); isParent {
+			return fmt.Errorf("could not detach trunk interface")
+		}
+
+		var opts instances.InterfaceOpts
+		opts.PortID = iface["port_id"].(string)
+		opts.IpAddress = iface["ip_address"].(string)
+
+		log.Printf("[DEBUG] detach interface: %+v", opts)
+		results, err := instances.DetachInterface(client, instanceID, opts).Extract()
+		if err != nil {
+			return err
+		}
+		taskID := results.Tasks[0]
+		log.Printf("[DEBUG] detach interface taskID: %s", taskID)
+		if _, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), nil); err != nil {
+			return err
+		}
+	}
+
+	var sgClient *gcorecloud.ServiceClient
+	for _, pair := range kept {
+		removed, added := diffInterfaceSecurityGroups(pair)
+		if len(removed) == 0 && len(added) == 0 {
+			continue
+		}
+		if sgClient == nil {
+			var err error
+			if sgClient, err = CreateClient(config, d, securityGroupPoint, versionPointV1); err != nil {
+				return err
+			}
+		}
+
+		portID := pair.Old["port_id"].(string)
+		if len(removed) > 0 {
+			opts, err := portSecurityGroupOpts(sgClient, portID, removed)
+			if err != nil {
+				return err
+			}
+			log.Printf("[DEBUG] unassign security groups %v from port %s", removed, portID)
+			if err := instances.UnAssignSecurityGroup(client, instanceID, opts).ExtractErr(); err != nil {
+				return fmt.Errorf("cannot unassign security groups from port %s: %w", portID, err)
+			}
+		}
+		if len(added) > 0 {
+			opts, err := portSecurityGroupOpts(sgClient, portID, added)
+			if err != nil {
+				return err
+			}
+			log.Printf("[DEBUG] assign security groups %v to port %s", added, portID)
+			if err := instances.AssignSecurityGroup(client, instanceID, opts).ExtractErr(); err != nil {
+				return fmt.Errorf("cannot assign security groups to port %s: %w", portID, err)
+			}
+		}
+	}
+
+	for _, pair := range kept {
+		if err := updateInterfaceFloatingIP(ctx, config, client, d, pair); err != nil {
+			return err
+		}
+	}
+
+	sort.Sort(instanceInterfaces(attach))
+	for _, i := range attach {
+		iface := i.(map[string]interface{})
+
+		iType := types.InterfaceType(iface["type"].(string))
+		opts := instances.InterfaceInstanceCreateOpts{
+			InterfaceOpts: instances.InterfaceOpts{Type: iType},
+		}
+
+		switch iType {
+		case types.SubnetInterfaceType:
+			opts.SubnetID = iface["subnet_id"].(string)
+		case types.AnySubnetInterfaceType:
+			opts.NetworkID = iface["network_id"].(string)
+		case types.ReservedFixedIpType:
+			opts.PortID = iface["port_id"].(string)
+		}
+
+		if fipSource, _ := iface["fip_source"].(string); fipSource != "" {
+			var fip instances.CreateNewInterfaceFloatingIPOpts
+			if fipID, _ := iface["existing_fip_id"].(string); fipID != "" {
+				fip.Source = types.ExistingFloatingIP
+				fip.ExistingFloatingID = fipID
+			} else {
+				fip.Source = types.NewFloatingIP
+			}
+			opts.FloatingIP = &fip
+		}
+
+		rawSgsID, _ := iface["security_groups"].([]interface{})
+		sgs := make([]gcorecloud.ItemID, len(rawSgsID))
+		for i, sgID := range rawSgsID {
+			sgs[i] = gcorecloud.ItemID{ID: sgID.(string)}
+		}
+		opts.SecurityGroups = sgs
+
+		log.Printf("[DEBUG] attach interface: %+v", opts)
+		results, err := instances.AttachInterface(client, instanceID, opts).Extract()
+		if err != nil {
+			return fmt.Errorf("cannot attach interface: %s. Error: %w", iType, err)
+		}
+
+		taskID := results.Tasks[0]
+		log.Printf("[DEBUG] attach interface taskID: %s", taskID)
+		if _, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateInterfaceFloatingIP makes the floating IP of a kept interface match
+// its configuration. Removing fip_source unassigns the floating IP, an
+// interface which never had fip_source is left as is, its floating IP may be
+// managed by a gcore_floatingip resource.
+func updateInterfaceFloatingIP(ctx context.Context, config *Config, client *gcorecloud.ServiceClient, d *schema.ResourceData, pair instanceInterfacePair) error {
+	fipSource, _ := pair.New["fip_source"].(string)
+	fipID, _ := pair.New["existing_fip_id"].(string)
+	if fipSource == pair.Old["fip_source"] && fipID == pair.Old["existing_fip_id"] {
+		return nil
+	}
+
+	fipClient, err := CreateClient(config, d, floatingIPsPoint, versionPointV1)
+	if err != nil {
+		return err
+	}
+
+	// a floating ip created with the interface isn't managed by anything
+	// else, it's deleted once replaced or removed, an existing one is only
+	// unassigned
+	createdFIP := pair.Old["fip_source"] == types.NewFloatingIP.String()
+	portID := pair.Old["port_id"].(string)
+	ifs, err := instances.ListInterfacesAll(client, d.Id())
+	if err != nil {
+		return err
+	}
+	for _, iface := range ifs {
+		if iface.PortID != portID {
+			continue
+		}
+		for _, fip := range iface.FloatingIPDetails {
+			if fip.ID == fipID {
+				continue
+			}
+			log.Printf("[DEBUG] unassign floating ip %s from port %s", fip.ID, portID)
+			if _, err := floatingips.UnAssign(fipClient, fip.ID).Extract(); err != nil {
+				return fmt.Errorf("cannot unassign floating ip %s: %w", fip.ID, err)
+			}
+			if createdFIP {
+				if err := deleteInterfaceFloatingIP(ctx, fipClient, d, fip.ID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if fipSource == "" {
+		return nil
+	}
+
+	opts := floatingips.CreateOpts{
+		PortID:         portID,
+		FixedIPAddress: net.ParseIP(pair.Old["ip_address"].(string)),
+	}
+	if fipSource == types.ExistingFloatingIP.String() {
+		log.Printf("[DEBUG] assign floating ip %s to port %s", fipID, portID)
+		if _, err := floatingips.Assign(fipClient, fipID, opts).Extract(); err != nil {
+			return fmt.Errorf("cannot assign floating ip %s: %w", fipID, err)
+		}
+		return nil
+	}
+
+	log.Printf("[DEBUG] create floating ip for port %s", portID)
+	results, err := floatingips.Create(fipClient, opts).Extract()
+	if err != nil {
+		return fmt.Errorf("cannot create floating ip: %w", err)
+	}
+	taskID := results.Tasks[0]
+	_, err = waitTask(ctx, fipClient, taskID, d.Timeout(schema.TimeoutUpdate), nil)
+	return err
+}
+
+// deleteInterfaceFloatingIP deletes a floating IP which was created with an
+// instance interface and is no longer used by it.
+func deleteInterfaceFloatingIP(ctx context.Context, fipClient *gcorecloud.ServiceClient, d *schema.ResourceData, fipID string) error {
+	log.Printf("[DEBUG] delete floating ip %s", fipID)
+	results, err := floatingips.Delete(fipClient, fipID).Extract()
+	if err != nil {
+		return fmt.Errorf("cannot delete floating ip %s: %w", fipID, err)
+	}
+	taskID := results.Tasks[0]
+	_, err = waitTask(ctx, fipClient, taskID, d.Timeout(schema.TimeoutUpdate), nil)
+	return err
+}
+
+// portSecurityGroupOpts builds the request assigning security groups, given
+// by ID, to a single port. The API refers to security groups by name.
+func portSecurityGroupOpts(sgClient *gcorecloud.ServiceClient, portID string, sgIDs []string) (instances.SecurityGroupOpts, error) {
+	names := make([]string, len(sgIDs))
+	for i, sgID := range sgIDs {
+		sg, err := securitygroups.Get(sgClient, sgID).Extract()
+		if err != nil {
+			return instances.SecurityGroupOpts{}, fmt.Errorf("cannot get security group %s: %w", sgID, err)
+		}
+		names[i] = sg.Name
+	}
+	return instances.SecurityGroupOpts{
+		PortsSecurityGroupNames: []instances.PortSecurityGroupNames{
+			{PortID: &portID, SecurityGroupNames: names},
+		},
+	}, nil
+}
+
 func resourceInstanceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start Instance deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	instanceID := d.Id()
 	log.Printf("[DEBUG] Instance id = %s", instanceID)
 
-	client, err := CreateClient(provider, d, InstancePoint, versionPointV1)
+	client, err := CreateClient(config, d, InstancePoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -940,7 +1114,7 @@ func resourceInstanceDelete(ctx context.Context, d *schema.ResourceData, m inter
 	}
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, InstanceDeleting, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := instances.Get(client, instanceID).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete instance with ID: %s", instanceID)
@@ -397,3 +397,438 @@ func testAccInstanceDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitInstance(t *testing.T) {
+	api := newMockAPI(t)
+	networkID := api.cloud.Seed("networks", map[string]interface{}{"name": "network"})
+	subnetID := api.cloud.Seed("subnets", map[string]interface{}{
+		"name":                      "subnet",
+		"cidr":                      "192.168.10.0/24",
+		"network_id":                networkID,
+		"connect_to_network_router": true,
+	})
+	volumeID := api.cloud.Seed("volumes", map[string]interface{}{
+		"name":     "boot",
+		"source":   "image",
+		"image_id": "f4ce3d30-e29c-4cfd-811f-46f383b6081f",
+		"size":     5,
+	})
+	fullName := "gcore_instance.test"
+
+	template := func(name, flavor, env string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_instance" "test" {
+  %s
+  name      = "%s"
+  flavor_id = "%s"
+
+  volume {
+    source     = "existing-volume"
+    volume_id  = "%s"
+    boot_index = 0
+  }
+
+  interface {
+    type       = "subnet"
+    network_id = "%s"
+    subnet_id  = "%s"
+  }
+
+  metadata_map = {
+    env = "%s"
+  }
+}
+`, mockCloudScope, name, flavor, volumeID, networkID, subnetID, env)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			if err := unitCloudCheckDestroy(api, "gcore_instance", "instances")(s); err != nil {
+				return err
+			}
+			if v := api.cloud.Get("volumes", volumeID); mockString(v["status"]) != "available" {
+				return fmt.Errorf("volume %s wasn't released: %v", volumeID, v["status"])
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: template("instance", "g1-standard-1-2", "test"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "instance"),
+					resource.TestCheckResourceAttr(fullName, "flavor_id", "g1-standard-1-2"),
+					resource.TestCheckResourceAttr(fullName, "vm_state", "active"),
+					resource.TestCheckResourceAttr(fullName, "volume.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "interface.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "interface.0.subnet_id", subnetID),
+					resource.TestCheckResourceAttr(fullName, "interface.0.network_id", networkID),
+					resource.TestCheckResourceAttr(fullName, "interface.0.ip_address", "192.168.10.2"),
+					resource.TestCheckResourceAttrSet(fullName, "interface.0.port_id"),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.env", "test"),
+					resource.TestCheckResourceAttr(fullName, "addresses.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "addresses.0.net.0.addr", "192.168.10.2"),
+					resource.TestCheckResourceAttr(fullName, "addresses.0.net.0.type", "fixed"),
+					resource.TestCheckResourceAttr(fullName, "flavor.flavor_id", "g1-standard-1-2"),
+				),
+			},
+			{
+				Config: template("instance-renamed", "g1-standard-2-4", "prod"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "instance-renamed"),
+					resource.TestCheckResourceAttr(fullName, "flavor_id", "g1-standard-2-4"),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.env", "prod"),
+				),
+			},
+			{
+				ResourceName:      fullName,
+				ImportState:       true,
+				ImportStateIdFunc: unitCloudImportID(fullName),
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"last_updated", "project_name", "region_name",
+					"interface", "metadata_map", "volume",
+				},
+			},
+		},
+	})
+}
+
+func TestUnitInstanceInterfaces(t *testing.T) {
+	api := newMockAPI(t)
+	networkID := api.cloud.Seed("networks", map[string]interface{}{"name": "network"})
+	subnetID := api.cloud.Seed("subnets", map[string]interface{}{
+		"name":       "subnet",
+		"cidr":       "192.168.10.0/24",
+		"network_id": networkID,
+	})
+	privateID := api.cloud.Seed("networks", map[string]interface{}{"name": "private"})
+	api.cloud.Seed("subnets", map[string]interface{}{
+		"name":       "private",
+		"cidr":       "10.0.0.0/24",
+		"network_id": privateID,
+	})
+	sgID := api.cloud.Seed("securitygroups", map[string]interface{}{
+		"security_group": map[string]interface{}{"name": "web"},
+	})
+	volumeID := api.cloud.Seed("volumes", map[string]interface{}{
+		"name":     "boot",
+		"source":   "image",
+		"image_id": "f4ce3d30-e29c-4cfd-811f-46f383b6081f",
+		"size":     5,
+	})
+	fullName := "gcore_instance.test"
+
+	primary := func(sgs string) string {
+		return fmt.Sprintf(`
+  interface {
+    type            = "subnet"
+    network_id      = "%s"
+    subnet_id       = "%s"
+    security_groups = [%s]
+  }
+`, networkID, subnetID, sgs)
+	}
+	secondary := fmt.Sprintf(`
+  interface {
+    type       = "any_subnet"
+    network_id = "%s"
+    order      = 1
+  }
+`, privateID)
+	template := func(interfaces string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_instance" "test" {
+  %s
+  name      = "instance"
+  flavor_id = "g1-standard-1-2"
+
+  volume {
+    source     = "existing-volume"
+    volume_id  = "%s"
+    boot_index = 0
+  }
+%s
+}
+`, mockCloudScope, volumeID, interfaces)
+	}
+
+	var primaryPort string
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_instance", "instances"),
+		Steps: []resource.TestStep{
+			{
+				Config: template(primary("")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "interface.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "interface.0.subnet_id", subnetID),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						primaryPort = s.Attributes["interface.0.port_id"]
+						api.ResetRequests()
+						return nil
+					}),
+				),
+			},
+			{
+				Config: template(primary(`"`+sgID+`"`) + secondary),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "interface.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "interface.0.security_groups.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "interface.0.security_groups.0", sgID),
+					resource.TestCheckResourceAttr(fullName, "interface.1.network_id", privateID),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if port := s.Attributes["interface.0.port_id"]; port != primaryPort {
+							return fmt.Errorf("primary interface was re-attached: port %s, was %s", port, primaryPort)
+						}
+						if reqs := api.Requests("POST /cloud/v1/instances/1/1/" + s.ID + "/detach_interface"); len(reqs) != 0 {
+							return fmt.Errorf("unexpected detach: %v", reqs)
+						}
+						if reqs := api.Requests("POST /cloud/v1/instances/1/1/" + s.ID + "/attach_interface"); len(reqs) != 1 {
+							return fmt.Errorf("expected a single attach, got %v", reqs)
+						}
+						if reqs := api.Requests("POST /cloud/v1/instances/1/1/" + s.ID + "/addsecuritygroup"); len(reqs) != 1 {
+							return fmt.Errorf("expected security group assignment, got %v", reqs)
+						}
+						api.ResetRequests()
+						return nil
+					}),
+				),
+			},
+			{
+				Config: template(primary(`"` + sgID + `"`)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "interface.#", "1"),
+					resource.TestCheckResourceAttrSet(fullName, "interface.0.port_id"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if reqs := api.Requests("POST /cloud/v1/instances/1/1/" + s.ID + "/detach_interface"); len(reqs) != 1 {
+							return fmt.Errorf("expected a single detach, got %v", reqs)
+						}
+						if reqs := api.Requests("POST /cloud/v1/instances/1/1/" + s.ID + "/attach_interface"); len(reqs) != 0 {
+							return fmt.Errorf("unexpected attach: %v", reqs)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitInstanceInterfacesFloatingIP(t *testing.T) {
+	api := newMockAPI(t)
+	networkID := api.cloud.Seed("networks", map[string]interface{}{"name": "network"})
+	subnetID := api.cloud.Seed("subnets", map[string]interface{}{
+		"name":       "subnet",
+		"cidr":       "192.168.10.0/24",
+		"network_id": networkID,
+	})
+	privateID := api.cloud.Seed("networks", map[string]interface{}{"name": "private"})
+	api.cloud.Seed("subnets", map[string]interface{}{
+		"name":       "private",
+		"cidr":       "10.0.0.0/24",
+		"network_id": privateID,
+	})
+	fipID := api.cloud.Seed("floatingips", map[string]interface{}{})
+	volumeID := api.cloud.Seed("volumes", map[string]interface{}{
+		"name":     "boot",
+		"source":   "image",
+		"image_id": "f4ce3d30-e29c-4cfd-811f-46f383b6081f",
+		"size":     5,
+	})
+	fullName := "gcore_instance.test"
+
+	// primary returns the primary interface with a new floating ip, the
+	// existing one when fip is set, or none when fip is "-".
+	primary := func(fip string) string {
+		source := `fip_source = "new"`
+		switch fip {
+		case "":
+		case "-":
+			source = ""
+		default:
+			source = fmt.Sprintf(`fip_source      = "existing"
+    existing_fip_id = "%s"`, fip)
+		}
+		return fmt.Sprintf(`
+  interface {
+    type       = "subnet"
+    network_id = "%s"
+    subnet_id  = "%s"
+    %s
+  }
+`, networkID, subnetID, source)
+	}
+	secondary := fmt.Sprintf(`
+  interface {
+    type       = "any_subnet"
+    network_id = "%s"
+    order      = 1
+  }
+`, privateID)
+	template := func(interfaces string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_instance" "test" {
+  %s
+  name      = "instance"
+  flavor_id = "g1-standard-1-2"
+
+  volume {
+    source     = "existing-volume"
+    volume_id  = "%s"
+    boot_index = 0
+  }
+%s
+}
+`, mockCloudScope, volumeID, interfaces)
+	}
+	portFIPs := func(portID string) []string {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		var ids []string
+		for _, raw := range api.cloud.portFloatingIPs(portID) {
+			ids = append(ids, mockString(mockMap(raw)["id"]))
+		}
+		return ids
+	}
+
+	var primaryPort, newFIP string
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_instance", "instances"),
+		Steps: []resource.TestStep{
+			{
+				Config: template(primary("")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "interface.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "interface.0.fip_source", "new"),
+					resource.TestCheckResourceAttr(fullName, "interface.0.existing_fip_id", ""),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						primaryPort = s.Attributes["interface.0.port_id"]
+						fips := portFIPs(primaryPort)
+						if len(fips) != 1 {
+							return fmt.Errorf("expected a floating ip on port %s, got %v", primaryPort, fips)
+						}
+						newFIP = fips[0]
+						api.ResetRequests()
+						return nil
+					}),
+				),
+			},
+			{
+				Config: template(primary("") + secondary),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "interface.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "interface.0.fip_source", "new"),
+					resource.TestCheckResourceAttr(fullName, "interface.1.network_id", privateID),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if port := s.Attributes["interface.0.port_id"]; port != primaryPort {
+							return fmt.Errorf("primary interface was re-attached: port %s, was %s", port, primaryPort)
+						}
+						if reqs := api.Requests("POST /cloud/v1/instances/1/1/" + s.ID + "/detach_interface"); len(reqs) != 0 {
+							return fmt.Errorf("unexpected detach: %v", reqs)
+						}
+						if reqs := api.Requests("POST /cloud/v1/instances/1/1/" + s.ID + "/attach_interface"); len(reqs) != 1 {
+							return fmt.Errorf("expected a single attach, got %v", reqs)
+						}
+						if reqs := api.Requests("POST /cloud/v1/floatingips/"); len(reqs) != 0 {
+							return fmt.Errorf("unexpected floating ip change: %v", reqs)
+						}
+						api.ResetRequests()
+						return nil
+					}),
+				),
+			},
+			{
+				Config: template(primary(fipID) + secondary),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "interface.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "interface.0.fip_source", "existing"),
+					resource.TestCheckResourceAttr(fullName, "interface.0.existing_fip_id", fipID),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if port := s.Attributes["interface.0.port_id"]; port != primaryPort {
+							return fmt.Errorf("primary interface was re-attached: port %s, was %s", port, primaryPort)
+						}
+						if reqs := api.Requests("POST /cloud/v1/instances/1/1/" + s.ID); len(reqs) != 0 {
+							return fmt.Errorf("unexpected instance change: %v", reqs)
+						}
+						if fips := portFIPs(primaryPort); len(fips) != 1 || fips[0] != fipID {
+							return fmt.Errorf("expected floating ip %s on port %s, got %v", fipID, primaryPort, fips)
+						}
+						if fip := api.cloud.Get("floatingips", newFIP); fip != nil {
+							return fmt.Errorf("floating ip %s created with the interface was not deleted", newFIP)
+						}
+						api.ResetRequests()
+						return nil
+					}),
+				),
+			},
+			{
+				Config: template(primary("") + secondary),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "interface.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "interface.0.fip_source", "new"),
+					resource.TestCheckResourceAttr(fullName, "interface.0.existing_fip_id", ""),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						fips := portFIPs(primaryPort)
+						if len(fips) != 1 || fips[0] == fipID {
+							return fmt.Errorf("expected a new floating ip on port %s, got %v", primaryPort, fips)
+						}
+						fip := api.cloud.Get("floatingips", fipID)
+						if fip == nil {
+							return fmt.Errorf("existing floating ip %s was deleted", fipID)
+						}
+						if port := fip["port_id"]; port != "" && port != nil {
+							return fmt.Errorf("floating ip %s is still assigned to %v", fipID, port)
+						}
+						newFIP = fips[0]
+						return nil
+					}),
+				),
+			},
+			{
+				// Removing fip_source deletes the floating ip created with
+				// the interface.
+				Config: template(primary("-") + secondary),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "interface.0.fip_source", ""),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if fips := portFIPs(primaryPort); len(fips) != 0 {
+							return fmt.Errorf("expected no floating ip on port %s, got %v", primaryPort, fips)
+						}
+						if fip := api.cloud.Get("floatingips", newFIP); fip != nil {
+							return fmt.Errorf("floating ip %s created with the interface was not deleted", newFIP)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: template(primary(fipID) + secondary),
+				Check:  resource.TestCheckResourceAttr(fullName, "interface.0.existing_fip_id", fipID),
+			},
+			{
+				// An existing floating ip is only unassigned.
+				Config: template(primary("-") + secondary),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "interface.0.fip_source", ""),
+					resource.TestCheckResourceAttr(fullName, "interface.0.existing_fip_id", ""),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if fips := portFIPs(primaryPort); len(fips) != 0 {
+							return fmt.Errorf("expected no floating ip on port %s, got %v", primaryPort, fips)
+						}
+						fip := api.cloud.Get("floatingips", fipID)
+						if fip == nil {
+							return fmt.Errorf("existing floating ip %s was deleted", fipID)
+						}
+						if port := fip["port_id"]; port != "" && port != nil {
+							return fmt.Errorf("floating ip %s is still assigned to %v", fipID, port)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	gcorecloud "github.com/G-Core/gcorelabscloud-go"
@@ -11,13 +13,19 @@ import (
 	"github.com/G-Core/gcorelabscloud-go/gcore/k8s/v1/pools"
 	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
 	"github.com/G-Core/gcorelabscloud-go/gcore/volume/v1/volumes"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
 	K8sPoint         = "k8s/clusters"
 	K8sCreateTimeout = 3600
+
+	// k8sPoolReplacementSuffix makes the temporary name of a pool replacing
+	// a pool of the same name.
+	k8sPoolReplacementSuffix = "-new"
 )
 
 var k8sCreateTimeout = time.Second * time.Duration(K8sCreateTimeout)
@@ -28,10 +36,12 @@ func resourceK8s() *schema.Resource {
 		ReadContext:   resourceK8sRead,
 		UpdateContext: resourceK8sUpdate,
 		DeleteContext: resourceK8sDelete,
-		Description:   "Represent k8s cluster with one default pool.",
+		CustomizeDiff: customdiff.All(cloudScopeDiff, validateK8sVersionDiff),
+		Description:   "Represent k8s cluster with its pools. The pools of the cluster created by gcore_k8s_pool resources aren't managed by it.",
 		Timeouts: &schema.ResourceTimeout{
 			Create: &k8sCreateTimeout,
 			Update: &k8sCreateTimeout,
+			Delete: &k8sCreateTimeout,
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -50,36 +60,26 @@ func resourceK8s() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -99,14 +99,17 @@ func resourceK8s() *schema.Resource {
 			"auto_healing_enabled": &schema.Schema{
 				Type:     schema.TypeBool,
 				Optional: true,
+				ForceNew: true,
 			},
 			"external_dns_enabled": &schema.Schema{
 				Type:     schema.TypeBool,
 				Optional: true,
+				ForceNew: true,
 			},
 			"master_lb_floating_ip_enabled": &schema.Schema{
 				Type:     schema.TypeBool,
 				Optional: true,
+				ForceNew: true,
 			},
 			"pods_ip_pool": &schema.Schema{
 				Type:     schema.TypeString,
@@ -125,8 +128,12 @@ func resourceK8s() *schema.Resource {
 			"pool": &schema.Schema{
 				Type:     schema.TypeList,
 				Required: true,
-				MaxItems: 1,
 				MinItems: 1,
+				Description: "Pools of the cluster, the first one is the default pool. " +
+					"A pool is matched with the pool of the same name in the state, or with the pool of its uuid when it's set, which renames the pool. " +
+					"A pool with a new name is created and the pools left out are deleted. " +
+					"Changing flavor_id or a docker volume setting of a pool recreates the pool, the new one is created before the old one is deleted, under a temporary name suffixed with -new when it keeps its name. " +
+					"An import takes the default pool only.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"name": &schema.Schema{
@@ -158,10 +165,13 @@ func resourceK8s() *schema.Resource {
 						"docker_volume_size": &schema.Schema{
 							Type:     schema.TypeInt,
 							Optional: true,
+							Computed: true,
 						},
 						"uuid": &schema.Schema{
-							Type:     schema.TypeString,
-							Computed: true,
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "Set to the uuid of an existing pool of the cluster to rename it in place, pool blocks are matched by name otherwise",
 						},
 						"stack_id": &schema.Schema{
 							Type:     schema.TypeString,
@@ -237,8 +247,10 @@ func resourceK8s() *schema.Resource {
 				Computed: true,
 			},
 			"version": &schema.Schema{
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Kubernetes version of the cluster, the latest one is used when it isn't set. A change upgrades the cluster in place, downgrades aren't supported.",
 			},
 			"updated_at": &schema.Schema{
 				Type:     schema.TypeString,
@@ -261,9 +273,8 @@ func resourceK8sCreate(ctx context.Context, d *schema.ResourceData, m interface{
 	log.Println("[DEBUG] Start K8s creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, K8sPoint, versionPointV1)
+	client, err := CreateClient(config, d, K8sPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -276,6 +287,7 @@ func resourceK8sCreate(ctx context.Context, d *schema.ResourceData, m interface{
 		AutoHealingEnabled:        d.Get("auto_healing_enabled").(bool),
 		ExternalDNSEnabled:        d.Get("external_dns_enabled").(bool),
 		MasterLBFloatingIPEnabled: d.Get("master_lb_floating_ip_enabled").(bool),
+		Version:                   d.Get("version").(string),
 	}
 
 	if podsIP, ok := d.GetOk("pods_ip_pool"); ok {
@@ -294,28 +306,9 @@ func resourceK8sCreate(ctx context.Context, d *schema.ResourceData, m interface{
 		opts.ServicesIPPool = &gccidr
 	}
 
-	poolRaw := d.Get("pool").([]interface{})
-	pool := poolRaw[0].(map[string]interface{})
-
-	optPool := pools.CreateOpts{
-		Name:         pool["name"].(string),
-		FlavorID:     pool["flavor_id"].(string),
-		NodeCount:    pool["node_count"].(int),
-		MinNodeCount: pool["min_node_count"].(int),
-		MaxNodeCount: pool["max_node_count"].(int),
-	}
-
-	dockerVolumeSize := pool["docker_volume_size"].(int)
-	if dockerVolumeSize != 0 {
-		optPool.DockerVolumeSize = dockerVolumeSize
+	for _, pool := range d.Get("pool").([]interface{}) {
+		opts.Pools = append(opts.Pools, k8sPoolCreateOpts(pool.(map[string]interface{})))
 	}
-
-	dockerVolumeType := pool["docker_volume_type"].(string)
-	if dockerVolumeType != "" {
-		optPool.DockerVolumeType = volumes.VolumeType(dockerVolumeType)
-	}
-
-	opts.Pools = []pools.CreateOpts{optPool}
 	results, err := clusters.Create(client, opts).Extract()
 	if err != nil {
 		return diag.FromErr(err)
@@ -323,7 +316,7 @@ func resourceK8sCreate(ctx context.Context, d *schema.ResourceData, m interface{
 
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	k8sID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, K8sCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	k8sID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -350,9 +343,8 @@ func resourceK8sRead(ctx context.Context, d *schema.ResourceData, m interface{})
 	log.Println("[DEBUG] Start K8s reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, K8sPoint, versionPointV1)
+	client, err := CreateClient(config, d, K8sPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -406,29 +398,13 @@ func resourceK8sRead(ctx context.Context, d *schema.ResourceData, m interface{})
 	d.Set("master_flavor_id", cluster.MasterFlavorID)
 	d.Set("cluster_template_id", cluster.ClusterTemplateID)
 	d.Set("version", cluster.Version)
-	d.Set("updated_at", cluster.UpdatedAt.Format(time.RFC850))
-	d.Set("created_at", cluster.CreatedAt.Format(time.RFC850))
-
-	var pool pools.ClusterPool
-	for _, p := range cluster.Pools {
-		if p.IsDefault {
-			pool = p
-		}
+	if cluster.UpdatedAt != nil {
+		d.Set("updated_at", cluster.UpdatedAt.Format(time.RFC850))
 	}
+	d.Set("created_at", cluster.CreatedAt.Format(time.RFC850))
 
-	p := make(map[string]interface{})
-	p["uuid"] = pool.UUID
-	p["name"] = pool.Name
-	p["flavor_id"] = pool.FlavorID
-	p["min_node_count"] = pool.MinNodeCount
-	p["max_node_count"] = pool.MaxNodeCount
-	p["node_count"] = pool.NodeCount
-	p["docker_volume_type"] = pool.DockerVolumeType.String()
-	p["docker_volume_size"] = pool.DockerVolumeSize
-	p["stack_id"] = pool.StackID
-	p["created_at"] = pool.CreatedAt.Format(time.RFC850)
-
-	if err := d.Set("pool", []interface{}{p}); err != nil {
+	poolIDs := k8sPoolIDs(cluster.Pools, d.Get("pool").([]interface{}))
+	if err := d.Set("pool", flattenK8sPools(cluster.Pools, poolIDs)); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -442,67 +418,25 @@ func resourceK8sRead(ctx context.Context, d *schema.ResourceData, m interface{})
 func resourceK8sUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start K8s updating")
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, K8sPoint, versionPointV1)
+	client, err := CreateClient(config, d, K8sPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	if d.HasChange("pool") {
-		poolRaw := d.Get("pool").([]interface{})[0]
-		pool := poolRaw.(map[string]interface{})
-
-		clusterID := d.Id()
-		poolID := pool["uuid"].(string)
-
-		if d.HasChanges("pool.0.name", "pool.0.min_node_count", "pool.0.max_node_count") {
-			updateOpts := pools.UpdateOpts{
-				Name:         pool["name"].(string),
-				MinNodeCount: pool["min_node_count"].(int),
-				MaxNodeCount: pool["max_node_count"].(int),
-			}
-			results, err := pools.Update(client, clusterID, poolID, updateOpts).Extract()
-			if err != nil {
-				return diag.FromErr(err)
-			}
-
-			taskID := results.Tasks[0]
-			_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, K8sCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
-				_, err := pools.Get(client, clusterID, poolID).Extract()
-				if err != nil {
-					return nil, fmt.Errorf("cannot get pool with ID: %s. Error: %w", poolID, err)
-				}
-				return nil, nil
-			})
-			if err != nil {
-				return diag.FromErr(err)
-			}
+	if d.HasChange("version") {
+		if err := resourceK8sUpgrade(ctx, client, d); err != nil {
+			return diag.FromErr(err)
 		}
+	}
 
-		if d.HasChange("pool.0.node_count") {
-			resizeOpts := clusters.ResizeOpts{
-				NodeCount: pool["node_count"].(int),
-			}
-			results, err := clusters.Resize(client, clusterID, poolID, resizeOpts).Extract()
-			if err != nil {
-				return diag.FromErr(err)
-			}
-
-			taskID := results.Tasks[0]
-			_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, K8sCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
-				_, err := pools.Get(client, clusterID, poolID).Extract()
-				if err != nil {
-					return nil, fmt.Errorf("cannot get pool with ID: %s. Error: %w", poolID, err)
-				}
-				return nil, nil
-			})
-			if err != nil {
-				return diag.FromErr(err)
-			}
+	if d.HasChange("pool") {
+		if err := resourceK8sUpdatePools(ctx, client, d); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
+	d.Set("last_updated", time.Now().Format(time.RFC850))
 	return resourceK8sRead(ctx, d, m)
 }
 
@@ -510,9 +444,8 @@ func resourceK8sDelete(ctx context.Context, d *schema.ResourceData, m interface{
 	log.Println("[DEBUG] Start K8s deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, K8sPoint, versionPointV1)
+	client, err := CreateClient(config, d, K8sPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -524,7 +457,7 @@ func resourceK8sDelete(ctx context.Context, d *schema.ResourceData, m interface{
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, K8sCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := clusters.Get(client, id).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete k8s cluster with ID: %s", id)
@@ -544,3 +477,426 @@ func resourceK8sDelete(ctx context.Context, d *schema.ResourceData, m interface{
 	log.Printf("[DEBUG] Finish of K8s deleting")
 	return diags
 }
+
+func k8sPoolCreateOpts(pool map[string]interface{}) pools.CreateOpts {
+	opts := pools.CreateOpts{
+		Name:         pool["name"].(string),
+		FlavorID:     pool["flavor_id"].(string),
+		NodeCount:    pool["node_count"].(int),
+		MinNodeCount: pool["min_node_count"].(int),
+		MaxNodeCount: pool["max_node_count"].(int),
+	}
+
+	dockerVolumeSize := pool["docker_volume_size"].(int)
+	if dockerVolumeSize != 0 {
+		opts.DockerVolumeSize = dockerVolumeSize
+	}
+
+	dockerVolumeType := pool["docker_volume_type"].(string)
+	if dockerVolumeType != "" {
+		opts.DockerVolumeType = volumes.VolumeType(dockerVolumeType)
+	}
+
+	return opts
+}
+
+// k8sPoolIDs returns the IDs of the cluster pools of the pool blocks, the
+// blocks without uuid, e.g. right after the creation, are matched by name.
+// The other pools of the cluster belong to gcore_k8s_pool resources, so when
+// there are no blocks at all, which is the case of an import, only the
+// default pool created with the cluster is taken.
+func k8sPoolIDs(clusterPools []pools.ClusterPool, blocks []interface{}) []string {
+	ids := make([]string, 0, len(blocks))
+	if len(blocks) == 0 {
+		for _, p := range clusterPools {
+			if p.IsDefault {
+				ids = append(ids, p.UUID)
+			}
+		}
+		return ids
+	}
+
+	used := make(map[string]bool)
+	for _, raw := range blocks {
+		block, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		uuid, _ := block["uuid"].(string)
+		name, _ := block["name"].(string)
+		for _, p := range clusterPools {
+			if used[p.UUID] {
+				continue
+			}
+			if (uuid != "" && p.UUID == uuid) || (uuid == "" && p.Name == name) {
+				ids = append(ids, p.UUID)
+				used[p.UUID] = true
+				break
+			}
+		}
+	}
+	return ids
+}
+
+// flattenK8sPools returns the pool blocks of the existing pools of the IDs,
+// in the order of the IDs.
+func flattenK8sPools(clusterPools []pools.ClusterPool, ids []string) []interface{} {
+	res := make([]interface{}, 0, len(ids))
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		for _, pool := range clusterPools {
+			if pool.UUID != id {
+				continue
+			}
+			p := make(map[string]interface{})
+			p["uuid"] = pool.UUID
+			p["name"] = pool.Name
+			p["flavor_id"] = pool.FlavorID
+			p["min_node_count"] = pool.MinNodeCount
+			p["max_node_count"] = pool.MaxNodeCount
+			p["node_count"] = pool.NodeCount
+			p["docker_volume_type"] = pool.DockerVolumeType.String()
+			p["docker_volume_size"] = pool.DockerVolumeSize
+			p["stack_id"] = pool.StackID
+			p["created_at"] = pool.CreatedAt.Format(time.RFC850)
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// k8sPoolChange pairs a pool block of the config with the block of the
+// state it was, old is nil for a new pool.
+type k8sPoolChange struct {
+	old, new map[string]interface{}
+}
+
+// diffK8sPools pairs the pool blocks of the config with the blocks of the
+// state: a block setting uuid is paired with the pool of that uuid, which
+// renames the pool, the other ones by name. The changes are in the order of
+// the config, the new blocks left unpaired are new pools and the state
+// blocks left are the pools to delete.
+func diffK8sPools(oldBlocks, newBlocks []interface{}) (changes []k8sPoolChange, deleted []map[string]interface{}, err error) {
+	changes = make([]k8sPoolChange, len(newBlocks))
+	oldUsed := make([]bool, len(oldBlocks))
+	for i, n := range newBlocks {
+		changes[i].new = n.(map[string]interface{})
+		uuid, _ := changes[i].new["uuid"].(string)
+		for j, o := range oldBlocks {
+			old := o.(map[string]interface{})
+			if oldUsed[j] {
+				continue
+			}
+			if (uuid != "" && old["uuid"] == uuid) || (uuid == "" && old["name"] == changes[i].new["name"]) {
+				changes[i].old = old
+				oldUsed[j] = true
+				break
+			}
+		}
+		if uuid != "" && changes[i].old == nil {
+			return nil, nil, fmt.Errorf("pool %s of the block %s is not a pool of the cluster", uuid, changes[i].new["name"])
+		}
+	}
+
+	for j, o := range oldBlocks {
+		if !oldUsed[j] {
+			deleted = append(deleted, o.(map[string]interface{}))
+		}
+	}
+	return changes, deleted, nil
+}
+
+// needsReplace reports whether the pool can't be changed in place. The
+// docker volume settings are computed, so they only count when they're set.
+func (c k8sPoolChange) needsReplace() bool {
+	if c.old["flavor_id"] != c.new["flavor_id"] {
+		return true
+	}
+	if t := c.new["docker_volume_type"].(string); t != "" && t != c.old["docker_volume_type"] {
+		return true
+	}
+	if size := c.new["docker_volume_size"].(int); size != 0 && size != c.old["docker_volume_size"] {
+		return true
+	}
+	return false
+}
+
+// k8sConfigPoolBlocks returns the planned pool blocks with the uuid only
+// when it's set in the config: a computed uuid of the plan is the one of the
+// state block at the same position, which isn't necessarily the same pool.
+func k8sConfigPoolBlocks(d *schema.ResourceData, blocks []interface{}) []interface{} {
+	var configPools []cty.Value
+	if raw := d.GetRawConfig(); raw.IsKnown() && !raw.IsNull() {
+		if v := raw.GetAttr("pool"); v.IsKnown() && !v.IsNull() {
+			configPools = v.AsValueSlice()
+		}
+	}
+
+	res := make([]interface{}, 0, len(blocks))
+	for i, raw := range blocks {
+		block := make(map[string]interface{})
+		for k, v := range raw.(map[string]interface{}) {
+			block[k] = v
+		}
+		block["uuid"] = ""
+		if i < len(configPools) {
+			if uuid := configPools[i].GetAttr("uuid"); uuid.IsKnown() && !uuid.IsNull() {
+				block["uuid"] = uuid.AsString()
+			}
+		}
+		res = append(res, block)
+	}
+	return res
+}
+
+// resourceK8sUpdatePools creates, changes and deletes the pools of the pool
+// blocks. When it fails, the state keeps the pools done so far and the old
+// blocks of the others, so no created pool is left out of the state.
+func resourceK8sUpdatePools(ctx context.Context, client *gcorecloud.ServiceClient, d *schema.ResourceData) error {
+	clusterID := d.Id()
+	oldRaw, newRaw := d.GetChange("pool")
+	changes, deleted, err := diffK8sPools(oldRaw.([]interface{}), k8sConfigPoolBlocks(d, newRaw.([]interface{})))
+	if err != nil {
+		return err
+	}
+
+	var poolIDs []string
+	for _, c := range changes {
+		var poolID string
+		switch {
+		case c.old == nil:
+			poolID, err = resourceK8sCreatePool(ctx, client, d, clusterID, c.new)
+		case c.needsReplace():
+			log.Printf("[DEBUG] Recreate k8s pool %s", c.old["uuid"])
+			poolID, err = resourceK8sReplacePool(ctx, client, d, clusterID, c)
+		default:
+			poolID = c.old["uuid"].(string)
+			err = resourceK8sChangePool(ctx, client, d, clusterID, poolID, c.old, c.new)
+		}
+		if poolID != "" {
+			poolIDs = append(poolIDs, poolID)
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		for _, pool := range deleted {
+			if err = resourceK8sDeletePool(ctx, client, d, clusterID, pool["uuid"].(string)); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		// The pools not done yet keep their old blocks, the ones which don't
+		// exist anymore are dropped by flattenK8sPools.
+		for _, c := range changes {
+			if c.old != nil {
+				poolIDs = append(poolIDs, c.old["uuid"].(string))
+			}
+		}
+		for _, pool := range deleted {
+			poolIDs = append(poolIDs, pool["uuid"].(string))
+		}
+	}
+
+	cluster, getErr := clusters.Get(client, clusterID).Extract()
+	if getErr != nil {
+		if err != nil {
+			return err
+		}
+		return getErr
+	}
+	if setErr := d.Set("pool", flattenK8sPools(cluster.Pools, poolIDs)); setErr != nil && err == nil {
+		err = setErr
+	}
+	return err
+}
+
+// resourceK8sReplacePool creates the new pool first, so the cluster keeps its
+// workers while the pool is replaced. The pool names are unique in a cluster,
+// a pool keeping its name is created under a temporary one and renamed once
+// the old pool is deleted.
+func resourceK8sReplacePool(ctx context.Context, client *gcorecloud.ServiceClient, d *schema.ResourceData, clusterID string, c k8sPoolChange) (string, error) {
+	pool := c.new
+	if c.new["name"] == c.old["name"] {
+		pool = make(map[string]interface{}, len(c.new))
+		for k, v := range c.new {
+			pool[k] = v
+		}
+		pool["name"] = c.new["name"].(string) + k8sPoolReplacementSuffix
+	}
+
+	poolID, err := resourceK8sCreatePool(ctx, client, d, clusterID, pool)
+	if err != nil {
+		return "", err
+	}
+	if err := resourceK8sDeletePool(ctx, client, d, clusterID, c.old["uuid"].(string)); err != nil {
+		return poolID, err
+	}
+	return poolID, resourceK8sChangePool(ctx, client, d, clusterID, poolID, pool, c.new)
+}
+
+func resourceK8sCreatePool(ctx context.Context, client *gcorecloud.ServiceClient, d *schema.ResourceData, clusterID string, pool map[string]interface{}) (string, error) {
+	results, err := pools.Create(client, clusterID, k8sPoolCreateOpts(pool)).Extract()
+	if err != nil {
+		return "", err
+	}
+
+	taskID := results.Tasks[0]
+	log.Printf("[DEBUG] Task id (%s)", taskID)
+	poolID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
+		taskInfo, err := tasks.Get(client, string(task)).Extract()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
+		}
+		poolID, err := pools.ExtractClusterPoolIDFromTask(taskInfo)
+		if err != nil {
+			return nil, fmt.Errorf("cannot retrieve k8s pool ID from task info: %w", err)
+		}
+		return poolID, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return poolID.(string), nil
+}
+
+func resourceK8sChangePool(ctx context.Context, client *gcorecloud.ServiceClient, d *schema.ResourceData, clusterID, poolID string, old, new map[string]interface{}) error {
+	if old["name"] != new["name"] || old["min_node_count"] != new["min_node_count"] || old["max_node_count"] != new["max_node_count"] {
+		updateOpts := pools.UpdateOpts{
+			Name:         new["name"].(string),
+			MinNodeCount: new["min_node_count"].(int),
+			MaxNodeCount: new["max_node_count"].(int),
+		}
+		results, err := pools.Update(client, clusterID, poolID, updateOpts).Extract()
+		if err != nil {
+			return err
+		}
+
+		taskID := results.Tasks[0]
+		_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
+			_, err := pools.Get(client, clusterID, poolID).Extract()
+			if err != nil {
+				return nil, fmt.Errorf("cannot get pool with ID: %s. Error: %w", poolID, err)
+			}
+			return nil, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if old["node_count"] != new["node_count"] {
+		resizeOpts := clusters.ResizeOpts{
+			NodeCount: new["node_count"].(int),
+		}
+		results, err := clusters.Resize(client, clusterID, poolID, resizeOpts).Extract()
+		if err != nil {
+			return err
+		}
+
+		taskID := results.Tasks[0]
+		_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
+			_, err := pools.Get(client, clusterID, poolID).Extract()
+			if err != nil {
+				return nil, fmt.Errorf("cannot get pool with ID: %s. Error: %w", poolID, err)
+			}
+			return nil, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resourceK8sDeletePool(ctx context.Context, client *gcorecloud.ServiceClient, d *schema.ResourceData, clusterID, poolID string) error {
+	results, err := pools.Delete(client, clusterID, poolID).Extract()
+	if err != nil {
+		return err
+	}
+
+	taskID := results.Tasks[0]
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
+		_, err := pools.Get(client, clusterID, poolID).Extract()
+		if err == nil {
+			return nil, fmt.Errorf("cannot delete k8s cluster pool with ID: %s", poolID)
+		}
+		switch err.(type) {
+		case gcorecloud.ErrDefault404:
+			return nil, nil
+		default:
+			return nil, err
+		}
+	})
+	return err
+}
+
+// resourceK8sUpgrade upgrades the cluster to the configured version.
+func resourceK8sUpgrade(ctx context.Context, client *gcorecloud.ServiceClient, d *schema.ResourceData) error {
+	clusterID := d.Id()
+	old, new := d.GetChange("version")
+	log.Printf("[DEBUG] Upgrade k8s cluster (%s) from %s to %s", clusterID, old, new)
+
+	err := func() error {
+		results, err := clusters.Upgrade(client, clusterID, clusters.UpgradeOpts{Version: new.(string)}).Extract()
+		if err != nil {
+			return err
+		}
+
+		taskID := results.Tasks[0]
+		log.Printf("[DEBUG] Task id (%s)", taskID)
+		_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), nil)
+		return err
+	}()
+	if err != nil {
+		d.Set("version", old)
+		return fmt.Errorf("cannot upgrade k8s cluster %s to %s: %w", clusterID, new, err)
+	}
+	return nil
+}
+
+// validateK8sVersionDiff rejects a downgrade of the cluster at plan time, the
+// API only upgrades clusters.
+func validateK8sVersionDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("version") || !d.NewValueKnown("version") {
+		return nil
+	}
+	old, new := d.GetChange("version")
+	if old.(string) == "" || new.(string) == "" {
+		return nil
+	}
+	if compareK8sVersions(new.(string), old.(string)) < 0 {
+		return fmt.Errorf("cannot downgrade k8s cluster from %s to %s", old, new)
+	}
+	return nil
+}
+
+// compareK8sVersions compares dotted versions like 1.20.6 part by part, a
+// missing part counts as 0. It returns -1, 0 or 1.
+func compareK8sVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
@@ -21,10 +21,12 @@ func resourceK8sPool() *schema.Resource {
 		ReadContext:   resourceK8sPoolRead,
 		UpdateContext: resourceK8sPoolUpdate,
 		DeleteContext: resourceK8sPoolDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent k8s cluster's pool.",
 		Timeouts: &schema.ResourceTimeout{
 			Create: &k8sCreateTimeout,
 			Update: &k8sCreateTimeout,
+			Delete: &k8sCreateTimeout,
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -44,36 +46,26 @@ func resourceK8sPool() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"cluster_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -130,9 +122,8 @@ func resourceK8sPoolCreate(ctx context.Context, d *schema.ResourceData, m interf
 	log.Println("[DEBUG] Start K8s pool creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, K8sPoint, versionPointV1)
+	client, err := CreateClient(config, d, K8sPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -163,7 +154,7 @@ func resourceK8sPoolCreate(ctx context.Context, d *schema.ResourceData, m interf
 
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	poolID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, K8sCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	poolID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -190,9 +181,8 @@ func resourceK8sPoolRead(ctx context.Context, d *schema.ResourceData, m interfac
 	log.Println("[DEBUG] Start K8s pool reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, K8sPoint, versionPointV1)
+	client, err := CreateClient(config, d, K8sPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -223,9 +213,8 @@ func resourceK8sPoolRead(ctx context.Context, d *schema.ResourceData, m interfac
 func resourceK8sPoolUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start K8s updating")
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, K8sPoint, versionPointV1)
+	client, err := CreateClient(config, d, K8sPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -245,7 +234,7 @@ func resourceK8sPoolUpdate(ctx context.Context, d *schema.ResourceData, m interf
 		}
 
 		taskID := results.Tasks[0]
-		_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, K8sCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+		_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
 			_, err := pools.Get(client, clusterID, poolID).Extract()
 			if err != nil {
 				return nil, fmt.Errorf("cannot get pool with ID: %s. Error: %w", poolID, err)
@@ -268,7 +257,7 @@ func resourceK8sPoolUpdate(ctx context.Context, d *schema.ResourceData, m interf
 		}
 
 		taskID := results.Tasks[0]
-		_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, K8sCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+		_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
 			_, err := pools.Get(client, clusterID, poolID).Extract()
 			if err != nil {
 				return nil, fmt.Errorf("cannot get pool with ID: %s. Error: %w", poolID, err)
@@ -287,9 +276,8 @@ func resourceK8sPoolDelete(ctx context.Context, d *schema.ResourceData, m interf
 	log.Println("[DEBUG] Start K8s deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, K8sPoint, versionPointV1)
+	client, err := CreateClient(config, d, K8sPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -302,7 +290,7 @@ func resourceK8sPoolDelete(ctx context.Context, d *schema.ResourceData, m interf
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, K8sCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := pools.Get(client, clusterID, id).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete k8s cluster pool with ID: %s", id)
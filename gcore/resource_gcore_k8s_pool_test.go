@@ -218,3 +218,81 @@ func testAccK8sPoolDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitK8sPool(t *testing.T) {
+	api := newMockAPI(t)
+	clusterID := api.cloud.SeedK8sCluster("cluster")
+	fullName := "gcore_k8s_pool.test"
+
+	template := func(name string, nodes, max int) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_k8s_pool" "test" {
+  %s
+  cluster_id         = "%s"
+  name               = "%s"
+  flavor_id          = "g1-standard-1-2"
+  node_count         = %d
+  min_node_count     = 1
+  max_node_count     = %d
+  docker_volume_type = "ssd_hiiops"
+  docker_volume_size = 10
+}
+`, mockCloudScope, clusterID, name, nodes, max)
+	}
+	pool := func(id string) map[string]interface{} {
+		for _, p := range api.cloud.K8sPools(clusterID) {
+			if p["uuid"] == id {
+				return p
+			}
+		}
+		return nil
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			for _, rs := range s.RootModule().Resources {
+				if rs.Type == "gcore_k8s_pool" && pool(rs.Primary.ID) != nil {
+					return fmt.Errorf("pool %s still exists", rs.Primary.ID)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: template("workers", 1, 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "workers"),
+					resource.TestCheckResourceAttr(fullName, "cluster_id", clusterID),
+					resource.TestCheckResourceAttr(fullName, "node_count", "1"),
+					resource.TestCheckResourceAttr(fullName, "max_node_count", "2"),
+					resource.TestCheckResourceAttr(fullName, "docker_volume_type", "ssd_hiiops"),
+					resource.TestCheckResourceAttrSet(fullName, "stack_id"),
+				),
+			},
+			{
+				Config: template("workers-renamed", 3, 4),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "workers-renamed"),
+					resource.TestCheckResourceAttr(fullName, "node_count", "3"),
+					resource.TestCheckResourceAttr(fullName, "max_node_count", "4"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if p := pool(s.ID); mockInt(p["node_count"]) != 3 {
+							return fmt.Errorf("pool wasn't resized: %v", p["node_count"])
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName: fullName,
+				ImportState:  true,
+				ImportStateIdFunc: unitImportID(fullName, func(s *terraform.InstanceState) string {
+					return fmt.Sprintf("%d:%d:%s:%s", mockProjectID, mockRegionID, s.ID, clusterID)
+				}),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "project_name", "region_name"},
+			},
+		},
+	})
+}
@@ -5,9 +5,13 @@ package gcore
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/G-Core/gcorelabscloud-go/gcore/k8s/v1/clusters"
@@ -146,3 +150,298 @@ func testAccK8sDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+// unitK8sPool returns a pool block of gcore_k8s, extra holds more pool
+// arguments.
+func unitK8sPool(name, flavor string, nodes int, extra string) string {
+	return fmt.Sprintf(`
+  pool {
+    name           = "%s"
+    flavor_id      = "%s"
+    min_node_count = 1
+    max_node_count = 5
+    node_count     = %d
+    %s
+  }
+`, name, flavor, nodes, extra)
+}
+
+// unitK8sTemplate returns the gcore_k8s.test cluster of the pools, extra
+// holds more cluster arguments.
+func unitK8sTemplate(api *mockAPI, pools, extra string) string {
+	return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_k8s" "test" {
+  %s
+  name          = "cluster"
+  fixed_network = "aaaaaaaa-0000-4000-8000-000000000001"
+  fixed_subnet  = "aaaaaaaa-0000-4000-8000-000000000002"
+  keypair       = "keypair"
+  %s
+%s
+}
+`, mockCloudScope, extra, pools)
+}
+
+func TestUnitK8sPools(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_k8s.test"
+
+	// checkPools compares the pool names of the cluster, the pool seeded
+	// in the first step stands for a pool of a gcore_k8s_pool resource.
+	checkPools := func(names ...string) func(*terraform.InstanceState) error {
+		return func(s *terraform.InstanceState) error {
+			var got []string
+			for _, p := range api.cloud.K8sPools(s.ID) {
+				got = append(got, mockString(p["name"]))
+			}
+			if strings.Join(got, ",") != strings.Join(names, ",") {
+				return fmt.Errorf("expected pools %v, got %v", names, got)
+			}
+			return nil
+		}
+	}
+	var defaultID, extraID, thirdID string
+	// the rename step pins the default pool by its uuid, known once the
+	// cluster is created
+	defaultIDFile := filepath.Join(t.TempDir(), "default_pool_uuid")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			for _, rs := range s.RootModule().Resources {
+				if rs.Type == "gcore_k8s" && api.cloud.K8sPools(rs.Primary.ID) != nil {
+					return fmt.Errorf("cluster %s still exists", rs.Primary.ID)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: unitK8sTemplate(api, unitK8sPool("default", "g1-standard-1-2", 1, "")+
+					unitK8sPool("extra", "g1-standard-1-2", 2, ""), ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "pool.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "pool.0.name", "default"),
+					resource.TestCheckResourceAttr(fullName, "pool.1.name", "extra"),
+					resource.TestCheckResourceAttr(fullName, "pool.1.node_count", "2"),
+					resource.TestCheckResourceAttr(fullName, "pool.1.docker_volume_type", "standard"),
+					resource.TestCheckResourceAttr(fullName, "node_count", "3"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						defaultID = s.Attributes["pool.0.uuid"]
+						if err := ioutil.WriteFile(defaultIDFile, []byte(defaultID), 0o644); err != nil {
+							return err
+						}
+						api.cloud.SeedK8sPool(s.ID, map[string]interface{}{
+							"name":           "foreign",
+							"flavor_id":      "g1-standard-1-2",
+							"min_node_count": 1,
+							"max_node_count": 5,
+							"node_count":     1,
+						})
+						return checkPools("default", "extra", "foreign")(s)
+					}),
+				),
+			},
+			{
+				// Renames the default pool, resizes extra and adds third,
+				// the foreign pool isn't in the state and stays as it is.
+				Config: unitK8sTemplate(api, unitK8sPool("main", "g1-standard-1-2", 1, fmt.Sprintf("uuid = trimspace(file(%q))", defaultIDFile))+
+					unitK8sPool("extra", "g1-standard-1-2", 3, "")+
+					unitK8sPool("third", "g1-standard-1-2", 1, ""), ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "pool.#", "3"),
+					resource.TestCheckResourceAttr(fullName, "pool.0.name", "main"),
+					resource.TestCheckResourceAttr(fullName, "pool.1.node_count", "3"),
+					resource.TestCheckResourceAttr(fullName, "pool.2.name", "third"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if s.Attributes["pool.0.uuid"] != defaultID {
+							return fmt.Errorf("expected the default pool to be renamed, not recreated")
+						}
+						extraID = s.Attributes["pool.1.uuid"]
+						thirdID = s.Attributes["pool.2.uuid"]
+						return checkPools("main", "extra", "foreign", "third")(s)
+					}),
+				),
+			},
+			{
+				// Deletes extra, recreates third with another flavor and adds
+				// fifth, which doesn't take over extra.
+				Config: unitK8sTemplate(api, unitK8sPool("main", "g1-standard-1-2", 1, "")+
+					unitK8sPool("third", "g1-standard-2-4", 1, "")+
+					unitK8sPool("fifth", "g1-standard-1-2", 1, ""), ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "pool.#", "3"),
+					resource.TestCheckResourceAttr(fullName, "pool.1.name", "third"),
+					resource.TestCheckResourceAttr(fullName, "pool.1.flavor_id", "g1-standard-2-4"),
+					resource.TestCheckResourceAttr(fullName, "pool.2.name", "fifth"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if s.Attributes["pool.1.uuid"] == thirdID {
+							return fmt.Errorf("expected pool third to be recreated")
+						}
+						if s.Attributes["pool.2.uuid"] == extraID {
+							return fmt.Errorf("expected pool fifth to be created, not renamed from extra")
+						}
+						return checkPools("main", "foreign", "third", "fifth")(s)
+					}),
+				),
+			},
+			{
+				// The import takes the default pool only, the foreign one
+				// belongs to another resource.
+				ResourceName:      fullName,
+				ImportState:       true,
+				ImportStateIdFunc: unitCloudImportID(fullName),
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"last_updated", "project_name", "region_name", "pool",
+					"auto_healing_enabled", "pods_ip_pool", "services_ip_pool",
+				},
+			},
+		},
+	})
+}
+
+func TestUnitK8sUpgrade(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_k8s.test"
+	template := func(version string, flags bool) string {
+		return unitK8sTemplate(api, unitK8sPool("default", "g1-standard-1-2", 1, ""), fmt.Sprintf(`version                       = "%s"
+  auto_healing_enabled          = %t
+  external_dns_enabled          = %t
+  master_lb_floating_ip_enabled = %t`, version, flags, flags, flags))
+	}
+	var clusterID string
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: template("1.20.6", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "version", "1.20.6"),
+					resource.TestCheckResourceAttr(fullName, "external_dns_enabled", "false"),
+					resource.TestCheckResourceAttr(fullName, "master_lb_floating_ip_enabled", "false"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						clusterID = s.ID
+						api.ResetRequests()
+						return nil
+					}),
+				),
+			},
+			{
+				// The version changes in place.
+				Config: template("1.21.4", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "version", "1.21.4"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if s.ID != clusterID {
+							return fmt.Errorf("expected the cluster to be upgraded in place")
+						}
+						if got := api.Requests("POST /cloud/v1/k8s/clusters/1/1/" + s.ID + "/upgrade"); len(got) != 1 {
+							return fmt.Errorf("expected 1 upgrade request, got %q", got)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				// The API has no update of the flags, the cluster is replaced.
+				Config: template("1.21.4", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "auto_healing_enabled", "true"),
+					resource.TestCheckResourceAttr(fullName, "external_dns_enabled", "true"),
+					resource.TestCheckResourceAttr(fullName, "master_lb_floating_ip_enabled", "true"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if s.ID == clusterID {
+							return fmt.Errorf("expected the cluster to be replaced")
+						}
+						cluster := api.cloud.K8sCluster(s.ID)
+						if !mockBool(cluster["auto_healing_enabled"]) || !mockBool(cluster["floating_ip_enabled"]) {
+							return fmt.Errorf("expected the flags enabled, got %v", cluster)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config:      template("1.20.6", true),
+				ExpectError: regexp.MustCompile("cannot downgrade k8s cluster from 1.21.4 to 1.20.6"),
+			},
+			{
+				Config: template("1.21.4", true),
+			},
+			{
+				ResourceName:      fullName,
+				ImportState:       true,
+				ImportStateIdFunc: unitCloudImportID(fullName),
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"last_updated", "project_name", "region_name", "pool",
+					"auto_healing_enabled", "pods_ip_pool", "services_ip_pool",
+				},
+			},
+		},
+	})
+}
+
+func TestUnitK8sVersionCompare(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"1.20.6", "1.20.6", 0},
+		{"1.21.0", "1.20.6", 1},
+		{"1.9.1", "1.10.0", -1},
+		{"v1.20", "1.20.0", 0},
+		{"1.20.6", "1.20", 1},
+	} {
+		if got := compareK8sVersions(tc.a, tc.b); got != tc.want {
+			t.Errorf("compare %s with %s: expected %d, got %d", tc.a, tc.b, tc.want, got)
+		}
+	}
+}
+
+func TestUnitK8sPoolsDiff(t *testing.T) {
+	block := func(uuid, name string) interface{} {
+		return map[string]interface{}{"uuid": uuid, "name": name}
+	}
+	changes, deleted, err := diffK8sPools(
+		[]interface{}{block("1", "a"), block("2", "b"), block("3", "c")},
+		[]interface{}{block("", "c"), block("2", "x"), block("", "y"), block("", "a")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range changes {
+		old := "-"
+		if c.old != nil {
+			old = c.old["uuid"].(string)
+		}
+		got = append(got, old+">"+c.new["name"].(string))
+	}
+	// c and a keep their pools, x renames b by its uuid and y is a new pool.
+	if want := "3>c,2>x,->y,1>a"; strings.Join(got, ",") != want {
+		t.Fatalf("expected changes %s, got %s", want, strings.Join(got, ","))
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("expected no deleted pools, got %v", deleted)
+	}
+
+	// a removed and b added is not a rename of a.
+	changes, deleted, err = diffK8sPools([]interface{}{block("1", "a")}, []interface{}{block("", "b")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].old != nil {
+		t.Fatalf("expected pool b to be created, got %v", changes)
+	}
+	if len(deleted) != 1 || deleted[0]["uuid"] != "1" {
+		t.Fatalf("expected pool a deleted, got %v", deleted)
+	}
+
+	if _, _, err := diffK8sPools([]interface{}{block("1", "a")}, []interface{}{block("9", "a")}); err == nil {
+		t.Fatal("expected an error for an unknown pool uuid")
+	}
+}
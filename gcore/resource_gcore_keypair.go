@@ -19,22 +19,17 @@ func resourceKeypair() *schema.Resource {
 		Description:   "Represent a ssh key, do not depends on region",
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"public_key": &schema.Schema{
 				Type:     schema.TypeString,
@@ -63,9 +58,13 @@ func resourceKeypairCreate(ctx context.Context, d *schema.ResourceData, m interf
 
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, keypairsPoint, versionPointV2)
+	client, err := CreateClient(config, d, keypairsPoint, versionPointV2)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	projectID, _, err := cloudScope(config, d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -73,7 +72,7 @@ func resourceKeypairCreate(ctx context.Context, d *schema.ResourceData, m interf
 	opts := keypairs.CreateOpts{
 		Name:      d.Get("sshkey_name").(string),
 		PublicKey: d.Get("public_key").(string),
-		ProjectID: d.Get("project_id").(int),
+		ProjectID: projectID,
 	}
 
 	kp, err := keypairs.Create(client, opts).Extract()
@@ -95,9 +94,8 @@ func resourceKeypairRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, keypairsPoint, versionPointV2)
+	client, err := CreateClient(config, d, keypairsPoint, versionPointV2)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -123,9 +121,8 @@ func resourceKeypairDelete(ctx context.Context, d *schema.ResourceData, m interf
 
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, keypairsPoint, versionPointV2)
+	client, err := CreateClient(config, d, keypairsPoint, versionPointV2)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -5,6 +5,7 @@ package gcore
 
 import (
 	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/G-Core/gcorelabscloud-go/gcore/keypair/v2/keypairs"
@@ -73,3 +74,62 @@ func testAccKeypairDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitKeypair(t *testing.T) {
+	api := newMockAPI(t)
+	const publicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGtNXo/Ec9AzCgcuL2FJiNOkA1Kz3BhKb8rBi3jBdd8g unit@test"
+	fullName := "gcore_keypair.test"
+	template := func(name string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_keypair" "test" {
+  project_id  = %d
+  sshkey_name = "%s"
+  public_key  = "%s"
+}
+`, mockProjectID, name, publicKey)
+	}
+	var firstID string
+
+	// gcore_keypair can't be imported.
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			if n := api.cloud.Count("keypairs"); n != 0 {
+				return fmt.Errorf("%d keypairs still exist", n)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: template("key"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "sshkey_name", "key"),
+					resource.TestCheckResourceAttr(fullName, "public_key", publicKey),
+					resource.TestCheckResourceAttrSet(fullName, "sshkey_id"),
+					resource.TestCheckResourceAttrSet(fullName, "fingerprint"),
+					resource.TestCheckResourceAttr(fullName, "project_id", strconv.Itoa(mockProjectID)),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						firstID = s.ID
+						return nil
+					}),
+				),
+			},
+			{
+				// The keypairs can't be changed, a new name replaces it.
+				Config: template("key-renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "sshkey_name", "key-renamed"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if s.ID == firstID {
+							return fmt.Errorf("expected a new keypair, got %s", s.ID)
+						}
+						if api.cloud.Count("keypairs") != 1 {
+							return fmt.Errorf("expected the old keypair deleted, got %d keypairs", api.cloud.Count("keypairs"))
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
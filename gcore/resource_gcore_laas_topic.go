@@ -16,6 +16,7 @@ func resourceLaaSTopic() *schema.Resource {
 		CreateContext: resourceLaaSTopicCreate,
 		ReadContext:   resourceLaaSTopicRead,
 		DeleteContext: resourceLaaSTopicDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent LaaS topic",
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -34,40 +35,30 @@ func resourceLaaSTopic() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -82,9 +73,8 @@ func resourceLaaSTopicCreate(ctx context.Context, d *schema.ResourceData, m inte
 	log.Println("[DEBUG] Start LaaS topic creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, laasPoint, versionPointV1)
+	client, err := CreateClient(config, d, laasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -106,11 +96,10 @@ func resourceLaaSTopicRead(ctx context.Context, d *schema.ResourceData, m interf
 	log.Println("[DEBUG] Start LaaS topic reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	topicName := d.Id()
 	log.Printf("[DEBUG] Topic id = %s", topicName)
 
-	client, err := CreateClient(provider, d, laasPoint, versionPointV1)
+	client, err := CreateClient(config, d, laasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -139,11 +128,10 @@ func resourceLaaSTopicDelete(ctx context.Context, d *schema.ResourceData, m inte
 	log.Println("[DEBUG] Start LaaS topic deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	topicName := d.Id()
 	log.Printf("[DEBUG] Topic id = %s", topicName)
 
-	client, err := CreateClient(provider, d, laasPoint, versionPointV1)
+	client, err := CreateClient(config, d, laasPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -67,3 +67,52 @@ func testAccLaaSTopicDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitLaaSTopic(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_laas_topic.test"
+	template := func(name string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_laas_topic" "test" {
+  %s
+  name = "%s"
+}
+`, mockCloudScope, name)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			if n := api.cloud.Count("laas_topics"); n != 0 {
+				return fmt.Errorf("%d topics still exist", n)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: template("logs"),
+				Check:  resource.TestCheckResourceAttr(fullName, "name", "logs"),
+			},
+			{
+				// The topics can't be renamed, a new name replaces it.
+				Config: template("audit"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "audit"),
+					func(s *terraform.State) error {
+						if n := api.cloud.Count("laas_topics"); n != 1 {
+							return fmt.Errorf("expected the old topic deleted, got %d topics", n)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"project_name", "region_name"},
+			},
+		},
+	})
+}
@@ -26,10 +26,12 @@ func resourceLbListener() *schema.Resource {
 		ReadContext:   resourceLBListenerRead,
 		UpdateContext: resourceLBListenerUpdate,
 		DeleteContext: resourceLBListenerDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent load balancer listener. Can not be created without load balancer. A listener is a process that checks for connection requests, using the protocol and port that you configure",
 		Timeouts: &schema.ResourceTimeout{
-			Create: schema.DefaultTimeout(5 * time.Minute),
-			Delete: schema.DefaultTimeout(5 * time.Minute),
+			Create: schema.DefaultTimeout(time.Duration(LBListenerCreateTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(LBListenerCreateTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(LBListenerCreateTimeout) * time.Second),
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -49,40 +51,30 @@ func resourceLbListener() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"loadbalancer_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -152,9 +144,8 @@ func resourceLBListenerCreate(ctx context.Context, d *schema.ResourceData, m int
 	log.Println("[DEBUG] Start LBListener creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LBListenersPoint, versionPointV1)
+	client, err := CreateClient(config, d, LBListenersPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -182,7 +173,7 @@ func resourceLBListenerCreate(ctx context.Context, d *schema.ResourceData, m int
 	}
 
 	taskID := results.Tasks[0]
-	listenerID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, LBListenerCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	listenerID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -209,9 +200,8 @@ func resourceLBListenerRead(ctx context.Context, d *schema.ResourceData, m inter
 	log.Println("[DEBUG] Start LBListener reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LBListenersPoint, versionPointV1)
+	client, err := CreateClient(config, d, LBListenersPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -239,19 +229,15 @@ func resourceLBListenerRead(ctx context.Context, d *schema.ResourceData, m inter
 func resourceLBListenerUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start LBListener updating")
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LBListenersPoint, versionPointV1)
+	client, err := CreateClient(config, d, LBListenersPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	var changed bool
-	opts := listeners.UpdateOpts{}
-	if d.HasChange("name") {
-		opts.Name = d.Get("name").(string)
-		changed = true
-	}
+	// The name is required by the API with every change.
+	opts := listeners.UpdateOpts{Name: d.Get("name").(string)}
+	changed := d.HasChange("name")
 
 	if d.HasChange("secret_id") {
 		opts.SecretID = d.Get("secret_id").(string)
@@ -285,9 +271,8 @@ func resourceLBListenerDelete(ctx context.Context, d *schema.ResourceData, m int
 	log.Println("[DEBUG] Start LBListener deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LBListenersPoint, versionPointV1)
+	client, err := CreateClient(config, d, LBListenersPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -299,7 +284,7 @@ func resourceLBListenerDelete(ctx context.Context, d *schema.ResourceData, m int
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, LBListenerCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := listeners.Get(client, id).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete LBListener with ID: %s", id)
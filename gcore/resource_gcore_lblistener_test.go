@@ -5,7 +5,10 @@ package gcore
 
 import (
 	"fmt"
+	"net/http"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/listeners"
 	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/loadbalancers"
@@ -103,3 +106,222 @@ func testAccLBListenerDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitLBListener(t *testing.T) {
+	api := newMockAPI(t)
+	lbID := api.cloud.Seed("loadbalancers", map[string]interface{}{"name": "lb"})
+	fullName := "gcore_lblistener.test"
+
+	template := func(name string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_lblistener" "test" {
+  %s
+  name            = "%s"
+  loadbalancer_id = "%s"
+  protocol        = "HTTP"
+  protocol_port   = 80
+}
+`, mockCloudScope, name, lbID)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_lblistener", "lblisteners"),
+		Steps: []resource.TestStep{
+			{
+				Config: template("listener"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "listener"),
+					resource.TestCheckResourceAttr(fullName, "protocol", "HTTP"),
+					resource.TestCheckResourceAttr(fullName, "protocol_port", "80"),
+					resource.TestCheckResourceAttr(fullName, "operating_status", "ONLINE"),
+					resource.TestCheckResourceAttr(fullName, "provisioning_status", "ACTIVE"),
+				),
+			},
+			{
+				Config: template("listener-renamed"),
+				Check:  resource.TestCheckResourceAttr(fullName, "name", "listener-renamed"),
+			},
+			{
+				ResourceName: fullName,
+				ImportState:  true,
+				ImportStateIdFunc: unitImportID(fullName, func(s *terraform.InstanceState) string {
+					return fmt.Sprintf("%d:%d:%s:%s", mockProjectID, mockRegionID, s.ID, lbID)
+				}),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "project_name", "region_name"},
+			},
+		},
+	})
+}
+
+// unitSecretHCL returns the gcore_secret.cert resource of the certificate.
+func unitSecretHCL(name, cert, key string, createBeforeDestroy bool) string {
+	lifecycle := ""
+	if createBeforeDestroy {
+		lifecycle = `
+  lifecycle {
+    create_before_destroy = true
+  }
+`
+	}
+	return fmt.Sprintf(`
+resource "gcore_secret" "cert" {
+  %s
+  name              = %q
+  certificate       = %q
+  certificate_chain = %q
+  private_key       = %q
+%s}
+`, mockCloudScope, name, cert, cert, key, lifecycle)
+}
+
+// unitHTTPSListenerHCL returns the gcore_lblistener.test resource using the
+// gcore_secret.cert secret.
+func unitHTTPSListenerHCL(lbID string) string {
+	return fmt.Sprintf(`
+resource "gcore_lblistener" "test" {
+  %s
+  name            = "https"
+  loadbalancer_id = "%s"
+  protocol        = "TERMINATED_HTTPS"
+  protocol_port   = 443
+  secret_id       = gcore_secret.cert.id
+}
+`, mockCloudScope, lbID)
+}
+
+func TestUnitSecretListener(t *testing.T) {
+	api := newMockAPI(t)
+	lbID := api.cloud.Seed("loadbalancers", map[string]interface{}{"name": "lb"})
+	firstCert, firstKey := unitCertificate(t, "example.com", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	secondCert, secondKey := unitCertificate(t, "example.com", time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC))
+	fullName := "gcore_secret.cert"
+	var firstID, listenerID, sniListenerID string
+
+	// The listeners are created through the API: the state of a listener
+	// managed in the same configuration only gets the new secret with the
+	// next refresh, which the post apply plan of the test doesn't do.
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_secret", "secrets"),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + unitSecretHCL("cert", firstCert, firstKey, false),
+				Check: unitCheckState(fullName, func(s *terraform.InstanceState) error {
+					firstID = s.ID
+					return nil
+				}),
+			},
+			{
+				// A listener failing to switch gets the others back to the
+				// old secret, the new one is deleted.
+				PreConfig: func() {
+					listenerID = api.cloud.Seed("lblisteners", map[string]interface{}{
+						"loadbalancer_id": lbID,
+						"name":            "https",
+						"protocol":        "TERMINATED_HTTPS",
+						"protocol_port":   443,
+						"secret_id":       firstID,
+					})
+					sniListenerID = api.cloud.Seed("lblisteners", map[string]interface{}{
+						"loadbalancer_id": lbID,
+						"name":            "sni",
+						"protocol":        "TERMINATED_HTTPS",
+						"protocol_port":   8443,
+						"sni_secret_id":   []interface{}{"other", firstID},
+					})
+					api.FailNext(http.MethodPatch, "/cloud/v1/lblisteners/1/1/"+sniListenerID, http.StatusBadRequest, "", 1)
+				},
+				Config:      api.providerHCL("") + unitSecretHCL("cert", secondCert, secondKey, false),
+				ExpectError: regexp.MustCompile("cannot switch listeners from secret"),
+			},
+			{
+				Config: api.providerHCL("") + unitSecretHCL("cert", firstCert, firstKey, false),
+				Check: unitCheckState(fullName, func(s *terraform.InstanceState) error {
+					if s.ID != firstID {
+						return fmt.Errorf("expected the secret %s kept, got %s", firstID, s.ID)
+					}
+					if got := mockString(api.cloud.Get("lblisteners", listenerID)["secret_id"]); got != firstID {
+						return fmt.Errorf("expected the listener back on secret %s, got %s", firstID, got)
+					}
+					if n := api.cloud.Count("secrets"); n != 1 {
+						return fmt.Errorf("expected the new secret deleted, %d secrets exist", n)
+					}
+					return nil
+				}),
+			},
+			{
+				// A new certificate switches the listeners to the new secret
+				// before the old one is deleted.
+				Config: api.providerHCL("") + unitSecretHCL("cert", secondCert, secondKey, false),
+				Check: unitCheckState(fullName, func(s *terraform.InstanceState) error {
+					if s.ID == firstID {
+						return fmt.Errorf("expected a new secret")
+					}
+					if got := mockString(api.cloud.Get("lblisteners", listenerID)["secret_id"]); got != s.ID {
+						return fmt.Errorf("expected the listener switched to secret %s, got %s", s.ID, got)
+					}
+					sni := mockList(api.cloud.Get("lblisteners", sniListenerID)["sni_secret_id"])
+					if len(sni) != 2 || sni[0] != "other" || sni[1] != s.ID {
+						return fmt.Errorf("expected the SNI secrets [other %s], got %v", s.ID, sni)
+					}
+					if api.cloud.Get("secrets", firstID) != nil {
+						return fmt.Errorf("expected the old secret %s deleted", firstID)
+					}
+					return nil
+				}),
+			},
+			{
+				PreConfig: func() {
+					api.cloud.Remove("lblisteners", listenerID)
+					api.cloud.Remove("lblisteners", sniListenerID)
+				},
+				Config: api.providerHCL("") + unitSecretHCL("cert", secondCert, secondKey, false),
+			},
+		},
+	})
+}
+
+func TestUnitSecretListenerReplace(t *testing.T) {
+	api := newMockAPI(t)
+	lbID := api.cloud.Seed("loadbalancers", map[string]interface{}{"name": "lb"})
+	cert, key := unitCertificate(t, "example.com", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	fullName := "gcore_lblistener.test"
+	template := func(name string, createBeforeDestroy bool) string {
+		return api.providerHCL("") + unitHTTPSListenerHCL(lbID) + unitSecretHCL(name, cert, key, createBeforeDestroy)
+	}
+	var firstID string
+
+	// A new name replaces the secret, without create_before_destroy the
+	// secret in use is deleted first, which the API refuses.
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: template("cert", false),
+				Check: unitCheckState(fullName, func(s *terraform.InstanceState) error {
+					firstID = s.Attributes["secret_id"]
+					return nil
+				}),
+			},
+			{
+				Config:      template("renamed", false),
+				ExpectError: regexp.MustCompile("set create_before_destroy in the lifecycle of gcore_secret"),
+			},
+			{
+				Config: template("renamed", true),
+				Check: unitCheckState(fullName, func(s *terraform.InstanceState) error {
+					secretID := mockString(api.cloud.Get("lblisteners", s.ID)["secret_id"])
+					if secretID == firstID || secretID != s.Attributes["secret_id"] {
+						return fmt.Errorf("expected the listener switched to the new secret, got %s", secretID)
+					}
+					if api.cloud.Get("secrets", firstID) != nil {
+						return fmt.Errorf("expected the old secret %s deleted", firstID)
+					}
+					return nil
+				}),
+			},
+		},
+	})
+}
@@ -27,10 +27,12 @@ func resourceLBMember() *schema.Resource {
 		ReadContext:   resourceLBMemberRead,
 		UpdateContext: resourceLBMemberUpdate,
 		DeleteContext: resourceLBMemberDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent load balancer member",
 		Timeouts: &schema.ResourceTimeout{
-			Create: schema.DefaultTimeout(5 * time.Minute),
-			Delete: schema.DefaultTimeout(5 * time.Minute),
+			Create: schema.DefaultTimeout(time.Duration(LBPoolsCreateTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(LBPoolsCreateTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(LBPoolsCreateTimeout) * time.Second),
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -50,40 +52,30 @@ func resourceLBMember() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"pool_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -145,9 +137,8 @@ func resourceLBMemberCreate(ctx context.Context, d *schema.ResourceData, m inter
 	log.Println("[DEBUG] Start LBMember creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LBPoolsPoint, versionPointV1)
+	client, err := CreateClient(config, d, LBPoolsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -166,7 +157,7 @@ func resourceLBMemberCreate(ctx context.Context, d *schema.ResourceData, m inter
 	}
 
 	taskID := results.Tasks[0]
-	pmID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, LBPoolsCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	pmID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -193,9 +184,8 @@ func resourceLBMemberRead(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Println("[DEBUG] Start LBMember reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LBPoolsPoint, versionPointV1)
+	client, err := CreateClient(config, d, LBPoolsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -227,9 +217,8 @@ func resourceLBMemberRead(ctx context.Context, d *schema.ResourceData, m interfa
 func resourceLBMemberUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start LBMember updating")
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LBPoolsPoint, versionPointV1)
+	client, err := CreateClient(config, d, LBPoolsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -270,7 +259,7 @@ func resourceLBMemberUpdate(ctx context.Context, d *schema.ResourceData, m inter
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, LBPoolsCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -294,9 +283,8 @@ func resourceLBMemberDelete(ctx context.Context, d *schema.ResourceData, m inter
 	log.Println("[DEBUG] Start LBMember deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LBPoolsPoint, versionPointV1)
+	client, err := CreateClient(config, d, LBPoolsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -316,7 +304,7 @@ func resourceLBMemberDelete(ctx context.Context, d *schema.ResourceData, m inter
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, LBPoolsCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		pool, err := lbpools.Get(client, pid).Extract()
 		if err != nil {
 			return nil, err
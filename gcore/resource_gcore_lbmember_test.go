@@ -181,3 +181,68 @@ func createTestLBPool(client *gcorecloud.ServiceClient, opts lbpools.CreateOpts)
 	}
 	return poolID.(string), nil
 }
+
+func TestUnitLBMember(t *testing.T) {
+	api := newMockAPI(t)
+	lbID := api.cloud.Seed("loadbalancers", map[string]interface{}{"name": "lb"})
+	poolID := api.cloud.Seed("lbpools", map[string]interface{}{
+		"name":            "pool",
+		"protocol":        "HTTP",
+		"lb_algorithm":    "ROUND_ROBIN",
+		"loadbalancer_id": lbID,
+	})
+	fullName := "gcore_lbmember.test"
+
+	template := func(weight int) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_lbmember" "test" {
+  %s
+  pool_id       = "%s"
+  address       = "10.10.2.15"
+  protocol_port = 8080
+  weight        = %d
+}
+`, mockCloudScope, poolID, weight)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			pool := api.cloud.Get("lbpools", poolID)
+			for _, rs := range s.RootModule().Resources {
+				if rs.Type != "gcore_lbmember" {
+					continue
+				}
+				for _, m := range mockList(pool["members"]) {
+					if mockMap(m)["id"] == rs.Primary.ID {
+						return fmt.Errorf("member %s still exists", rs.Primary.ID)
+					}
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: template(5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "address", "10.10.2.15"),
+					resource.TestCheckResourceAttr(fullName, "protocol_port", "8080"),
+					resource.TestCheckResourceAttr(fullName, "weight", "5"),
+				),
+			},
+			{
+				Config: template(10),
+				Check:  resource.TestCheckResourceAttr(fullName, "weight", "10"),
+			},
+			{
+				ResourceName: fullName,
+				ImportState:  true,
+				ImportStateIdFunc: unitImportID(fullName, func(s *terraform.InstanceState) string {
+					return fmt.Sprintf("%d:%d:%s:%s", mockProjectID, mockRegionID, s.ID, poolID)
+				}),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "project_name", "region_name"},
+			},
+		},
+	})
+}
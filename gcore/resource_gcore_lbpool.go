@@ -27,10 +27,12 @@ func resourceLBPool() *schema.Resource {
 		ReadContext:   resourceLBPoolRead,
 		UpdateContext: resourceLBPoolUpdate,
 		DeleteContext: resourceLBPoolDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent load balancer listener pool. A pool is a list of virtual machines to which the listener will redirect incoming traffic",
 		Timeouts: &schema.ResourceTimeout{
-			Create: schema.DefaultTimeout(5 * time.Minute),
-			Delete: schema.DefaultTimeout(5 * time.Minute),
+			Create: schema.DefaultTimeout(time.Duration(LBPoolsCreateTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(LBPoolsCreateTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(LBPoolsCreateTimeout) * time.Second),
 		},
 
 		Importer: &schema.ResourceImporter{
@@ -49,40 +51,30 @@ func resourceLBPool() *schema.Resource {
 		},
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -224,9 +216,8 @@ func resourceLBPoolCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Println("[DEBUG] Start LBPool creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LBPoolsPoint, versionPointV1)
+	client, err := CreateClient(config, d, LBPoolsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -249,7 +240,7 @@ func resourceLBPoolCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	}
 
 	taskID := results.Tasks[0]
-	lbPoolID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, LBPoolsCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	lbPoolID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -276,9 +267,8 @@ func resourceLBPoolRead(ctx context.Context, d *schema.ResourceData, m interface
 	log.Println("[DEBUG] Start LBPool reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LBPoolsPoint, versionPointV1)
+	client, err := CreateClient(config, d, LBPoolsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -342,9 +332,8 @@ func resourceLBPoolRead(ctx context.Context, d *schema.ResourceData, m interface
 func resourceLBPoolUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start LBPool updating")
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LBPoolsPoint, versionPointV1)
+	client, err := CreateClient(config, d, LBPoolsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -378,7 +367,7 @@ func resourceLBPoolUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, LBPoolsCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
 		_, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -399,9 +388,8 @@ func resourceLBPoolDelete(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Println("[DEBUG] Start LBPool deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LBPoolsPoint, versionPointV1)
+	client, err := CreateClient(config, d, LBPoolsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -417,7 +405,7 @@ func resourceLBPoolDelete(ctx context.Context, d *schema.ResourceData, m interfa
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, LBPoolsCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := lbpools.Get(client, id).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete LBPool with ID: %s", id)
@@ -119,3 +119,73 @@ func testAccLBPoolDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitLBPool(t *testing.T) {
+	api := newMockAPI(t)
+	lbID := api.cloud.Seed("loadbalancers", map[string]interface{}{"name": "lb"})
+	listenerID := api.cloud.Seed("lblisteners", map[string]interface{}{
+		"name":            "listener",
+		"loadbalancer_id": lbID,
+		"protocol":        "HTTP",
+		"protocol_port":   80,
+	})
+	fullName := "gcore_lbpool.test"
+
+	template := func(name string, delay int) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_lbpool" "test" {
+  %s
+  name            = "%s"
+  protocol        = "HTTP"
+  lb_algorithm    = "LEAST_CONNECTIONS"
+  loadbalancer_id = "%s"
+  listener_id     = "%s"
+
+  health_monitor {
+    type        = "PING"
+    delay       = %d
+    max_retries = 5
+    timeout     = 5
+  }
+
+  session_persistence {
+    type        = "APP_COOKIE"
+    cookie_name = "test_new_cookie"
+  }
+}
+`, mockCloudScope, name, lbID, listenerID, delay)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_lbpool", "lbpools"),
+		Steps: []resource.TestStep{
+			{
+				Config: template("pool", 10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "pool"),
+					resource.TestCheckResourceAttr(fullName, "lb_algorithm", "LEAST_CONNECTIONS"),
+					resource.TestCheckResourceAttr(fullName, "health_monitor.0.type", "PING"),
+					resource.TestCheckResourceAttr(fullName, "health_monitor.0.delay", "10"),
+					resource.TestCheckResourceAttrSet(fullName, "health_monitor.0.id"),
+					resource.TestCheckResourceAttr(fullName, "session_persistence.0.type", "APP_COOKIE"),
+					resource.TestCheckResourceAttr(fullName, "session_persistence.0.cookie_name", "test_new_cookie"),
+				),
+			},
+			{
+				Config: template("pool-renamed", 20),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "pool-renamed"),
+					resource.TestCheckResourceAttr(fullName, "health_monitor.0.delay", "20"),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "project_name", "region_name"},
+			},
+		},
+	})
+}
@@ -30,6 +30,7 @@ func resourceLifecyclePolicy() *schema.Resource {
 		ReadContext:   resourceLifecyclePolicyRead,
 		UpdateContext: resourceLifecyclePolicyUpdate,
 		DeleteContext: resourceLifecyclePolicyDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent lifecycle policy. Use to periodically take snapshots",
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -50,24 +51,26 @@ func resourceLifecyclePolicy() *schema.Resource {
 			"project_id": {
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
 				ForceNew: true,
 			},
 			"region_id": {
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
 				ForceNew: true,
 			},
 			"project_name": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ForceNew:     true,
-				ExactlyOneOf: []string{"project_id", "project_name"},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ForceNew:     true,
-				ExactlyOneOf: []string{"region_id", "region_name"},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": {
 				Type:         schema.TypeString,
@@ -265,7 +268,7 @@ func resourceLifecyclePolicy() *schema.Resource {
 }
 
 func resourceLifecyclePolicyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client, err := CreateClient(m.(*Config).Provider, d, lifecyclePolicyPoint, versionPointV1)
+	client, err := CreateClient(m.(*Config), d, lifecyclePolicyPoint, versionPointV1)
 	if err != nil {
 		return diag.Errorf("Error creating client: %s", err)
 	}
@@ -285,7 +288,7 @@ func resourceLifecyclePolicyCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceLifecyclePolicyRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client, err := CreateClient(m.(*Config).Provider, d, lifecyclePolicyPoint, versionPointV1)
+	client, err := CreateClient(m.(*Config), d, lifecyclePolicyPoint, versionPointV1)
 	if err != nil {
 		return diag.Errorf("Error creating client: %s", err)
 	}
@@ -317,7 +320,7 @@ func resourceLifecyclePolicyRead(_ context.Context, d *schema.ResourceData, m in
 }
 
 func resourceLifecyclePolicyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client, err := CreateClient(m.(*Config).Provider, d, lifecyclePolicyPoint, versionPointV1)
+	client, err := CreateClient(m.(*Config), d, lifecyclePolicyPoint, versionPointV1)
 	if err != nil {
 		return diag.Errorf("Error creating client: %s", err)
 	}
@@ -350,7 +353,7 @@ func resourceLifecyclePolicyUpdate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceLifecyclePolicyDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client, err := CreateClient(m.(*Config).Provider, d, lifecyclePolicyPoint, versionPointV1)
+	client, err := CreateClient(m.(*Config), d, lifecyclePolicyPoint, versionPointV1)
 	if err != nil {
 		return diag.Errorf("Error creating client: %s", err)
 	}
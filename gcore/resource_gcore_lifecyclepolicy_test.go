@@ -232,3 +232,83 @@ func testAccLifecyclePolicyDestroy(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestUnitLifecyclePolicy(t *testing.T) {
+	api := newMockAPI(t)
+	volume := func(name string) string {
+		return api.cloud.Seed("volumes", map[string]interface{}{"name": name, "size": 1})
+	}
+	firstID, secondID := volume("first"), volume("second")
+	fullName := "gcore_lifecyclepolicy.test"
+
+	template := func(name, volumeID string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_lifecyclepolicy" "test" {
+  %s
+  name = "%s"
+
+  volume {
+    id = "%s"
+  }
+
+  schedule {
+    max_quantity = 4
+    interval {
+      days = 1
+    }
+    retention_time {
+      weeks = 2
+    }
+  }
+}
+`, mockCloudScope, name, volumeID)
+	}
+	volumes := func(want string) resource.TestCheckFunc {
+		return unitCheckState(fullName, func(s *terraform.InstanceState) error {
+			got := mockList(api.cloud.Get("lifecycle_policy", s.ID)["volumes"])
+			if len(got) != 1 || mockMap(got[0])["volume_id"] != want {
+				return fmt.Errorf("expected volume %s in the policy, got %v", want, got)
+			}
+			return nil
+		})
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_lifecyclepolicy", "lifecycle_policy"),
+		Steps: []resource.TestStep{
+			{
+				Config: template("policy", firstID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "policy"),
+					resource.TestCheckResourceAttr(fullName, "status", "active"),
+					resource.TestCheckResourceAttr(fullName, "action", "volume_snapshot"),
+					resource.TestCheckResourceAttr(fullName, "volume.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "schedule.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "schedule.0.type", "interval"),
+					resource.TestCheckResourceAttr(fullName, "schedule.0.max_quantity", "4"),
+					resource.TestCheckResourceAttr(fullName, "schedule.0.interval.0.days", "1"),
+					resource.TestCheckResourceAttr(fullName, "schedule.0.retention_time.0.weeks", "2"),
+					resource.TestCheckResourceAttr(fullName, "schedule.0.resource_name_template", "reserve snap of the volume {volume_id}"),
+					resource.TestCheckResourceAttrSet(fullName, "schedule.0.id"),
+					volumes(firstID),
+				),
+			},
+			{
+				Config: template("policy-renamed", secondID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "policy-renamed"),
+					resource.TestCheckResourceAttr(fullName, "volume.#", "1"),
+					volumes(secondID),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"project_name", "region_name"},
+			},
+		},
+	})
+}
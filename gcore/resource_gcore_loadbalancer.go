@@ -30,10 +30,12 @@ func resourceLoadBalancer() *schema.Resource {
 		ReadContext:        resourceLoadBalancerRead,
 		UpdateContext:      resourceLoadBalancerUpdate,
 		DeleteContext:      resourceLoadBalancerDelete,
+		CustomizeDiff:      cloudScopeDiff,
 		Description:        "Represent load balancer",
 		Timeouts: &schema.ResourceTimeout{
-			Create: schema.DefaultTimeout(5 * time.Minute),
-			Delete: schema.DefaultTimeout(5 * time.Minute),
+			Create: schema.DefaultTimeout(time.Duration(LoadBalancerCreateTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(LBListenerCreateTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(LoadBalancerCreateTimeout) * time.Second),
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
@@ -47,9 +49,8 @@ func resourceLoadBalancer() *schema.Resource {
 				d.SetId(lbID)
 
 				config := m.(*Config)
-				provider := config.Provider
 
-				listenersClient, err := CreateClient(provider, d, LBListenersPoint, versionPointV1)
+				listenersClient, err := CreateClient(config, d, LBListenersPoint, versionPointV1)
 				if err != nil {
 					return nil, err
 				}
@@ -70,40 +71,30 @@ func resourceLoadBalancer() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -169,8 +160,11 @@ func resourceLoadBalancer() *schema.Resource {
 							Required: true,
 						},
 						"private_key": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							Sensitive:        true,
+							DiffSuppressFunc: suppressSensitiveHashDiff,
+							Description:      "Only its hash is kept in the state when the provider sets hash_sensitive_values",
 						},
 						"insert_x_forwarded": &schema.Schema{
 							Type:     schema.TypeBool,
@@ -232,9 +226,8 @@ func resourceLoadBalancerRead(ctx context.Context, d *schema.ResourceData, m int
 	log.Println("[DEBUG] Start LoadBalancer reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LoadBalancersPoint, versionPointV1)
+	client, err := CreateClient(config, d, LoadBalancersPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -255,7 +248,7 @@ func resourceLoadBalancerRead(ctx context.Context, d *schema.ResourceData, m int
 	fields := []string{"vip_network_id", "vip_subnet_id"}
 	revertState(d, &fields)
 
-	listenersClient, err := CreateClient(provider, d, LBListenersPoint, versionPointV1)
+	listenersClient, err := CreateClient(config, d, LBListenersPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -278,10 +271,18 @@ func resourceLoadBalancerRead(ctx context.Context, d *schema.ResourceData, m int
 		}
 		port, _ := currentL["protocol_port"].(int)
 		if (listener.ProtocolPort == port && listener.Protocol.String() == currentL["protocol"]) || len(cls) == 0 {
-			currentL = extractListenerIntoMap(listener)
+			l := extractListenerIntoMap(listener)
+			// The API doesn't return the certificates and the key.
+			for _, field := range []string{"certificate", "certificate_chain", "private_key"} {
+				l[field] = currentL[field]
+			}
+			currentL = l
 			break
 		}
 	}
+	if key, ok := currentL["private_key"].(string); ok {
+		currentL["private_key"] = sensitiveStateValue(config, key)
+	}
 	if err := d.Set("listener", []interface{}{currentL}); err != nil {
 		diag.FromErr(err)
 	}
@@ -316,9 +317,8 @@ func resourceLoadBalancerRead(ctx context.Context, d *schema.ResourceData, m int
 func resourceLoadBalancerUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start LoadBalancer updating")
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LoadBalancersPoint, versionPointV1)
+	client, err := CreateClient(config, d, LoadBalancersPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -336,7 +336,7 @@ func resourceLoadBalancerUpdate(ctx context.Context, d *schema.ResourceData, m i
 	}
 
 	if d.HasChange("listener") {
-		client, err := CreateClient(provider, d, LBListenersPoint, versionPointV1)
+		client, err := CreateClient(config, d, LBListenersPoint, versionPointV1)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -356,7 +356,7 @@ func resourceLoadBalancerUpdate(ctx context.Context, d *schema.ResourceData, m i
 			}
 
 			taskID := results.Tasks[0]
-			_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, LBListenerCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+			_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
 				_, err := listeners.Get(client, listenerID).Extract()
 				if err == nil {
 					return nil, fmt.Errorf("cannot delete LBListener with ID: %s", listenerID)
@@ -395,7 +395,7 @@ func resourceLoadBalancerUpdate(ctx context.Context, d *schema.ResourceData, m i
 			}
 
 			taskID = results.Tasks[0]
-			_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, LBListenerCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+			_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutUpdate), func(task tasks.TaskID) (interface{}, error) {
 				taskInfo, err := tasks.Get(client, string(task)).Extract()
 				if err != nil {
 					return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -448,9 +448,8 @@ func resourceLoadBalancerDelete(ctx context.Context, d *schema.ResourceData, m i
 	log.Println("[DEBUG] Start LoadBalancer deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, LoadBalancersPoint, versionPointV1)
+	client, err := CreateClient(config, d, LoadBalancersPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -462,7 +461,7 @@ func resourceLoadBalancerDelete(ctx context.Context, d *schema.ResourceData, m i
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, LoadBalancerCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := loadbalancers.Get(client, id).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete loadbalancer with ID: %s", id)
@@ -4,7 +4,9 @@
 package gcore
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/loadbalancers"
@@ -96,3 +98,88 @@ func testAccLoadBalancerDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitLoadBalancer(t *testing.T) {
+	api := newMockAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_loadbalancer" "test" {
+  %s
+  name = "lb"
+
+  listener {
+    name          = "http"
+    protocol      = "HTTP"
+    protocol_port = 80
+  }
+}
+`, mockCloudScope),
+				ExpectError: regexp.MustCompile("use gcore_loadbalancerv2 resource instead"),
+			},
+		},
+	})
+
+	// The balancers created before can still be read, changed and deleted.
+	lbID := api.cloud.Seed("loadbalancers", map[string]interface{}{
+		"name": "lb",
+		"listeners": []interface{}{
+			map[string]interface{}{"name": "http", "protocol": "HTTP", "protocol_port": 80},
+		},
+	})
+	config := unitProviderConfig(t, api, nil)
+	ctx := context.Background()
+	r := Provider().ResourcesMap["gcore_loadbalancer"]
+	d := r.Data(nil)
+	d.SetId(lbID)
+	d.Set("project_id", mockProjectID)
+	d.Set("region_id", mockRegionID)
+	if diags := r.ReadContext(ctx, d, config); diags.HasError() {
+		t.Fatalf("read: %s", unitDiagsError(diags))
+	}
+	listenerID := d.Get("listener.0.id").(string)
+	if d.Get("name") != "lb" || listenerID == "" || d.Get("listener.0.protocol_port") != 80 {
+		t.Fatalf("unexpected state: name %v, listener %v", d.Get("name"), d.Get("listener"))
+	}
+
+	// A changed port recreates the listener.
+	state := d.State()
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"project_id": mockProjectID,
+		"region_id":  mockRegionID,
+		"name":       "lb-renamed",
+		"flavor":     "lb1-1-2",
+		"listener": []interface{}{
+			map[string]interface{}{"name": "http", "protocol": "HTTP", "protocol_port": 8080},
+		},
+	}), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, diags := r.Apply(ctx, state, diff, config)
+	if diags.HasError() {
+		t.Fatalf("update: %s", unitDiagsError(diags))
+	}
+	lb := api.cloud.Get("loadbalancers", lbID)
+	if lb["name"] != "lb-renamed" {
+		t.Fatalf("expected the balancer renamed, got %v", lb["name"])
+	}
+	listeners := mockList(lb["listeners"])
+	if len(listeners) != 1 || mockMap(listeners[0])["id"] == listenerID {
+		t.Fatalf("expected the listener recreated, got %v", listeners)
+	}
+	if got := mockInt(api.cloud.Get("lblisteners", mockString(mockMap(listeners[0])["id"]))["protocol_port"]); got != 8080 {
+		t.Fatalf("expected the listener on port 8080, got %d", got)
+	}
+
+	d = r.Data(state)
+	if diags := r.DeleteContext(ctx, d, config); diags.HasError() {
+		t.Fatalf("delete: %s", unitDiagsError(diags))
+	}
+	if api.cloud.Get("loadbalancers", lbID) != nil {
+		t.Fatalf("balancer %s still exists", lbID)
+	}
+}
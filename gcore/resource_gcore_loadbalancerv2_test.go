@@ -0,0 +1,59 @@
+//go:build cloud
+// +build cloud
+
+package gcore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitLoadBalancerV2(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_loadbalancerv2.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_loadbalancerv2", "loadbalancers"),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_loadbalancerv2" "test" {
+  %s
+  name   = "test-lb"
+  flavor = "lb1-1-2"
+}
+`, mockCloudScope),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "test-lb"),
+					resource.TestCheckResourceAttrSet(fullName, "vip_address"),
+				),
+			},
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_loadbalancerv2" "test" {
+  %s
+  name         = "test-lb-renamed"
+  flavor       = "lb1-1-2"
+  metadata_map = {
+    env = "test"
+  }
+}
+`, mockCloudScope),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "test-lb-renamed"),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.env", "test"),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "flavor", "project_name", "region_name"},
+			},
+		},
+	})
+}
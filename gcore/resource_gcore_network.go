@@ -26,7 +26,13 @@ func resourceNetwork() *schema.Resource {
 		ReadContext:   resourceNetworkRead,
 		UpdateContext: resourceNetworkUpdate,
 		DeleteContext: resourceNetworkDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent network. A network is a software-defined network in a cloud computing infrastructure",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Duration(networkCreatingTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(networkCreatingTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(networkDeleting) * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, NetworkID, err := ImportStringParser(d.Id())
@@ -44,36 +50,26 @@ func resourceNetwork() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -135,9 +131,8 @@ func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interf
 	log.Println("[DEBUG] Start Network creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, networksPoint, versionPointV1)
+	client, err := CreateClient(config, d, networksPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -165,7 +160,7 @@ func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interf
 
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	networkID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, networkCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
+	networkID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -194,11 +189,10 @@ func resourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interfac
 	log.Printf("[DEBUG] Start network reading%s", d.State())
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	networkID := d.Id()
 	log.Printf("[DEBUG] Network id = %s", networkID)
 
-	client, err := CreateClient(provider, d, networksPoint, versionPointV1)
+	client, err := CreateClient(config, d, networksPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -249,8 +243,7 @@ func resourceNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interf
 	networkID := d.Id()
 	log.Printf("[DEBUG] Volume id = %s", networkID)
 	config := m.(*Config)
-	provider := config.Provider
-	client, err := CreateClient(provider, d, networksPoint, versionPointV1)
+	client, err := CreateClient(config, d, networksPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -285,11 +278,10 @@ func resourceNetworkDelete(ctx context.Context, d *schema.ResourceData, m interf
 	log.Println("[DEBUG] Start network deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	networkID := d.Id()
 	log.Printf("[DEBUG] Network id = %s", networkID)
 
-	client, err := CreateClient(provider, d, networksPoint, versionPointV1)
+	client, err := CreateClient(config, d, networksPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -300,7 +292,7 @@ func resourceNetworkDelete(ctx context.Context, d *schema.ResourceData, m interf
 	}
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, networkDeleting, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := networks.Get(client, networkID).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete network with ID: %s", networkID)
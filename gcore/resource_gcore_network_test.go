@@ -129,3 +129,53 @@ func testAccNetworkDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitNetwork(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_network.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_network", "networks"),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_network" "test" {
+  %s
+  name = "network"
+  type = "vxlan"
+}
+`, mockCloudScope),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "network"),
+					resource.TestCheckResourceAttr(fullName, "mtu", "1450"),
+					resource.TestCheckResourceAttr(fullName, "type", "vxlan"),
+					resource.TestCheckResourceAttr(fullName, "create_router", "true"),
+				),
+			},
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_network" "test" {
+  %s
+  name         = "network-renamed"
+  type         = "vxlan"
+  metadata_map = {
+    owner = "unit"
+  }
+}
+`, mockCloudScope),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "network-renamed"),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.owner", "unit"),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "create_router", "project_name", "region_name"},
+			},
+		},
+	})
+}
@@ -28,7 +28,13 @@ func resourceReservedFixedIP() *schema.Resource {
 		ReadContext:   resourceReservedFixedIPRead,
 		UpdateContext: resourceReservedFixedIPUpdate,
 		DeleteContext: resourceReservedFixedIPDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent reserved ips",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Duration(ReservedFixedIPCreateTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(ReservedFixedIPCreateTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(ReservedFixedIPCreateTimeout) * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, ipID, err := ImportStringParser(d.Id())
@@ -46,40 +52,30 @@ func resourceReservedFixedIP() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"type": &schema.Schema{
 				Type:        schema.TypeString,
@@ -165,9 +161,8 @@ func resourceReservedFixedIPCreate(ctx context.Context, d *schema.ResourceData,
 	log.Println("[DEBUG] Start ReservedFixedIP creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, reservedFixedIPsPoint, versionPointV1)
+	client, err := CreateClient(config, d, reservedFixedIPsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -212,7 +207,7 @@ func resourceReservedFixedIPCreate(ctx context.Context, d *schema.ResourceData,
 	}
 
 	taskID := results.Tasks[0]
-	reservedFixedIPID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, ReservedFixedIPCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	reservedFixedIPID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -240,9 +235,8 @@ func resourceReservedFixedIPRead(ctx context.Context, d *schema.ResourceData, m
 	log.Println("[DEBUG] Start ReservedFixedIP reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, reservedFixedIPsPoint, versionPointV1)
+	client, err := CreateClient(config, d, reservedFixedIPsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -290,9 +284,8 @@ func resourceReservedFixedIPRead(ctx context.Context, d *schema.ResourceData, m
 func resourceReservedFixedIPUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start ReservedFixedIP updating")
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, reservedFixedIPsPoint, versionPointV1)
+	client, err := CreateClient(config, d, reservedFixedIPsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -317,7 +310,7 @@ func resourceReservedFixedIPUpdate(ctx context.Context, d *schema.ResourceData,
 			}
 		}
 
-		clientPort, err := CreateClient(provider, d, portsPoint, versionPointV1)
+		clientPort, err := CreateClient(config, d, portsPoint, versionPointV1)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -337,9 +330,8 @@ func resourceReservedFixedIPDelete(ctx context.Context, d *schema.ResourceData,
 	log.Println("[DEBUG] Start ReservedFixedIP deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, reservedFixedIPsPoint, versionPointV1)
+	client, err := CreateClient(config, d, reservedFixedIPsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -364,7 +356,7 @@ func resourceReservedFixedIPDelete(ctx context.Context, d *schema.ResourceData,
 	}
 
 	taskID := results.Tasks[0]
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, ReservedFixedIPCreateTimeout, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := reservedfixedips.Get(client, id).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete reserved fixed ip with ID: %s", id)
@@ -85,3 +85,67 @@ func testAccReservedFixedIPDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitReservedFixedIP(t *testing.T) {
+	api := newMockAPI(t)
+	networkID := api.cloud.Seed("networks", map[string]interface{}{"name": "network"})
+	subnetID := api.cloud.Seed("subnets", map[string]interface{}{
+		"name":       "subnet",
+		"cidr":       "192.168.10.0/24",
+		"network_id": networkID,
+	})
+	fullName := "gcore_reservedfixedip.test"
+
+	template := func(vip bool) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_reservedfixedip" "test" {
+  %s
+  type      = "subnet"
+  subnet_id = "%s"
+  is_vip    = %t
+}
+`, mockCloudScope, subnetID, vip)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_reservedfixedip", "reserved_fixed_ips"),
+		Steps: []resource.TestStep{
+			{
+				Config: template(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "type", "subnet"),
+					resource.TestCheckResourceAttr(fullName, "subnet_id", subnetID),
+					resource.TestCheckResourceAttr(fullName, "network_id", networkID),
+					resource.TestCheckResourceAttr(fullName, "fixed_ip_address", "192.168.10.2"),
+					resource.TestCheckResourceAttrSet(fullName, "port_id"),
+					resource.TestCheckResourceAttr(fullName, "is_vip", "false"),
+				),
+			},
+			{
+				Config: template(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "is_vip", "true"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if ip := api.cloud.Get("reserved_fixed_ips", s.ID); !mockBool(ip["is_vip"]) {
+							return fmt.Errorf("reserved fixed ip %s isn't a vip", s.ID)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				// A VIP can't be deleted.
+				Config: template(false),
+				Check:  resource.TestCheckResourceAttr(fullName, "is_vip", "false"),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "project_name", "region_name", "type"},
+			},
+		},
+	})
+}
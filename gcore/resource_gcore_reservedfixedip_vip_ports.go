@@ -0,0 +1,273 @@
+package gcore
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/reservedfixedip/v1/reservedfixedips"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceReservedFixedIPVIPPorts() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceReservedFixedIPVIPPortsCreate,
+		ReadContext:   resourceReservedFixedIPVIPPortsRead,
+		UpdateContext: resourceReservedFixedIPVIPPortsUpdate,
+		DeleteContext: resourceReservedFixedIPVIPPortsDelete,
+		CustomizeDiff: cloudScopeDiff,
+		Description:   "Represent the instance ports sharing a reserved fixed ip with is_vip, e.g. the ports of a keepalived pair",
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				projectID, regionID, ipID, err := ImportStringParser(d.Id())
+
+				if err != nil {
+					return nil, err
+				}
+				d.Set("project_id", projectID)
+				d.Set("region_id", regionID)
+				d.Set("reserved_fixed_ip_id", ipID)
+				d.SetId(ipID)
+
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
+			},
+			"region_id": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
+			},
+			"project_name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
+			},
+			"region_name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
+			},
+			"reserved_fixed_ip_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "port_id of the gcore_reservedfixedip, is_vip of it must be true",
+			},
+			"port_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Required:    true,
+				Description: "IDs of the instance ports sharing the VIP, the ports must be in the network of the reserved fixed ip",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"available_ports": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Instance ports which can share the VIP",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"port_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"instance_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"instance_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"network_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ip_addresses": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceReservedFixedIPVIPPortsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start ReservedFixedIP VIP ports creating")
+	config := m.(*Config)
+
+	client, err := CreateClient(config, d, reservedFixedIPsPoint, versionPointV1)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	id := d.Get("reserved_fixed_ip_id").(string)
+	if err := replaceReservedFixedIPVIPPorts(client, id, d.Get("port_ids").(*schema.Set)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(id)
+
+	resourceReservedFixedIPVIPPortsRead(ctx, d, m)
+
+	log.Printf("[DEBUG] Finish ReservedFixedIP VIP ports creating (%s)", id)
+	return nil
+}
+
+func resourceReservedFixedIPVIPPortsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start ReservedFixedIP VIP ports reading")
+	var diags diag.Diagnostics
+	config := m.(*Config)
+
+	client, err := CreateClient(config, d, reservedFixedIPsPoint, versionPointV1)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	reservedFixedIP, err := reservedfixedips.Get(client, d.Id()).Extract()
+	if err != nil {
+		switch err.(type) {
+		case gcorecloud.ErrDefault404:
+			log.Printf("[WARN] Removing reserved fixed ip %s VIP ports because reserved fixed ip doesn't exist anymore", d.Id())
+			d.SetId("")
+			return nil
+		default:
+			return diag.FromErr(err)
+		}
+	}
+
+	d.Set("project_id", reservedFixedIP.ProjectID)
+	d.Set("region_id", reservedFixedIP.RegionID)
+	d.Set("reserved_fixed_ip_id", reservedFixedIP.PortID)
+
+	connected, err := reservedfixedips.ListAllConnectedDevice(client, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	portIDs := make([]string, len(connected))
+	for i, device := range connected {
+		portIDs[i] = device.PortID
+	}
+	if err := d.Set("port_ids", portIDs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	available, err := reservedfixedips.ListAllAvailableDevice(client, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	availablePorts := make([]map[string]interface{}, len(available))
+	for i, device := range available {
+		ipAddresses := make([]string, len(device.IPAssignments))
+		for j, ip := range device.IPAssignments {
+			ipAddresses[j] = ip.IPAddress.String()
+		}
+		availablePorts[i] = map[string]interface{}{
+			"port_id":       device.PortID,
+			"instance_id":   device.InstanceID,
+			"instance_name": device.InstanceName,
+			"network_id":    device.Network.ID,
+			"ip_addresses":  ipAddresses,
+		}
+	}
+	if err := d.Set("available_ports", availablePorts); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Println("[DEBUG] Finish ReservedFixedIP VIP ports reading")
+	return diags
+}
+
+func resourceReservedFixedIPVIPPortsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start ReservedFixedIP VIP ports updating")
+	config := m.(*Config)
+
+	client, err := CreateClient(config, d, reservedFixedIPsPoint, versionPointV1)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("port_ids") {
+		if err := replaceReservedFixedIPVIPPorts(client, d.Id(), d.Get("port_ids").(*schema.Set)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	log.Println("[DEBUG] Finish ReservedFixedIP VIP ports updating")
+	return resourceReservedFixedIPVIPPortsRead(ctx, d, m)
+}
+
+func resourceReservedFixedIPVIPPortsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start ReservedFixedIP VIP ports deleting")
+	var diags diag.Diagnostics
+	config := m.(*Config)
+
+	client, err := CreateClient(config, d, reservedFixedIPsPoint, versionPointV1)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	id := d.Id()
+	reservedFixedIP, err := reservedfixedips.Get(client, id).Extract()
+	if err != nil {
+		switch err.(type) {
+		case gcorecloud.ErrDefault404:
+			d.SetId("")
+			return diags
+		default:
+			return diag.FromErr(err)
+		}
+	}
+	// the ports stop sharing the address together with is_vip
+	if reservedFixedIP.IsVip {
+		opts := reservedfixedips.PortsToShareVIPOpts{PortIDs: []string{}}
+		if _, err := reservedfixedips.ReplacePortsToShareVIP(client, id, opts).Extract(); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+	log.Printf("[DEBUG] Finish of ReservedFixedIP VIP ports deleting")
+	return diags
+}
+
+// replaceReservedFixedIPVIPPorts makes the ports the only ones sharing the
+// reserved fixed ip, which must be a VIP.
+func replaceReservedFixedIPVIPPorts(client *gcorecloud.ServiceClient, id string, ports *schema.Set) error {
+	reservedFixedIP, err := reservedfixedips.Get(client, id).Extract()
+	if err != nil {
+		return err
+	}
+	if !reservedFixedIP.IsVip {
+		return fmt.Errorf("reserved fixed ip %s is not a VIP, set is_vip of it to true", id)
+	}
+
+	portIDs := make([]string, 0, ports.Len())
+	for _, portID := range ports.List() {
+		portIDs = append(portIDs, portID.(string))
+	}
+	opts := reservedfixedips.PortsToShareVIPOpts{PortIDs: portIDs}
+	if _, err := reservedfixedips.ReplacePortsToShareVIP(client, id, opts).Extract(); err != nil {
+		return fmt.Errorf("replace ports sharing reserved fixed ip %s: %w", id, err)
+	}
+	return nil
+}
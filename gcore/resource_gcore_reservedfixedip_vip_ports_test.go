@@ -0,0 +1,122 @@
+//go:build cloud
+// +build cloud
+
+package gcore
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestUnitReservedFixedIPVIPPorts(t *testing.T) {
+	api := newMockAPI(t)
+	networkID := api.cloud.Seed("networks", map[string]interface{}{"name": "network"})
+	subnetID := api.cloud.Seed("subnets", map[string]interface{}{
+		"name":       "subnet",
+		"cidr":       "192.168.10.0/24",
+		"network_id": networkID,
+	})
+	privateID := api.cloud.Seed("networks", map[string]interface{}{"name": "private"})
+	privateSubnetID := api.cloud.Seed("subnets", map[string]interface{}{
+		"name":       "private",
+		"cidr":       "10.0.0.0/24",
+		"network_id": privateID,
+	})
+	instance := func(name, networkID, subnetID string) string {
+		id := api.cloud.Seed("instances", map[string]interface{}{
+			"names":  []interface{}{name},
+			"flavor": "g1-standard-1-2",
+			"interfaces": []interface{}{
+				map[string]interface{}{"type": "subnet", "network_id": networkID, "subnet_id": subnetID},
+			},
+		})
+		return api.cloud.InstancePorts(id)[0]
+	}
+	masterPort := instance("master", networkID, subnetID)
+	backupPort := instance("backup", networkID, subnetID)
+	privatePort := instance("private", privateID, privateSubnetID)
+	vipID := api.cloud.Seed("reserved_fixed_ips", map[string]interface{}{
+		"type": "subnet", "subnet_id": subnetID, "is_vip": true,
+	})
+	fixedID := api.cloud.Seed("reserved_fixed_ips", map[string]interface{}{
+		"type": "subnet", "subnet_id": subnetID,
+	})
+	fullName := "gcore_reservedfixedip_vip_ports.test"
+
+	template := func(ipID string, ports ...string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_reservedfixedip_vip_ports" "test" {
+  %s
+  reserved_fixed_ip_id = "%s"
+  port_ids             = ["%s"]
+}
+`, mockCloudScope, ipID, strings.Join(ports, `", "`))
+	}
+	connected := func(ports ...string) resource.TestCheckFunc {
+		sort.Strings(ports)
+		return unitCheckState(fullName, func(s *terraform.InstanceState) error {
+			if got := api.cloud.ConnectedPorts(s.ID); strings.Join(got, ",") != strings.Join(ports, ",") {
+				return fmt.Errorf("expected connected ports %v, got %v", ports, got)
+			}
+			return nil
+		})
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			if ports := api.cloud.ConnectedPorts(vipID); len(ports) != 0 {
+				return fmt.Errorf("ports %v still share the vip", ports)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      template(fixedID, masterPort),
+				ExpectError: regexp.MustCompile("reserved fixed ip " + fixedID + " is not a VIP, set is_vip of it to true"),
+			},
+			{
+				Config:      template(vipID, privatePort),
+				ExpectError: regexp.MustCompile("port " + privatePort + " is not available for the vip"),
+			},
+			{
+				Config: template(vipID, masterPort),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "reserved_fixed_ip_id", vipID),
+					resource.TestCheckResourceAttr(fullName, "port_ids.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "available_ports.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "available_ports.0.network_id", networkID),
+					resource.TestCheckResourceAttr(fullName, "available_ports.0.ip_addresses.#", "1"),
+					connected(masterPort),
+				),
+			},
+			{
+				Config: template(vipID, masterPort, backupPort),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "port_ids.#", "2"),
+					connected(masterPort, backupPort),
+				),
+			},
+			{
+				Config: template(vipID, backupPort),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "port_ids.#", "1"),
+					connected(backupPort),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"project_name", "region_name"},
+			},
+		},
+	})
+}
@@ -24,7 +24,13 @@ func resourceRouter() *schema.Resource {
 		ReadContext:   resourceRouterRead,
 		UpdateContext: resourceRouterUpdate,
 		DeleteContext: resourceRouterDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent router. Router enables you to dynamically exchange routes between networks",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Duration(RouterCreatingTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(RouterCreatingTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(RouterDeleting) * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, routerID, err := ImportStringParser(d.Id())
@@ -42,36 +48,26 @@ func resourceRouter() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -185,9 +181,8 @@ func resourceRouterCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Println("[DEBUG] Start router creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, RouterPoint, versionPointV1)
+	client, err := CreateClient(config, d, RouterPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -230,7 +225,7 @@ func resourceRouterCreate(ctx context.Context, d *schema.ResourceData, m interfa
 
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	routerID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, RouterCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
+	routerID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -259,11 +254,10 @@ func resourceRouterRead(ctx context.Context, d *schema.ResourceData, m interface
 	log.Printf("[DEBUG] Start router reading%s", d.State())
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	routerID := d.Id()
 	log.Printf("[DEBUG] Router id = %s", routerID)
 
-	client, err := CreateClient(provider, d, RouterPoint, versionPointV1)
+	client, err := CreateClient(config, d, RouterPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -338,8 +332,7 @@ func resourceRouterUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 	routerID := d.Id()
 	log.Printf("[DEBUG] Router id = %s", routerID)
 	config := m.(*Config)
-	provider := config.Provider
-	client, err := CreateClient(provider, d, RouterPoint, versionPointV1)
+	client, err := CreateClient(config, d, RouterPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -428,11 +421,10 @@ func resourceRouterDelete(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Println("[DEBUG] Start router deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	routerID := d.Id()
 	log.Printf("[DEBUG] Router id = %s", routerID)
 
-	client, err := CreateClient(provider, d, RouterPoint, versionPointV1)
+	client, err := CreateClient(config, d, RouterPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -443,7 +435,7 @@ func resourceRouterDelete(ctx context.Context, d *schema.ResourceData, m interfa
 	}
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, RouterDeleting, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := routers.Get(client, routerID).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete router with ID: %s", routerID)
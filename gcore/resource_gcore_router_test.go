@@ -248,3 +248,95 @@ func testAccRouterDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitRouter(t *testing.T) {
+	api := newMockAPI(t)
+	networkID := api.cloud.Seed("networks", map[string]interface{}{"name": "network"})
+	subnet := func(name, cidr, gateway string) string {
+		return api.cloud.Seed("subnets", map[string]interface{}{
+			"name":       name,
+			"cidr":       cidr,
+			"network_id": networkID,
+			"gateway_ip": gateway,
+		})
+	}
+	firstID := subnet("first", "192.168.10.0/24", "192.168.10.1")
+	secondID := subnet("second", "192.168.20.0/24", "192.168.20.1")
+	fullName := "gcore_router.test"
+
+	template := func(name, subnetID, routes string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_router" "test" {
+  %s
+  name = "%s"
+
+  external_gateway_info {
+    type        = "default"
+    enable_snat = true
+  }
+
+  interfaces {
+    type      = "subnet"
+    subnet_id = "%s"
+  }
+%s
+}
+`, mockCloudScope, name, subnetID, routes)
+	}
+	attached := func(subnetID string) func(*terraform.InstanceState) error {
+		return func(s *terraform.InstanceState) error {
+			ifaces := mockList(api.cloud.Get("routers", s.ID)["interfaces"])
+			if len(ifaces) != 1 {
+				return fmt.Errorf("expected one router interface, got %v", ifaces)
+			}
+			ip := mockMap(mockList(mockMap(ifaces[0])["ip_assignments"])[0])
+			if ip["subnet_id"] != subnetID {
+				return fmt.Errorf("expected subnet %s attached, got %v", subnetID, ip["subnet_id"])
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_router", "routers"),
+		Steps: []resource.TestStep{
+			{
+				Config: template("router", firstID, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "router"),
+					resource.TestCheckResourceAttr(fullName, "external_gateway_info.0.type", "default"),
+					resource.TestCheckResourceAttr(fullName, "external_gateway_info.0.network_id", mockExternalNetworkID),
+					resource.TestCheckResourceAttr(fullName, "external_gateway_info.0.enable_snat", "true"),
+					resource.TestCheckResourceAttr(fullName, "external_gateway_info.0.external_fixed_ips.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "interfaces.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "routes.#", "0"),
+					unitCheckState(fullName, attached(firstID)),
+				),
+			},
+			{
+				Config: template("router-renamed", secondID, `
+  routes {
+    destination = "10.0.3.0/24"
+    nexthop     = "192.168.20.5"
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "router-renamed"),
+					resource.TestCheckResourceAttr(fullName, "interfaces.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "routes.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "routes.0.destination", "10.0.3.0/24"),
+					resource.TestCheckResourceAttr(fullName, "routes.0.nexthop", "192.168.20.5"),
+					unitCheckState(fullName, attached(secondID)),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "project_name", "region_name", "external_gateway_info.0.type"},
+			},
+		},
+	})
+}
@@ -2,16 +2,20 @@ package gcore
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"log"
 	"time"
 
 	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/listeners"
 	"github.com/G-Core/gcorelabscloud-go/gcore/secret/v1/secrets"
 	secretsV2 "github.com/G-Core/gcorelabscloud-go/gcore/secret/v2/secrets"
 	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -23,8 +27,15 @@ func resourceSecret() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceSecretCreate,
 		ReadContext:   resourceSecretRead,
+		UpdateContext: resourceSecretUpdate,
 		DeleteContext: resourceSecretDelete,
-		Description:   "Represent secret",
+		CustomizeDiff: customdiff.All(cloudScopeDiff, resourceSecretCustomizeDiff),
+		Description:   "Represent secret. A new certificate rotates it: the new secret replaces the old one in the load balancer listeners.",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Duration(SecretCreatingTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(SecretCreatingTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(SecretDeleting) * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, secretID, err := ImportStringParser(d.Id())
@@ -42,40 +53,30 @@ func resourceSecret() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -83,23 +84,43 @@ func resourceSecret() *schema.Resource {
 				ForceNew: true,
 			},
 			"private_key": &schema.Schema{
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "SSL private key in PEM format",
+				Type:             schema.TypeString,
+				Required:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressSensitiveHashDiff,
+				Description:      "SSL private key in PEM format. Only its hash is kept in the state when the provider sets hash_sensitive_values",
 			},
 			"certificate_chain": &schema.Schema{
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 				Description: "SSL certificate chain of intermediates and root certificates in PEM format",
 			},
 			"certificate": &schema.Schema{
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 				Description: "SSL certificate in PEM format",
 			},
+			"subject": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Subject of the certificate",
+			},
+			"issuer": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Issuer of the certificate",
+			},
+			"sans": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Subject alternative names of the certificate, DNS names, IP addresses, emails and URIs",
+			},
+			"not_after": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Datetime when the certificate expires. The format is RFC3339, e.g. 2025-12-28T19:14:44Z",
+			},
 			"algorithm": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -128,6 +149,7 @@ func resourceSecret() *schema.Resource {
 				Description: "Datetime when the secret will expire. The format is 2025-12-28T19:14:44",
 				Optional:    true,
 				Computed:    true,
+				ForceNew:    true,
 				StateFunc: func(val interface{}) string {
 					expTime, _ := time.Parse(gcorecloud.RFC3339NoZ, val.(string))
 					return expTime.Format(gcorecloud.RFC3339NoZ)
@@ -154,12 +176,28 @@ func resourceSecretCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Println("[DEBUG] Start Secret creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, secretPoint, versionPointV2)
+	secretID, err := createSecret(ctx, config, d, d.Timeout(schema.TimeoutCreate))
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	log.Printf("[DEBUG] Secret id (%s)", secretID)
+
+	d.SetId(secretID)
+
+	resourceSecretRead(ctx, d, m)
+
+	log.Printf("[DEBUG] Finish Secret creating (%s)", secretID)
+	return diags
+}
+
+// createSecret creates a secret from the arguments of the resource and
+// returns its ID.
+func createSecret(ctx context.Context, config *Config, d *schema.ResourceData, timeout time.Duration) (string, error) {
+	client, err := CreateClient(config, d, secretPoint, versionPointV2)
+	if err != nil {
+		return "", err
+	}
 
 	opts := secretsV2.CreateOpts{
 		Name: d.Get("name").(string),
@@ -172,24 +210,24 @@ func resourceSecretCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	if rawTime := d.Get("expiration").(string); rawTime != "" {
 		expiration, err := time.Parse(gcorecloud.RFC3339NoZ, rawTime)
 		if err != nil {
-			return diag.FromErr(err)
+			return "", err
 		}
 		opts.Expiration = &expiration
 	}
 
 	results, err := secretsV2.Create(client, opts).Extract()
 	if err != nil {
-		return diag.FromErr(err)
+		return "", err
 	}
 
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
 
-	clientV1, err := CreateClient(provider, d, secretPoint, versionPointV1)
+	clientV1, err := CreateClient(config, d, secretPoint, versionPointV1)
 	if err != nil {
-		return diag.FromErr(err)
+		return "", err
 	}
-	secretID, err := tasks.WaitTaskAndReturnResult(clientV1, taskID, true, SecretCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
+	secretID, err := waitTask(ctx, clientV1, taskID, timeout, func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(clientV1, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -202,27 +240,19 @@ func resourceSecretCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	},
 	)
 	if err != nil {
-		return diag.FromErr(err)
+		return "", err
 	}
-	log.Printf("[DEBUG] Secret id (%s)", secretID)
-
-	d.SetId(secretID.(string))
-
-	resourceSecretRead(ctx, d, m)
-
-	log.Printf("[DEBUG] Finish Secret creating (%s)", secretID)
-	return diags
+	return secretID.(string), nil
 }
 
 func resourceSecretRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start secret reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	secretID := d.Id()
 	log.Printf("[DEBUG] Secret id = %s", secretID)
 
-	client, err := CreateClient(provider, d, secretPoint, versionPointV1)
+	client, err := CreateClient(config, d, secretPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -241,42 +271,207 @@ func resourceSecretRead(ctx context.Context, d *schema.ResourceData, m interface
 	if err := d.Set("content_types", secret.ContentTypes); err != nil {
 		return diag.FromErr(err)
 	}
+	// The API doesn't return the certificate, its attributes come from the
+	// state.
+	if cert := d.Get("certificate").(string); cert != "" {
+		if info, err := parseSecretCertificate(cert); err == nil {
+			for field, v := range info {
+				d.Set(field, v)
+			}
+		}
+	}
+	hashSensitiveFields(d, config, "private_key")
 
 	log.Println("[DEBUG] Finish secret reading")
 	return diags
 }
 
+// resourceSecretUpdate rotates the secret: a new secret is created from the
+// changed certificate, the listeners using the old one switch to it, then the
+// old secret is deleted. The API doesn't change the payload of a secret.
+func resourceSecretUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start secret updating")
+	config := m.(*Config)
+	oldID := d.Id()
+
+	client, err := CreateClient(config, d, secretPoint, versionPointV1)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	lbClient, err := CreateClient(config, d, LBListenersPoint, versionPointV1)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	newID, err := createSecret(ctx, config, d, d.Timeout(schema.TimeoutUpdate))
+	if err != nil {
+		revertSecretPayload(d)
+		return diag.Errorf("cannot rotate secret %s: %s", oldID, err)
+	}
+	log.Printf("[DEBUG] Secret %s is rotated to %s", oldID, newID)
+
+	switched, err := switchListenersSecret(lbClient, oldID, newID)
+	if err != nil {
+		// The listeners go back to the old secret, which is kept.
+		if _, rerr := switchListenersSecret(lbClient, newID, oldID); rerr != nil {
+			log.Printf("[WARN] Cannot switch listeners back to secret %s: %s", oldID, rerr)
+		}
+		if derr := deleteSecret(ctx, client, newID, d.Timeout(schema.TimeoutUpdate)); derr != nil {
+			log.Printf("[WARN] Cannot delete secret %s: %s", newID, derr)
+		}
+		revertSecretPayload(d)
+		return diag.Errorf("cannot switch listeners from secret %s to %s: %s", oldID, newID, err)
+	}
+	log.Printf("[DEBUG] Listeners %v switched to secret %s", switched, newID)
+
+	d.SetId(newID)
+	if err := deleteSecret(ctx, client, oldID, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.Errorf("cannot delete secret %s replaced by %s: %s", oldID, newID, err)
+	}
+
+	log.Println("[DEBUG] Finish secret updating")
+	return resourceSecretRead(ctx, d, m)
+}
+
+// revertSecretPayload restores the payload of the state after a failed
+// rotation, so the next plan shows the change again.
+func revertSecretPayload(d *schema.ResourceData) {
+	for _, field := range []string{"certificate", "certificate_chain", "private_key"} {
+		old, _ := d.GetChange(field)
+		d.Set(field, old)
+	}
+}
+
+// switchListenersSecret replaces the secret in the secret_id and
+// sni_secret_id of the listeners of the project and region, and returns the
+// IDs of the changed listeners.
+func switchListenersSecret(client *gcorecloud.ServiceClient, oldID, newID string) ([]string, error) {
+	lbListeners, err := listeners.ListAll(client, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var switched []string
+	for _, l := range lbListeners {
+		opts := listeners.UpdateOpts{Name: l.Name}
+		changed := false
+		if l.SecretID != nil && *l.SecretID == oldID {
+			opts.SecretID = newID
+			changed = true
+		}
+		for _, id := range l.SNISecretID {
+			if id == oldID {
+				id = newID
+				changed = true
+			}
+			opts.SNISecretID = append(opts.SNISecretID, id)
+		}
+		if !changed {
+			continue
+		}
+		if _, err := listeners.Update(client, l.ID, opts).Extract(); err != nil {
+			return switched, fmt.Errorf("listener %s: %w", l.ID, err)
+		}
+		switched = append(switched, l.ID)
+	}
+	return switched, nil
+}
+
 func resourceSecretDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Println("[DEBUG] Start secret deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	secretID := d.Id()
 	log.Printf("[DEBUG] Secret id = %s", secretID)
 
-	client, err := CreateClient(provider, d, secretPoint, versionPointV1)
+	client, err := CreateClient(config, d, secretPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if err := deleteSecret(ctx, client, secretID, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	log.Printf("[DEBUG] Finish of secret deleting")
+	return diags
+}
+
+func deleteSecret(ctx context.Context, client *gcorecloud.ServiceClient, secretID string, timeout time.Duration) error {
 	results, err := secrets.Delete(client, secretID).Extract()
 	if err != nil {
-		return diag.FromErr(err)
+		if _, ok := err.(gcorecloud.ErrDefault409); ok {
+			return fmt.Errorf("secret %s is still in use, set create_before_destroy in the lifecycle of gcore_secret to replace a secret used by load balancer listeners: %w", secretID, err)
+		}
+		return err
 	}
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, SecretDeleting, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, timeout, func(task tasks.TaskID) (interface{}, error) {
 		_, err := secrets.Get(client, secretID).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete secret with ID: %s", secretID)
 		}
 		return nil, nil
 	})
+	return err
+}
+
+// resourceSecretCustomizeDiff plans the attributes of a changed certificate.
+func resourceSecretCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("certificate") {
+		return nil
+	}
+	if !d.NewValueKnown("certificate") {
+		for _, field := range []string{"subject", "issuer", "sans", "not_after"} {
+			if err := d.SetNewComputed(field); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	info, err := parseSecretCertificate(d.Get("certificate").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return fmt.Errorf("certificate: %w", err)
 	}
+	for field, v := range info {
+		if err := d.SetNew(field, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	d.SetId("")
-	log.Printf("[DEBUG] Finish of secret deleting")
-	return diags
+// parseSecretCertificate returns the computed attributes of the first
+// certificate of the PEM data.
+func parseSecretCertificate(data string) (map[string]interface{}, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	sans := make([]interface{}, 0)
+	for _, name := range cert.DNSNames {
+		sans = append(sans, name)
+	}
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		sans = append(sans, email)
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return map[string]interface{}{
+		"subject":   cert.Subject.String(),
+		"issuer":    cert.Issuer.String(),
+		"sans":      sans,
+		"not_after": cert.NotAfter.UTC().Format(time.RFC3339),
+	}, nil
 }
@@ -4,8 +4,17 @@
 package gcore
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/G-Core/gcorelabscloud-go/gcore/secret/v1/secrets"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -68,3 +77,100 @@ func testAccSecretDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+// unitCertificate returns a self-signed certificate for the name and its key
+// in PEM format.
+func unitCertificate(t *testing.T, name string, notAfter time.Time) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name, "www." + name},
+		IPAddresses:  []net.IP{net.ParseIP("192.0.2.1")},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestUnitSecretRotate(t *testing.T) {
+	api := newMockAPI(t)
+	firstCert, firstKey := unitCertificate(t, "example.com", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	secondCert, secondKey := unitCertificate(t, "example.com", time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC))
+	fullName := "gcore_secret.test"
+
+	template := func(cert, key string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_secret" "test" {
+  %s
+  name              = "secret"
+  certificate       = %q
+  certificate_chain = %q
+  private_key       = %q
+}
+`, mockCloudScope, cert, cert, key)
+	}
+
+	var firstID string
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_secret", "secrets"),
+		Steps: []resource.TestStep{
+			{
+				Config: template(firstCert, firstKey),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "subject", "CN=example.com"),
+					resource.TestCheckResourceAttr(fullName, "issuer", "CN=example.com"),
+					resource.TestCheckResourceAttr(fullName, "sans.#", "3"),
+					resource.TestCheckResourceAttr(fullName, "sans.0", "example.com"),
+					resource.TestCheckResourceAttr(fullName, "sans.1", "www.example.com"),
+					resource.TestCheckResourceAttr(fullName, "sans.2", "192.0.2.1"),
+					resource.TestCheckResourceAttr(fullName, "not_after", "2030-01-01T00:00:00Z"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						firstID = s.ID
+						return nil
+					}),
+				),
+			},
+			{
+				// A new certificate rotates the secret in place.
+				Config: template(secondCert, secondKey),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "not_after", "2031-01-01T00:00:00Z"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if s.ID == firstID {
+							return fmt.Errorf("expected a new secret")
+						}
+						if api.cloud.Get("secrets", firstID) != nil {
+							return fmt.Errorf("expected the old secret %s deleted", firstID)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:      fullName,
+				ImportState:       true,
+				ImportStateIdFunc: unitCloudImportID(fullName),
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"project_name", "region_name", "certificate", "certificate_chain", "private_key",
+					"subject", "issuer", "sans", "not_after",
+				},
+			},
+		},
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
 	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/securitygrouprules"
 	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/securitygroups"
 	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/types"
@@ -21,6 +22,10 @@ const (
 
 	minPort = 0
 	maxPort = 65535
+
+	securityGroupCreateTimeout = 300
+	securityGroupUpdateTimeout = 300
+	securityGroupDeleteTimeout = 300
 )
 
 func resourceSecurityGroup() *schema.Resource {
@@ -29,7 +34,13 @@ func resourceSecurityGroup() *schema.Resource {
 		ReadContext:   resourceSecurityGroupRead,
 		UpdateContext: resourceSecurityGroupUpdate,
 		DeleteContext: resourceSecurityGroupDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent SecurityGroups(Firewall)",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(securityGroupCreateTimeout * time.Second),
+			Update: schema.DefaultTimeout(securityGroupUpdateTimeout * time.Second),
+			Delete: schema.DefaultTimeout(securityGroupDeleteTimeout * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, sgID, err := ImportStringParser(d.Id())
@@ -47,36 +58,26 @@ func resourceSecurityGroup() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -116,7 +117,7 @@ func resourceSecurityGroup() *schema.Resource {
 			"security_group_rules": &schema.Schema{
 				Type:        schema.TypeSet,
 				Required:    true,
-				Description: "Firewall rules control what inbound(ingress) and outbound(egress) traffic is allowed to enter or leave a Instance. At least one 'egress' rule should be set",
+				Description: "Firewall rules control what inbound(ingress) and outbound(egress) traffic is allowed to enter or leave a Instance. At least one 'egress' rule should be set. Rules of gcore_securitygroup_rule resources aren't listed here",
 				Set:         secGroupUniqueID,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -215,9 +216,8 @@ func resourceSecurityGroupCreate(ctx context.Context, d *schema.ResourceData, m
 
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, securityGroupPoint, versionPointV1)
+	client, err := CreateClient(config, d, securityGroupPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -283,9 +283,8 @@ func resourceSecurityGroupRead(ctx context.Context, d *schema.ResourceData, m in
 	log.Println("[DEBUG] Start SecurityGroup reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, securityGroupPoint, versionPointV1)
+	client, err := CreateClient(config, d, securityGroupPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -305,6 +304,9 @@ func resourceSecurityGroupRead(ctx context.Context, d *schema.ResourceData, m in
 
 	if len(sg.Metadata) > 0 {
 		for _, metadataItem := range sg.Metadata {
+			if strings.HasPrefix(metadataItem.Key, securityGroupRuleMetadataPrefix) {
+				continue
+			}
 			if !metadataItem.ReadOnly {
 				metadataMap[metadataItem.Key] = metadataItem.Value
 			}
@@ -323,45 +325,15 @@ func resourceSecurityGroupRead(ctx context.Context, d *schema.ResourceData, m in
 		return diag.FromErr(err)
 	}
 
-	newSgRules := make([]interface{}, len(sg.SecurityGroupRules))
-	for i, sgr := range sg.SecurityGroupRules {
-		log.Printf("rules: %+v", sgr)
-		r := make(map[string]interface{})
-		r["id"] = sgr.ID
-		r["direction"] = sgr.Direction.String()
-
-		if sgr.EtherType != nil {
-			r["ethertype"] = sgr.EtherType.String()
-		}
-
-		r["protocol"] = types.ProtocolAny
-		if sgr.Protocol != nil {
-			r["protocol"] = sgr.Protocol.String()
+	// Rules of gcore_securitygroup_rule resources, marked in the metadata,
+	// are left out, any other rule is managed here.
+	owned := markedSecurityGroupRules(sg.Metadata)
+	newSgRules := make([]interface{}, 0, len(sg.SecurityGroupRules))
+	for _, sgr := range sg.SecurityGroupRules {
+		if owned[sgr.ID] {
+			continue
 		}
-
-		r["port_range_max"] = 0
-		if sgr.PortRangeMax != nil {
-			r["port_range_max"] = *sgr.PortRangeMax
-		}
-		r["port_range_min"] = 0
-		if sgr.PortRangeMin != nil {
-			r["port_range_min"] = *sgr.PortRangeMin
-		}
-
-		r["description"] = ""
-		if sgr.Description != nil {
-			r["description"] = *sgr.Description
-		}
-
-		r["remote_ip_prefix"] = ""
-		if sgr.RemoteIPPrefix != nil {
-			r["remote_ip_prefix"] = *sgr.RemoteIPPrefix
-		}
-
-		r["updated_at"] = sgr.UpdatedAt.String()
-		r["created_at"] = sgr.CreatedAt.String()
-
-		newSgRules[i] = r
+		newSgRules = append(newSgRules, securityGroupRuleMap(sgr))
 	}
 
 	if err := d.Set("security_group_rules", schema.NewSet(secGroupUniqueID, newSgRules)); err != nil {
@@ -388,13 +360,12 @@ func resourceSecurityGroupUpdate(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	config := m.(*Config)
-	provider := config.Provider
-	clientCreate, err := CreateClient(provider, d, securityGroupPoint, versionPointV1)
+	clientCreate, err := CreateClient(config, d, securityGroupPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	clientUpdateDelete, err := CreateClient(provider, d, securityGroupRulesPoint, versionPointV1)
+	clientUpdateDelete, err := CreateClient(config, d, securityGroupRulesPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -433,13 +404,13 @@ func resourceSecurityGroupUpdate(ctx context.Context, d *schema.ResourceData, m
 			rule := r.(map[string]interface{})
 			rid := rule["id"].(string)
 			if !newRules.Contains(r) && !changedRule[rid] {
-				// todo patch lib, should be task instead of DeleteResult
 				err := securitygrouprules.Delete(clientUpdateDelete, rid).ExtractErr()
 				if err != nil {
 					return diag.FromErr(err)
 				}
-				// todo remove after patch lib
-				time.Sleep(time.Second * 2)
+				if err := waitSecurityGroupRuleDeleted(ctx, clientCreate, gid, rid, d.Timeout(schema.TimeoutUpdate)); err != nil {
+					return diag.FromErr(err)
+				}
 				continue
 			}
 		}
@@ -448,7 +419,23 @@ func resourceSecurityGroupUpdate(ctx context.Context, d *schema.ResourceData, m
 	if d.HasChange("metadata_map") {
 		_, nmd := d.GetChange("metadata_map")
 
-		err := securitygroups.MetadataReplace(clientCreate, gid, nmd.(map[string]interface{})).Err
+		// the marks of the rules of gcore_securitygroup_rule resources are
+		// kept by the replace
+		current, err := securitygroups.MetadataListAll(clientCreate, gid)
+		if err != nil {
+			return diag.Errorf("cannot list metadata. Error: %s", err)
+		}
+		md := make(map[string]interface{})
+		for k, v := range nmd.(map[string]interface{}) {
+			md[k] = v
+		}
+		for _, item := range current {
+			if strings.HasPrefix(item.Key, securityGroupRuleMetadataPrefix) {
+				md[item.Key] = item.Value
+			}
+		}
+
+		err = securitygroups.MetadataReplace(clientCreate, gid, md).Err
 		if err != nil {
 			return diag.Errorf("cannot update metadata. Error: %s", err)
 		}
@@ -463,10 +450,9 @@ func resourceSecurityGroupDelete(ctx context.Context, d *schema.ResourceData, m
 	log.Println("[DEBUG] Start SecurityGroup deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	sgID := d.Id()
 
-	client, err := CreateClient(provider, d, securityGroupPoint, versionPointV1)
+	client, err := CreateClient(config, d, securityGroupPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -480,3 +466,86 @@ func resourceSecurityGroupDelete(ctx context.Context, d *schema.ResourceData, m
 	log.Printf("[DEBUG] Finish of SecurityGroup deleting")
 	return diags
 }
+
+// securityGroupRuleMap converts a rule to its security_group_rules element.
+func securityGroupRuleMap(sgr securitygroups.SecurityGroupRule) map[string]interface{} {
+	r := make(map[string]interface{})
+	r["id"] = sgr.ID
+	r["direction"] = sgr.Direction.String()
+
+	if sgr.EtherType != nil {
+		r["ethertype"] = sgr.EtherType.String()
+	}
+
+	r["protocol"] = types.ProtocolAny.String()
+	if sgr.Protocol != nil {
+		r["protocol"] = sgr.Protocol.String()
+	}
+
+	r["port_range_max"] = 0
+	if sgr.PortRangeMax != nil {
+		r["port_range_max"] = *sgr.PortRangeMax
+	}
+	r["port_range_min"] = 0
+	if sgr.PortRangeMin != nil {
+		r["port_range_min"] = *sgr.PortRangeMin
+	}
+
+	r["description"] = ""
+	if sgr.Description != nil {
+		r["description"] = *sgr.Description
+	}
+
+	r["remote_ip_prefix"] = ""
+	if sgr.RemoteIPPrefix != nil {
+		r["remote_ip_prefix"] = *sgr.RemoteIPPrefix
+	}
+
+	r["updated_at"] = ""
+	if sgr.UpdatedAt != nil {
+		r["updated_at"] = sgr.UpdatedAt.String()
+	}
+	r["created_at"] = sgr.CreatedAt.String()
+
+	return r
+}
+
+// findSecurityGroupRule returns the rule of the security group, nil when the
+// group has no such rule.
+func findSecurityGroupRule(sg *securitygroups.SecurityGroup, ruleID string) *securitygroups.SecurityGroupRule {
+	for i := range sg.SecurityGroupRules {
+		if sg.SecurityGroupRules[i].ID == ruleID {
+			return &sg.SecurityGroupRules[i]
+		}
+	}
+	return nil
+}
+
+// waitSecurityGroupRuleDeleted waits until the deleted rule is gone from the
+// security group, the rule delete isn't a task.
+func waitSecurityGroupRuleDeleted(ctx context.Context, client *gcorecloud.ServiceClient, sgID, ruleID string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(taskPollInterval)
+	defer ticker.Stop()
+	for {
+		sg, err := securitygroups.Get(client, sgID).Extract()
+		if err != nil {
+			if _, ok := err.(gcorecloud.ErrDefault404); ok {
+				return nil
+			}
+			return err
+		}
+		if findSecurityGroupRule(sg, ruleID) == nil {
+			return nil
+		}
+		log.Printf("[DEBUG] Rule %s of security group %s is being deleted", ruleID, sgID)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timeout while waiting for rule %s of security group %s to be deleted after %s", ruleID, sgID, timeout)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package gcore
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/securitygrouprules"
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/securitygroups"
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/types"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	securityGroupRuleCreateTimeout = 300
+	securityGroupRuleDeleteTimeout = 300
+
+	// securityGroupRuleMetadataPrefix starts the metadata keys of a security
+	// group marking its rules managed by gcore_securitygroup_rule resources.
+	securityGroupRuleMetadataPrefix = "gcore_securitygroup_rule_"
+)
+
+func resourceSecurityGroupRule() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSecurityGroupRuleCreate,
+		ReadContext:   resourceSecurityGroupRuleRead,
+		DeleteContext: resourceSecurityGroupRuleDelete,
+		CustomizeDiff: cloudScopeDiff,
+		Description:   "Represent a rule of SecurityGroup(Firewall). It can be used together with security_group_rules of gcore_securitygroup, the rule is marked in the metadata of the security group so it doesn't show up in security_group_rules. An imported rule is marked when it's read",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(securityGroupRuleCreateTimeout * time.Second),
+			Delete: schema.DefaultTimeout(securityGroupRuleDeleteTimeout * time.Second),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				projectID, regionID, sgID, ruleID, err := ImportStringParserExtended(d.Id())
+
+				if err != nil {
+					return nil, err
+				}
+				d.Set("project_id", projectID)
+				d.Set("region_id", regionID)
+				d.Set("security_group_id", sgID)
+				d.SetId(ruleID)
+
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
+			},
+			"region_id": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
+			},
+			"project_name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
+			},
+			"region_name": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
+			},
+			"security_group_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the security group the rule belongs to",
+			},
+			"direction": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: fmt.Sprintf("Available value is '%s', '%s'", types.RuleDirectionIngress, types.RuleDirectionEgress),
+				ValidateDiagFunc: func(v interface{}, path cty.Path) diag.Diagnostics {
+					val := v.(string)
+					switch types.RuleDirection(val) {
+					case types.RuleDirectionIngress, types.RuleDirectionEgress:
+						return nil
+					}
+					return diag.Errorf("wrong direction '%s', available value is '%s', '%s'", val, types.RuleDirectionIngress, types.RuleDirectionEgress)
+				},
+			},
+			"ethertype": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: fmt.Sprintf("Available value is '%s', '%s'", types.EtherTypeIPv4, types.EtherTypeIPv6),
+				ValidateDiagFunc: func(v interface{}, path cty.Path) diag.Diagnostics {
+					val := v.(string)
+					switch types.EtherType(val) {
+					case types.EtherTypeIPv4, types.EtherTypeIPv6:
+						return nil
+					}
+					return diag.Errorf("wrong ethertype '%s', available value is '%s', '%s'", val, types.EtherTypeIPv4, types.EtherTypeIPv6)
+				},
+			},
+			"protocol": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: fmt.Sprintf("Available value is %s", strings.Join(types.Protocol("").StringList(), ",")),
+			},
+			"port_range_min": &schema.Schema{
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          0,
+				ValidateDiagFunc: validatePortRange,
+			},
+			"port_range_max": &schema.Schema{
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          0,
+				ValidateDiagFunc: validatePortRange,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "",
+			},
+			"remote_ip_prefix": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"remote_group_id"},
+				Description:   "CIDR of the traffic source or destination",
+			},
+			"remote_group_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"remote_ip_prefix"},
+				Description:   "ID of the security group whose members are the traffic source or destination",
+			},
+			"updated_at": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created_at": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceSecurityGroupRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start SecurityGroupRule creating")
+	var diags diag.Diagnostics
+	config := m.(*Config)
+
+	client, err := CreateClient(config, d, securityGroupPoint, versionPointV1)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	sgID := d.Get("security_group_id").(string)
+	opts := extractSecurityGroupRuleMap(map[string]interface{}{
+		"direction":        d.Get("direction"),
+		"ethertype":        d.Get("ethertype"),
+		"protocol":         d.Get("protocol"),
+		"port_range_min":   d.Get("port_range_min"),
+		"port_range_max":   d.Get("port_range_max"),
+		"description":      d.Get("description"),
+		"remote_ip_prefix": d.Get("remote_ip_prefix"),
+		"remote_group_id":  d.Get("remote_group_id"),
+	}, sgID)
+
+	rule, err := securitygroups.AddRule(client, sgID, opts).Extract()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(rule.ID)
+	if err := markSecurityGroupRule(client, sgID, rule.ID); err != nil {
+		return diag.FromErr(err)
+	}
+
+	resourceSecurityGroupRuleRead(ctx, d, m)
+	log.Printf("[DEBUG] Finish SecurityGroupRule creating (%s)", rule.ID)
+	return diags
+}
+
+func resourceSecurityGroupRuleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start SecurityGroupRule reading")
+	var diags diag.Diagnostics
+	config := m.(*Config)
+
+	client, err := CreateClient(config, d, securityGroupPoint, versionPointV1)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	sgID := d.Get("security_group_id").(string)
+	sg, err := securitygroups.Get(client, sgID).Extract()
+	if err != nil {
+		switch err.(type) {
+		case gcorecloud.ErrDefault404:
+			log.Printf("[WARN] Removing security group rule %s because security group %s doesn't exist anymore", d.Id(), sgID)
+			d.SetId("")
+			return nil
+		default:
+			return diag.FromErr(err)
+		}
+	}
+
+	sgr := findSecurityGroupRule(sg, d.Id())
+	if sgr == nil {
+		log.Printf("[WARN] Removing security group rule %s because resource doesn't exist anymore", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	// The rule of an import isn't marked yet, it's marked once read so that
+	// gcore_securitygroup leaves it out from now on.
+	if !markedSecurityGroupRules(sg.Metadata)[sgr.ID] {
+		if err := markSecurityGroupRule(client, sgID, sgr.ID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.Set("project_id", sg.ProjectID)
+	d.Set("region_id", sg.RegionID)
+	for k, v := range securityGroupRuleMap(*sgr) {
+		if k != "id" {
+			d.Set(k, v)
+		}
+	}
+	remoteGroupID := ""
+	if sgr.RemoteGroupID != nil {
+		remoteGroupID = *sgr.RemoteGroupID
+	}
+	d.Set("remote_group_id", remoteGroupID)
+
+	log.Println("[DEBUG] Finish SecurityGroupRule reading")
+	return diags
+}
+
+func resourceSecurityGroupRuleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start SecurityGroupRule deleting")
+	var diags diag.Diagnostics
+	config := m.(*Config)
+	ruleID := d.Id()
+	sgID := d.Get("security_group_id").(string)
+
+	sgClient, err := CreateClient(config, d, securityGroupPoint, versionPointV1)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	client, err := CreateClient(config, d, securityGroupRulesPoint, versionPointV1)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = securitygrouprules.Delete(client, ruleID).ExtractErr()
+	if _, notFound := err.(gcorecloud.ErrDefault404); err != nil && !notFound {
+		return diag.FromErr(err)
+	}
+	if err := waitSecurityGroupRuleDeleted(ctx, sgClient, sgID, ruleID, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := unmarkSecurityGroupRule(sgClient, sgID, ruleID); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	log.Printf("[DEBUG] Finish of SecurityGroupRule deleting")
+	return diags
+}
+
+// markSecurityGroupRule records in the metadata of the security group that
+// the rule belongs to a gcore_securitygroup_rule resource, so gcore_securitygroup
+// leaves it out of its security_group_rules.
+func markSecurityGroupRule(client *gcorecloud.ServiceClient, sgID, ruleID string) error {
+	md := map[string]interface{}{securityGroupRuleMetadataPrefix + ruleID: "true"}
+	if err := securitygroups.MetadataCreateOrUpdate(client, sgID, md).Err; err != nil {
+		return fmt.Errorf("cannot mark rule %s in the metadata of security group %s: %w", ruleID, sgID, err)
+	}
+	return nil
+}
+
+// unmarkSecurityGroupRule removes the mark of a deleted rule, the security
+// group may be gone already.
+func unmarkSecurityGroupRule(client *gcorecloud.ServiceClient, sgID, ruleID string) error {
+	err := securitygroups.MetadataDelete(client, sgID, securityGroupRuleMetadataPrefix+ruleID).Err
+	if _, notFound := err.(gcorecloud.ErrDefault404); err != nil && !notFound {
+		return fmt.Errorf("cannot remove the mark of rule %s from the metadata of security group %s: %w", ruleID, sgID, err)
+	}
+	return nil
+}
+
+// markedSecurityGroupRules returns the IDs of the rules of
+// gcore_securitygroup_rule resources marked in the security group metadata.
+func markedSecurityGroupRules(md []securitygroups.Metadata) map[string]bool {
+	ids := make(map[string]bool)
+	for _, item := range md {
+		if strings.HasPrefix(item.Key, securityGroupRuleMetadataPrefix) {
+			ids[strings.TrimPrefix(item.Key, securityGroupRuleMetadataPrefix)] = true
+		}
+	}
+	return ids
+}
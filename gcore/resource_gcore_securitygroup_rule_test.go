@@ -0,0 +1,142 @@
+//go:build cloud
+// +build cloud
+
+package gcore
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestUnitSecurityGroupRule(t *testing.T) {
+	api := newMockAPI(t)
+	sgID := api.cloud.Seed("securitygroups", map[string]interface{}{
+		"security_group": map[string]interface{}{"name": "web"},
+	})
+	lbID := api.cloud.Seed("securitygroups", map[string]interface{}{
+		"security_group": map[string]interface{}{"name": "lb"},
+	})
+	fullName := "gcore_securitygroup_rule.test"
+
+	config := api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_securitygroup_rule" "test" {
+  %s
+  security_group_id = "%s"
+  direction         = "ingress"
+  ethertype         = "IPv4"
+  protocol          = "tcp"
+  port_range_min    = 80
+  port_range_max    = 80
+  remote_group_id   = "%s"
+}
+`, mockCloudScope, sgID, lbID)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			if rules := api.cloud.SecurityGroupRules(sgID); len(rules) != 0 {
+				return fmt.Errorf("expected the rule deleted, got %v", rules)
+			}
+			if md := api.cloud.Metadata("securitygroups", sgID); len(md) != 0 {
+				return fmt.Errorf("expected the mark of the rule removed, got %v", md)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "direction", "ingress"),
+					resource.TestCheckResourceAttr(fullName, "protocol", "tcp"),
+					resource.TestCheckResourceAttr(fullName, "port_range_min", "80"),
+					resource.TestCheckResourceAttr(fullName, "port_range_max", "80"),
+					resource.TestCheckResourceAttr(fullName, "remote_group_id", lbID),
+					resource.TestCheckResourceAttr(fullName, "remote_ip_prefix", ""),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if _, ok := api.cloud.Metadata("securitygroups", sgID)[securityGroupRuleMetadataPrefix+s.ID]; !ok {
+							return fmt.Errorf("expected rule %s marked in the security group metadata", s.ID)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_securitygroup_rule" "test" {
+  %s
+  security_group_id = "%s"
+  direction         = "ingress"
+  ethertype         = "IPv4"
+  protocol          = "tcp"
+  remote_group_id   = "%s"
+  remote_ip_prefix  = "10.0.0.0/8"
+}
+`, mockCloudScope, sgID, lbID),
+				ExpectError: regexp.MustCompile("conflicts with"),
+			},
+			{
+				Config: config,
+			},
+			{
+				ResourceName: fullName,
+				ImportState:  true,
+				ImportStateIdFunc: unitImportID(fullName, func(s *terraform.InstanceState) string {
+					return fmt.Sprintf("%d:%d:%s:%s", mockProjectID, mockRegionID, sgID, s.ID)
+				}),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"project_name", "region_name"},
+			},
+		},
+	})
+}
+
+func TestUnitSecurityGroupRuleImport(t *testing.T) {
+	api := newMockAPI(t)
+	sgID := api.cloud.Seed("securitygroups", map[string]interface{}{
+		"security_group": map[string]interface{}{"name": "web"},
+	})
+	ruleID := api.cloud.SeedSecurityGroupRule(sgID, map[string]interface{}{
+		"direction": "ingress", "ethertype": "IPv4", "protocol": "tcp",
+		"port_range_min": 443, "port_range_max": 443,
+	})
+
+	// The import itself doesn't write to the API, the rule is marked when
+	// the imported resource is read.
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_securitygroup_rule" "test" {
+  %s
+  security_group_id = "%s"
+  direction         = "ingress"
+  ethertype         = "IPv4"
+  protocol          = "tcp"
+  port_range_min    = 443
+  port_range_max    = 443
+}
+`, mockCloudScope, sgID),
+				ResourceName:  "gcore_securitygroup_rule.test",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("%d:%d:%s:%s", mockProjectID, mockRegionID, sgID, ruleID),
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 || states[0].ID != ruleID {
+						return fmt.Errorf("expected rule %s imported, got %v", ruleID, states)
+					}
+					if got := states[0].Attributes["port_range_min"]; got != "443" {
+						return fmt.Errorf("expected port_range_min 443, got %s", got)
+					}
+					if _, ok := api.cloud.Metadata("securitygroups", sgID)[securityGroupRuleMetadataPrefix+ruleID]; !ok {
+						return fmt.Errorf("expected rule %s marked in the security group metadata", ruleID)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
@@ -5,6 +5,9 @@ package gcore
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/securitygroups"
@@ -104,3 +107,181 @@ func testAccSecurityGroupDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+const (
+	unitSecurityGroupEgress = `
+  security_group_rules {
+    direction = "egress"
+    ethertype = "IPv4"
+    protocol  = "any"
+  }
+`
+	unitSecurityGroupHTTPS = `
+  security_group_rules {
+    direction        = "ingress"
+    ethertype        = "IPv4"
+    protocol         = "tcp"
+    port_range_min   = 443
+    port_range_max   = 443
+    remote_ip_prefix = "0.0.0.0/0"
+  }
+`
+)
+
+func TestUnitSecurityGroup(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_securitygroup.test"
+	template := func(env, rules string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_securitygroup" "test" {
+  %s
+  name         = "web"
+  description  = "web servers"
+  metadata_map = {
+    env = "%s"
+  }
+%s
+}
+`, mockCloudScope, env, rules)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_securitygroup", "securitygroups"),
+		Steps: []resource.TestStep{
+			{
+				Config: template("test", unitSecurityGroupEgress),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "web"),
+					resource.TestCheckResourceAttr(fullName, "description", "web servers"),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.env", "test"),
+					resource.TestCheckResourceAttr(fullName, "metadata_read_only.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "security_group_rules.#", "1"),
+				),
+			},
+			{
+				Config: template("prod", unitSecurityGroupEgress+unitSecurityGroupHTTPS),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "metadata_map.env", "prod"),
+					resource.TestCheckResourceAttr(fullName, "security_group_rules.#", "2"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if rules := api.cloud.SecurityGroupRules(s.ID); len(rules) != 2 {
+							return fmt.Errorf("expected 2 rules, got %v", rules)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config:      template("prod", unitSecurityGroupHTTPS),
+				ExpectError: regexp.MustCompile("at least one 'egress' rule should be set"),
+			},
+			{
+				Config: template("prod", unitSecurityGroupEgress+unitSecurityGroupHTTPS),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"project_name", "region_name", "last_updated"},
+			},
+		},
+	})
+}
+
+func TestUnitSecurityGroupRulesCoexist(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_securitygroup.test"
+	ssh := `
+  security_group_rules {
+    direction        = "ingress"
+    ethertype        = "IPv4"
+    protocol         = "tcp"
+    port_range_min   = 22
+    port_range_max   = 22
+    remote_ip_prefix = "10.0.0.0/8"
+  }
+`
+	// https is a rule of a gcore_securitygroup_rule resource, it isn't a
+	// drift of security_group_rules.
+	template := func(extra, rules string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_securitygroup" "test" {
+  %s
+  name = "web"
+  %s
+%s
+}
+
+resource "gcore_securitygroup_rule" "https" {
+  %s
+  security_group_id = gcore_securitygroup.test.id
+  direction         = "ingress"
+  ethertype         = "IPv4"
+  protocol          = "tcp"
+  port_range_min    = 443
+  port_range_max    = 443
+}
+`, mockCloudScope, extra, rules, mockCloudScope)
+	}
+	ingressPorts := func(sgID string) []string {
+		var ports []string
+		for _, rule := range api.cloud.SecurityGroupRules(sgID) {
+			if rule["direction"] == "ingress" {
+				ports = append(ports, fmt.Sprint(rule["port_range_min"]))
+			}
+		}
+		sort.Strings(ports)
+		return ports
+	}
+	var sgID string
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_securitygroup", "securitygroups"),
+		Steps: []resource.TestStep{
+			{
+				Config: template("", unitSecurityGroupEgress+ssh),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "security_group_rules.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.%", "0"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						sgID = s.ID
+						return nil
+					}),
+				),
+			},
+			{
+				// A rule added out of band is a drift and is deleted, like
+				// the removed ssh rule; the https rule is kept.
+				PreConfig: func() {
+					api.cloud.SeedSecurityGroupRule(sgID, map[string]interface{}{
+						"direction": "ingress", "ethertype": "IPv4", "protocol": "tcp",
+						"port_range_min": 8080, "port_range_max": 8080,
+					})
+				},
+				Config: template(`metadata_map = {
+    env = "test"
+  }`, unitSecurityGroupEgress),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "security_group_rules.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.%", "1"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if ports := ingressPorts(s.ID); strings.Join(ports, ",") != "443" {
+							return fmt.Errorf("expected only the ingress rule of port 443, got %v", ports)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"project_name", "region_name", "last_updated"},
+			},
+		},
+	})
+}
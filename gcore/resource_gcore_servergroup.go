@@ -18,6 +18,7 @@ func resourceServerGroup() *schema.Resource {
 		CreateContext: resourceServerGroupCreate,
 		ReadContext:   resourceServerGroupRead,
 		DeleteContext: resourceServerGroupDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent server group resource",
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -36,40 +37,30 @@ func resourceServerGroup() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": {
 				Type:        schema.TypeString,
@@ -108,9 +99,8 @@ func resourceServerGroupCreate(ctx context.Context, d *schema.ResourceData, m in
 	log.Println("[DEBUG] Start ServerGroup creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, serverGroupsPoint, versionPointV1)
+	client, err := CreateClient(config, d, serverGroupsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -135,9 +125,8 @@ func resourceServerGroupRead(ctx context.Context, d *schema.ResourceData, m inte
 	log.Println("[DEBUG] Start ServerGroup reading")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, serverGroupsPoint, versionPointV1)
+	client, err := CreateClient(config, d, serverGroupsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -171,9 +160,8 @@ func resourceServerGroupDelete(ctx context.Context, d *schema.ResourceData, m in
 	log.Println("[DEBUG] Start ServerGroup deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, serverGroupsPoint, versionPointV1)
+	client, err := CreateClient(config, d, serverGroupsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -5,6 +5,7 @@ package gcore
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/G-Core/gcorelabscloud-go/gcore/servergroup/v1/servergroups"
@@ -72,3 +73,64 @@ func testAccServerGroupDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitServerGroup(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_servergroup.test"
+	template := func(policy string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_servergroup" "test" {
+  %s
+  name   = "group"
+  policy = "%s"
+}
+`, mockCloudScope, policy)
+	}
+	var firstID string
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_servergroup", "servergroups"),
+		Steps: []resource.TestStep{
+			{
+				Config: template("affinity"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "group"),
+					resource.TestCheckResourceAttr(fullName, "policy", "affinity"),
+					resource.TestCheckResourceAttr(fullName, "instances.#", "0"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						firstID = s.ID
+						return nil
+					}),
+				),
+			},
+			{
+				// The policy can't be changed, the group is replaced.
+				Config: template("anti-affinity"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "policy", "anti-affinity"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if s.ID == firstID || api.cloud.Get("servergroups", firstID) != nil {
+							return fmt.Errorf("expected group %s replaced, got %s", firstID, s.ID)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config:      template("soft-affinity"),
+				ExpectError: regexp.MustCompile("unknown policy"),
+			},
+			{
+				Config: template("anti-affinity"),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"project_name", "region_name"},
+			},
+		},
+	})
+}
@@ -23,6 +23,12 @@ func resourceSnapshot() *schema.Resource {
 		ReadContext:   resourceSnapshotRead,
 		UpdateContext: resourceSnapshotUpdate,
 		DeleteContext: resourceSnapshotDelete,
+		CustomizeDiff: cloudScopeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Duration(snapshotCreatingTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(snapshotCreatingTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(snapshotDeleting) * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, snapshotID, err := ImportStringParser(d.Id())
@@ -40,36 +46,26 @@ func resourceSnapshot() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -114,9 +110,8 @@ func resourceSnapshotCreate(ctx context.Context, d *schema.ResourceData, m inter
 	log.Println("[DEBUG] Start snapshot creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, snapshotsPoint, versionPointV1)
+	client, err := CreateClient(config, d, snapshotsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -129,7 +124,7 @@ func resourceSnapshotCreate(ctx context.Context, d *schema.ResourceData, m inter
 
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	SnapshotID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, snapshotCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
+	SnapshotID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -158,11 +153,10 @@ func resourceSnapshotRead(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Printf("[DEBUG] Start snapshot reading %s", d.State())
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	snapshotID := d.Id()
 	log.Printf("[DEBUG] Snapshot id = %s", snapshotID)
 
-	client, err := CreateClient(provider, d, snapshotsPoint, versionPointV1)
+	client, err := CreateClient(config, d, snapshotsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -192,8 +186,7 @@ func resourceSnapshotUpdate(ctx context.Context, d *schema.ResourceData, m inter
 	snapshotID := d.Id()
 	if d.HasChange("metadata") {
 		config := m.(*Config)
-		provider := config.Provider
-		client, err := CreateClient(provider, d, snapshotsPoint, versionPointV1)
+		client, err := CreateClient(config, d, snapshotsPoint, versionPointV1)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -217,11 +210,10 @@ func resourceSnapshotDelete(ctx context.Context, d *schema.ResourceData, m inter
 	log.Println("[DEBUG] Start snapshot deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	snapshotID := d.Id()
 	log.Printf("[DEBUG] Snapshot id = %s", snapshotID)
 
-	client, err := CreateClient(provider, d, snapshotsPoint, versionPointV1)
+	client, err := CreateClient(config, d, snapshotsPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -232,7 +224,7 @@ func resourceSnapshotDelete(ctx context.Context, d *schema.ResourceData, m inter
 	}
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, snapshotDeleting, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := snapshots.Get(client, snapshotID).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete snapshot with ID: %s", snapshotID)
@@ -122,3 +122,64 @@ func testAccSnapshotDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitSnapshot(t *testing.T) {
+	api := newMockAPI(t)
+	volumeID := api.cloud.Seed("volumes", map[string]interface{}{"name": "volume", "size": 2})
+	fullName := "gcore_snapshot.test"
+
+	template := func(metadata string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_snapshot" "test" {
+  %s
+  name      = "snapshot"
+  volume_id = "%s"
+  metadata  = %s
+}
+`, mockCloudScope, volumeID, metadata)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_snapshot", "snapshots"),
+		Steps: []resource.TestStep{
+			{
+				Config: template(`{
+    env = "test"
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "snapshot"),
+					resource.TestCheckResourceAttr(fullName, "volume_id", volumeID),
+					resource.TestCheckResourceAttr(fullName, "size", "2"),
+					resource.TestCheckResourceAttr(fullName, "status", "available"),
+					resource.TestCheckResourceAttr(fullName, "metadata.%", "1"),
+					resource.TestCheckResourceAttr(fullName, "metadata.env", "test"),
+				),
+			},
+			{
+				Config: template(`{
+    env   = "prod"
+    owner = "unit"
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "metadata.%", "2"),
+					resource.TestCheckResourceAttr(fullName, "metadata.env", "prod"),
+					resource.TestCheckResourceAttr(fullName, "metadata.owner", "unit"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if md := mockMap(api.cloud.Get("snapshots", s.ID)["metadata"]); md["owner"] != "unit" {
+							return fmt.Errorf("metadata wasn't replaced: %v", md)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "project_name", "region_name"},
+			},
+		},
+	})
+}
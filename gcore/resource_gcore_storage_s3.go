@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/G-Core/gcore-storage-sdk-go/swagger/client/storage"
+	"github.com/G-Core/gcore-storage-sdk-go/swagger/models"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -20,6 +23,7 @@ const (
 	StorageSchemaGenerateHTTPEndpoint = "generated_http_endpoint"
 	StorageSchemaGenerateS3Endpoint   = "generated_s3_endpoint"
 	StorageSchemaGenerateEndpoint     = "generated_endpoint"
+	StorageS3SchemaRotateKeysTrigger  = "rotate_keys_trigger"
 
 	StorageSchemaLocation = "location"
 	StorageSchemaName     = "name"
@@ -56,32 +60,24 @@ func resourceStorageS3() *schema.Resource {
 				Description: "A name of new storage resource.",
 			},
 			StorageSchemaLocation: {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				ValidateDiagFunc: func(v interface{}, path cty.Path) diag.Diagnostics {
-					val := v.(string)
-					allowed := []string{"s-ed1", "s-darz1", "s-ws1", "s-dt2", "s-drc2"}
-					for _, el := range allowed {
-						if el == val {
-							return nil
-						}
-					}
-					return diag.Errorf(`must be one of %+v`, allowed)
-				},
-				Description: "A location of new storage resource. One of (s-ed1, s-darz1, s-ws1, s-dt2, s-drc2)",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "A location of new storage resource, e.g. s-ed1. The locations of s3 storages are listed by the gcore_storage_locations data source.",
 			},
 			StorageS3SchemaGenerateAccessKey: {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
-				Description: "A s3 access key for new storage resource.",
+				Sensitive:   true,
+				Description: "A s3 access key for new storage resource, the API returns it on create and on rotation only.",
 			},
 			StorageS3SchemaGenerateSecretKey: {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
-				Description: "A s3 secret key for new storage resource.",
+				Sensitive:   true,
+				Description: "A s3 secret key for new storage resource, the API returns it on create and on rotation only.",
 			},
 			StorageSchemaGenerateHTTPEndpoint: {
 				Type:        schema.TypeString,
@@ -101,10 +97,17 @@ func resourceStorageS3() *schema.Resource {
 				Computed:    true,
 				Description: "A s3 entry point for new storage resource.",
 			},
+			StorageS3SchemaRotateKeysTrigger: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An arbitrary value, a change of it generates new s3 keys of the storage in place, e.g. a date of the rotation. The old keys stop working, the buckets and the objects are kept.",
+			},
 		},
 		CreateContext: resourceStorageS3Create,
 		ReadContext:   resourceStorageS3Read,
+		UpdateContext: resourceStorageS3Update,
 		DeleteContext: resourceStorageS3Delete,
+		CustomizeDiff: customdiff.All(checkStorageLocation("s3"), resourceStorageS3CustomizeDiff),
 		Description:   "Represent s3 storage resource. https://storage.gcorelabs.com/storage/list",
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -148,6 +151,44 @@ func resourceStorageS3Create(ctx context.Context, d *schema.ResourceData, m inte
 	return resourceStorageS3Read(ctx, d, m)
 }
 
+func resourceStorageS3Update(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourceId := storageResourceID(d)
+	log.Printf("[DEBUG] Start S3 Storage Resource updating (id=%s)\n", resourceId)
+	defer log.Println("[DEBUG] Finish S3 Storage Resource updating")
+
+	config := m.(*Config)
+	client := config.StorageClient
+
+	id, err := strconv.ParseInt(resourceId, 10, 64)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("get resource id: %w", err))
+	}
+
+	if d.HasChange(StorageS3SchemaRotateKeysTrigger) {
+		opts := []func(*storage.StorageUpdateCredentialsHTTPParams){
+			func(params *storage.StorageUpdateCredentialsHTTPParams) {
+				params.ID = id
+				params.Context = ctx
+				params.Body.GenerateS3Keys = true
+			},
+		}
+		result, err := client.UpdateStorageCredentials(opts...)
+		if err != nil {
+			// The old keys still work.
+			old, _ := d.GetChange(StorageS3SchemaRotateKeysTrigger)
+			_ = d.Set(StorageS3SchemaRotateKeysTrigger, old)
+			return diag.FromErr(fmt.Errorf("update creds: %w", err))
+		}
+		if result.S3 == nil || result.S3.AccessKey == "" || result.S3.SecretKey == "" {
+			return diag.Errorf("update creds: the api returned no s3 keys")
+		}
+		_ = d.Set(StorageS3SchemaGenerateAccessKey, result.S3.AccessKey)
+		_ = d.Set(StorageS3SchemaGenerateSecretKey, result.S3.SecretKey)
+	}
+
+	return resourceStorageS3Read(ctx, d, m)
+}
+
 func resourceStorageS3Read(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	resourceId := storageResourceID(d)
 	log.Printf("[DEBUG] Start S3 Storage Resource reading (id=%s)\n", resourceId)
@@ -238,3 +279,48 @@ func storageResourceID(d *schema.ResourceData) string {
 	}
 	return resourceID
 }
+
+// resourceStorageS3CustomizeDiff plans new keys on a rotation, so the
+// resources using the keys are updated with the new ones.
+func resourceStorageS3CustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange(StorageS3SchemaRotateKeysTrigger) {
+		return nil
+	}
+	if err := d.SetNewComputed(StorageS3SchemaGenerateAccessKey); err != nil {
+		return err
+	}
+	return d.SetNewComputed(StorageS3SchemaGenerateSecretKey)
+}
+
+// checkStorageLocation checks the location of a new storage of the type
+// against the locations of the API at plan time, so new locations can be
+// used without a new release of the provider.
+func checkStorageLocation(storageType string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if !d.HasChange(StorageSchemaLocation) || !d.NewValueKnown(StorageSchemaLocation) {
+			return nil
+		}
+		client := m.(*Config).StorageClient
+		if client == nil {
+			return fmt.Errorf("storage api client is null. make sure that you defined gcore_storage_api var in gcore provider section.")
+		}
+		locations, err := client.LocationsList(ctx)
+		if err != nil {
+			return fmt.Errorf("list storage locations: %w", err)
+		}
+
+		location := d.Get(StorageSchemaLocation).(string)
+		var allowed []string
+		for _, l := range locations {
+			if l.Type != storageType || l.AllowForNewStorage == models.ClientLocationResAllowForNewStorageDeny {
+				continue
+			}
+			if l.Name == location {
+				return nil
+			}
+			allowed = append(allowed, l.Name)
+		}
+		sort.Strings(allowed)
+		return fmt.Errorf("location %q is not available for new %s storages, must be one of %v", location, storageType, allowed)
+	}
+}
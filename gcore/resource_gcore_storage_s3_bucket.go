@@ -5,18 +5,30 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/G-Core/gcore-storage-sdk-go/swagger/client/storage"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
 	StorageS3BucketSchemaName      = "name"
 	StorageS3BucketSchemaStorageID = "storage_id"
+
+	StorageS3BucketSchemaCORS           = "cors"
+	StorageS3BucketSchemaAllowedOrigins = "allowed_origins"
+	StorageS3BucketSchemaLifecycle      = "lifecycle_rule"
+	StorageS3BucketSchemaExpirationDays = "expiration_days"
+	StorageS3BucketSchemaPolicy         = "policy"
+
+	StorageS3BucketPolicyPrivate    = "private"
+	StorageS3BucketPolicyPublicRead = "public-read"
 )
 
 func resourceStorageS3Bucket() *schema.Resource {
@@ -43,9 +55,51 @@ func resourceStorageS3Bucket() *schema.Resource {
 				},
 				Description: "A name of new storage bucket resource.",
 			},
+			StorageS3BucketSchemaCORS: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "CORS settings of the bucket, browsers of other origins can't read its objects without them.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						StorageS3BucketSchemaAllowedOrigins: {
+							Type:        schema.TypeSet,
+							Required:    true,
+							MinItems:    1,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Origins allowed to read the objects, e.g. https://example.com or *.",
+						},
+					},
+				},
+			},
+			StorageS3BucketSchemaLifecycle: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Lifecycle rules of the objects of the bucket.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						StorageS3BucketSchemaExpirationDays: {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "Days after which the objects are removed.",
+						},
+					},
+				},
+			},
+			StorageS3BucketSchemaPolicy: {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					StorageS3BucketPolicyPrivate, StorageS3BucketPolicyPublicRead,
+				}, false),
+				Description: "An access policy of the bucket, 'public-read' lets anyone read its objects, 'private' requires the keys. The API doesn't return the policy, so changes made outside of Terraform are not detected. The policy is not managed when unset.",
+			},
 		},
 		CreateContext: resourceStorageS3BucketCreate,
 		ReadContext:   resourceStorageS3BucketRead,
+		UpdateContext: resourceStorageS3BucketUpdate,
 		DeleteContext: resourceStorageS3BucketDelete,
 		Description:   "Represent s3 storage bucket resource. https://storage.gcorelabs.com/storage/list",
 		Importer: &schema.ResourceImporter{
@@ -78,9 +132,104 @@ func resourceStorageS3BucketCreate(ctx context.Context, d *schema.ResourceData,
 	}
 	d.SetId(fmt.Sprintf("%d:%s", id, name))
 
+	if err := updateStorageS3BucketSettings(ctx, client, d, true); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceStorageS3BucketRead(ctx, d, m)
+}
+
+func resourceStorageS3BucketUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	storageId, bucketName := storageBucketResourceID(d)
+	log.Printf("[DEBUG] Start S3 Storage Bucket Resource updating (id=%d, name=%s)\n", storageId, bucketName)
+	defer log.Println("[DEBUG] Finish S3 Storage Bucket Resource updating")
+
+	config := m.(*Config)
+	client := config.StorageClient
+
+	if err := updateStorageS3BucketSettings(ctx, client, d, false); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceStorageS3BucketRead(ctx, d, m)
 }
 
+// updateStorageS3BucketSettings applies the CORS, lifecycle and policy
+// settings of the bucket, all the set ones on create and the changed ones
+// otherwise.
+func updateStorageS3BucketSettings(ctx context.Context, client *StorageClient, d *schema.ResourceData, create bool) error {
+	storageID, bucketName := storageBucketResourceID(d)
+	id := int64(storageID)
+
+	if _, ok := d.GetOk(StorageS3BucketSchemaCORS); (create && ok) || (!create && d.HasChange(StorageS3BucketSchemaCORS)) {
+		// No origins remove the CORS settings.
+		origins := make([]string, 0)
+		for _, cors := range d.Get(StorageS3BucketSchemaCORS).([]interface{}) {
+			if cors == nil {
+				continue
+			}
+			for _, o := range cors.(map[string]interface{})[StorageS3BucketSchemaAllowedOrigins].(*schema.Set).List() {
+				origins = append(origins, o.(string))
+			}
+		}
+		sort.Strings(origins)
+		err := client.CreateBucketCORS(func(opt *storage.StorageBucketCORSCreateHTTPParams) {
+			opt.Context = ctx
+			opt.ID = id
+			opt.Name = bucketName
+			opt.Body.AllowedOrigins = origins
+		})
+		if err != nil {
+			return fmt.Errorf("set bucket cors: %w", err)
+		}
+	}
+
+	if _, ok := d.GetOk(StorageS3BucketSchemaLifecycle); (create && ok) || (!create && d.HasChange(StorageS3BucketSchemaLifecycle)) {
+		days := d.Get(StorageS3BucketSchemaLifecycle + ".0." + StorageS3BucketSchemaExpirationDays).(int)
+		var err error
+		if days > 0 {
+			err = client.CreateBucketLifecycle(func(opt *storage.StorageBucketLifecycleCreateHTTPParams) {
+				opt.Context = ctx
+				opt.ID = id
+				opt.Name = bucketName
+				opt.Body.ExpirationDays = int64(days)
+			})
+		} else {
+			err = client.DeleteBucketLifecycle(func(opt *storage.StorageBucketLifecycleDeleteHTTPParams) {
+				opt.Context = ctx
+				opt.ID = id
+				opt.Name = bucketName
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("set bucket lifecycle: %w", err)
+		}
+	}
+
+	// A new bucket is private.
+	policy := d.Get(StorageS3BucketSchemaPolicy).(string)
+	if (create && policy == StorageS3BucketPolicyPublicRead) || (!create && d.HasChange(StorageS3BucketSchemaPolicy) && policy != "") {
+		var err error
+		if policy == StorageS3BucketPolicyPublicRead {
+			err = client.CreateBucketPolicy(func(opt *storage.StorageBucketPolicyCreateHTTPParams) {
+				opt.Context = ctx
+				opt.ID = id
+				opt.Name = bucketName
+			})
+		} else {
+			err = client.DeleteBucketPolicy(ctx, id, bucketName)
+		}
+		if err != nil {
+			// The bucket keeps the old policy.
+			old, _ := d.GetChange(StorageS3BucketSchemaPolicy)
+			_ = d.Set(StorageS3BucketSchemaPolicy, old)
+			return fmt.Errorf("set bucket policy: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func resourceStorageS3BucketRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	storageId, bucketName := storageBucketResourceID(d)
 	log.Printf("[DEBUG] Start S3 Storage Bucket Resource reading (id=%d, name=%s)\n", storageId, bucketName)
@@ -106,6 +255,26 @@ func resourceStorageS3BucketRead(ctx context.Context, d *schema.ResourceData, m
 			d.SetId(fmt.Sprintf("%d:%s", storageId, bucketName))
 			_ = d.Set(StorageS3BucketSchemaStorageID, storageId)
 			_ = d.Set(StorageS3BucketSchemaName, bucketName)
+
+			lifecycle := make([]interface{}, 0)
+			if bucket.Lifecycle > 0 {
+				lifecycle = append(lifecycle, map[string]interface{}{StorageS3BucketSchemaExpirationDays: bucket.Lifecycle})
+			}
+			_ = d.Set(StorageS3BucketSchemaLifecycle, lifecycle)
+
+			rawCORS, err := client.BucketCORS(func(opt *storage.GetStorageBucketCORSHTTPParams) {
+				opt.Context = ctx
+				opt.ID = int64(storageId)
+				opt.Name = bucketName
+			})
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("get bucket cors: %w", err))
+			}
+			cors := make([]interface{}, 0)
+			if origins := parseStorageBucketCORS(rawCORS); len(origins) > 0 {
+				cors = append(cors, map[string]interface{}{StorageS3BucketSchemaAllowedOrigins: origins})
+			}
+			_ = d.Set(StorageS3BucketSchemaCORS, cors)
 			return nil
 		}
 	}
@@ -153,3 +322,13 @@ func storageBucketResourceID(d *schema.ResourceData) (storageID int, bucketName
 	bucketName = parts[1]
 	return storageID, bucketName
 }
+
+// parseStorageBucketCORS returns the allowed origins of the CORS settings the
+// API returns as a list separated by commas.
+func parseStorageBucketCORS(raw string) []interface{} {
+	origins := make([]interface{}, 0)
+	for _, o := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		origins = append(origins, o)
+	}
+	return origins
+}
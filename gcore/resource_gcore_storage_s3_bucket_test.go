@@ -6,6 +6,10 @@ package gcore
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -77,3 +81,120 @@ resource "gcore_storage_s3_bucket" "terraform_test_%d_s3_bucket" {
 		},
 	})
 }
+
+func TestUnitStorageS3Bucket(t *testing.T) {
+	api := newMockAPI(t)
+	storageID := api.storage.SeedStorage("s3", "s-ed1", "unit-s3")
+	fullName := "gcore_storage_s3_bucket.test"
+
+	template := func(extra string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_storage_s3_bucket" "test" {
+  storage_id = %d
+  name       = "bucket"
+  %s
+}
+`, storageID, extra)
+	}
+	checkBucket := func(lifecycle int, cors []string, policy string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			b := api.storage.Bucket(storageID, "bucket")
+			var origins []string
+			for _, o := range mockList(b["cors"]) {
+				origins = append(origins, mockString(o))
+			}
+			sort.Strings(origins)
+			if mockInt(b["lifecycle"]) != lifecycle || strings.Join(origins, ",") != strings.Join(cors, ",") || b["policy"] != policy {
+				return fmt.Errorf("unexpected bucket settings: %v", b)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(*terraform.State) error {
+			if api.storage.Bucket(storageID, "bucket") != nil {
+				return fmt.Errorf("bucket still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: template(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "id", fmt.Sprintf("%d:bucket", storageID)),
+					resource.TestCheckResourceAttr(fullName, "storage_id", strconv.Itoa(storageID)),
+					resource.TestCheckResourceAttr(fullName, "name", "bucket"),
+					resource.TestCheckResourceAttr(fullName, "cors.#", "0"),
+					resource.TestCheckResourceAttr(fullName, "lifecycle_rule.#", "0"),
+					checkBucket(0, nil, "private"),
+				),
+			},
+			{
+				Config: template(`
+  policy = "public-read"
+
+  cors {
+    allowed_origins = ["https://example.com", "https://example.org"]
+  }
+
+  lifecycle_rule {
+    expiration_days = 7
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "cors.0.allowed_origins.#", "2"),
+					resource.TestCheckResourceAttr(fullName, "lifecycle_rule.0.expiration_days", "7"),
+					resource.TestCheckResourceAttr(fullName, "policy", "public-read"),
+					checkBucket(7, []string{"https://example.com", "https://example.org"}, "public-read"),
+				),
+			},
+			{
+				// The settings changed outside of Terraform are restored.
+				PreConfig: func() {
+					api.storage.SeedBucket(storageID, "bucket", map[string]interface{}{"lifecycle": 1, "cors": []interface{}{"*"}})
+				},
+				Config: template(`
+  policy = "private"
+
+  cors {
+    allowed_origins = ["https://example.com"]
+  }
+
+  lifecycle_rule {
+    expiration_days = 30
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "cors.0.allowed_origins.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "lifecycle_rule.0.expiration_days", "30"),
+					resource.TestCheckResourceAttr(fullName, "policy", "private"),
+					checkBucket(30, []string{"https://example.com"}, "private"),
+				),
+			},
+			{
+				Config: template(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "cors.#", "0"),
+					resource.TestCheckResourceAttr(fullName, "lifecycle_rule.#", "0"),
+					checkBucket(0, nil, "private"),
+				),
+			},
+			{
+				Config:      template(`policy = "public"`),
+				ExpectError: regexp.MustCompile(`expected policy to be one of \[private public-read\]`),
+			},
+			{
+				Config: template(""),
+			},
+			{
+				ResourceName:      fullName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The API doesn't return the policy.
+				ImportStateVerifyIgnore: []string{"policy"},
+			},
+		},
+	})
+}
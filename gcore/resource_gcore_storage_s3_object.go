@@ -0,0 +1,295 @@
+package gcore
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	StorageS3ObjectSchemaEndpoint     = "s3_endpoint"
+	StorageS3ObjectSchemaRegion       = "region"
+	StorageS3ObjectSchemaAccessKey    = "access_key"
+	StorageS3ObjectSchemaSecretKey    = "secret_key"
+	StorageS3ObjectSchemaBucket       = "bucket"
+	StorageS3ObjectSchemaKey          = "key"
+	StorageS3ObjectSchemaSource       = "source"
+	StorageS3ObjectSchemaContent      = "content"
+	StorageS3ObjectSchemaContentType  = "content_type"
+	StorageS3ObjectSchemaCacheControl = "cache_control"
+	StorageS3ObjectSchemaETag         = "etag"
+
+	// storageS3ObjectDefaultContentType is the type of the objects with a
+	// key without a known extension.
+	storageS3ObjectDefaultContentType = "application/octet-stream"
+)
+
+func resourceStorageS3Object() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			StorageS3ObjectSchemaEndpoint: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "An S3 endpoint of the storage, generated_s3_endpoint of gcore_storage_s3.",
+			},
+			StorageS3ObjectSchemaRegion: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "A region of the requests signature, the first label of the endpoint host by default, e.g. s-ed1.",
+			},
+			StorageS3ObjectSchemaAccessKey: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "An access key of the storage, generated_access_key of gcore_storage_s3.",
+			},
+			StorageS3ObjectSchemaSecretKey: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "A secret key of the storage, generated_secret_key of gcore_storage_s3.",
+			},
+			StorageS3ObjectSchemaBucket: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "A name of the bucket of the storage.",
+			},
+			StorageS3ObjectSchemaKey: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "A key of the object in the bucket, e.g. static/app.js.",
+			},
+			StorageS3ObjectSchemaSource: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{StorageS3ObjectSchemaSource, StorageS3ObjectSchemaContent},
+				Description:  "A path of the local file to upload, a change of the file uploads it again.",
+			},
+			StorageS3ObjectSchemaContent: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A content of the object as a string.",
+			},
+			StorageS3ObjectSchemaContentType: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "A content type of the object, by the extension of the key by default.",
+			},
+			StorageS3ObjectSchemaCacheControl: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A Cache-Control header of the object, e.g. max-age=3600. CDN resources cache it for this long when they follow the origin.",
+			},
+			StorageS3ObjectSchemaETag: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "An ETag of the object, the hex MD5 of its content. A different ETag of the local content uploads it again.",
+			},
+		},
+		CreateContext: resourceStorageS3ObjectCreate,
+		ReadContext:   resourceStorageS3ObjectRead,
+		UpdateContext: resourceStorageS3ObjectUpdate,
+		DeleteContext: resourceStorageS3ObjectDelete,
+		CustomizeDiff: resourceStorageS3ObjectCustomizeDiff,
+		Description:   "Represent an object of a bucket of an S3 storage, uploaded from a local file or a string.",
+	}
+}
+
+func resourceStorageS3ObjectCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Start S3 Object Resource creating")
+
+	if err := putStorageS3Object(ctx, d, m); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(storageS3ObjectID(d.Get(StorageS3ObjectSchemaBucket).(string), d.Get(StorageS3ObjectSchemaKey).(string)))
+	log.Printf("[DEBUG] Finish S3 Object Resource creating (id=%s)\n", d.Id())
+
+	return resourceStorageS3ObjectRead(ctx, d, m)
+}
+
+func resourceStorageS3ObjectUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Start S3 Object Resource updating (id=%s)\n", d.Id())
+	defer log.Println("[DEBUG] Finish S3 Object Resource updating")
+
+	// A change of the keys only signs the next requests differently.
+	if d.HasChanges(StorageS3ObjectSchemaSource, StorageS3ObjectSchemaContent, StorageS3ObjectSchemaContentType,
+		StorageS3ObjectSchemaCacheControl, StorageS3ObjectSchemaETag) {
+		if err := putStorageS3Object(ctx, d, m); err != nil {
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+	}
+
+	if diags := resourceStorageS3ObjectRead(ctx, d, m); diags.HasError() {
+		// Wrong keys must not replace the working ones in the state.
+		d.Partial(true)
+		return diags
+	}
+	return nil
+}
+
+func resourceStorageS3ObjectRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Start S3 Object Resource reading (id=%s)\n", d.Id())
+	defer log.Println("[DEBUG] Finish S3 Object Resource reading")
+
+	client, err := storageS3ObjectClient(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	object, err := client.HeadObject(ctx, d.Get(StorageS3ObjectSchemaBucket).(string), d.Get(StorageS3ObjectSchemaKey).(string))
+	if err != nil {
+		if isStorageS3NotFound(err) {
+			log.Printf("[WARN] S3 object %s not found, removing it from the state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("head object: %w", err))
+	}
+	_ = d.Set(StorageS3ObjectSchemaETag, object.ETag)
+	_ = d.Set(StorageS3ObjectSchemaContentType, object.ContentType)
+	_ = d.Set(StorageS3ObjectSchemaCacheControl, object.CacheControl)
+
+	return nil
+}
+
+func resourceStorageS3ObjectDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Start S3 Object Resource deleting (id=%s)\n", d.Id())
+	defer log.Println("[DEBUG] Finish S3 Object Resource deleting")
+
+	client, err := storageS3ObjectClient(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = client.DeleteObject(ctx, d.Get(StorageS3ObjectSchemaBucket).(string), d.Get(StorageS3ObjectSchemaKey).(string))
+	if err != nil && !isStorageS3NotFound(err) {
+		return diag.FromErr(fmt.Errorf("delete object: %w", err))
+	}
+	d.SetId("")
+
+	return nil
+}
+
+// resourceStorageS3ObjectCustomizeDiff plans the ETag of the local content,
+// so a change of the source file or of the object in the bucket uploads the
+// content again.
+func resourceStorageS3ObjectCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown(StorageS3ObjectSchemaSource) || !d.NewValueKnown(StorageS3ObjectSchemaContent) {
+		return d.SetNewComputed(StorageS3ObjectSchemaETag)
+	}
+	body, err := openStorageS3ObjectBody(d.Get(StorageS3ObjectSchemaSource).(string), d.Get(StorageS3ObjectSchemaContent).(string))
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	etag, _, _, err := storageS3ObjectHashes(body)
+	if err != nil {
+		return err
+	}
+	if etag != d.Get(StorageS3ObjectSchemaETag).(string) {
+		return d.SetNew(StorageS3ObjectSchemaETag, etag)
+	}
+	return nil
+}
+
+// putStorageS3Object uploads the source or the content of the object.
+func putStorageS3Object(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	client, err := storageS3ObjectClient(d, m)
+	if err != nil {
+		return err
+	}
+	source, content := d.Get(StorageS3ObjectSchemaSource).(string), d.Get(StorageS3ObjectSchemaContent).(string)
+	body, err := openStorageS3ObjectBody(source, content)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	_, payloadHash, size, err := storageS3ObjectHashes(body)
+	if err != nil {
+		return err
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("read %s: %w", source, err)
+	}
+
+	key := d.Get(StorageS3ObjectSchemaKey).(string)
+	contentType := d.Get(StorageS3ObjectSchemaContentType).(string)
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(key))
+	}
+	if contentType == "" {
+		contentType = storageS3ObjectDefaultContentType
+	}
+	_, err = client.PutObject(ctx, d.Get(StorageS3ObjectSchemaBucket).(string), key, body, size, payloadHash,
+		contentType, d.Get(StorageS3ObjectSchemaCacheControl).(string))
+	if err != nil {
+		return fmt.Errorf("put object: %w", err)
+	}
+	return nil
+}
+
+func storageS3ObjectClient(d *schema.ResourceData, m interface{}) (*StorageS3Client, error) {
+	return newStorageS3Client(
+		m.(*Config).httpClient,
+		d.Get(StorageS3ObjectSchemaEndpoint).(string),
+		d.Get(StorageS3ObjectSchemaRegion).(string),
+		d.Get(StorageS3ObjectSchemaAccessKey).(string),
+		d.Get(StorageS3ObjectSchemaSecretKey).(string),
+	)
+}
+
+// storageS3ObjectBody is the content of an object, the file is read twice:
+// for the hashes and for the upload.
+type storageS3ObjectBody interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+type storageS3ObjectContent struct {
+	*bytes.Reader
+}
+
+func (storageS3ObjectContent) Close() error { return nil }
+
+func openStorageS3ObjectBody(source, content string) (storageS3ObjectBody, error) {
+	if source == "" {
+		return storageS3ObjectContent{bytes.NewReader([]byte(content))}, nil
+	}
+	f, err := os.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("open source: %w", err)
+	}
+	return f, nil
+}
+
+// storageS3ObjectHashes returns the hex MD5, which is the ETag of an object
+// uploaded at once, the hex SHA-256 and the size of the body.
+func storageS3ObjectHashes(body io.Reader) (string, string, int64, error) {
+	md5Hash, sha256Hash := md5.New(), sha256.New()
+	size, err := io.Copy(io.MultiWriter(md5Hash, sha256Hash), body)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("read source: %w", err)
+	}
+	return hex.EncodeToString(md5Hash.Sum(nil)), hex.EncodeToString(sha256Hash.Sum(nil)), size, nil
+}
+
+// storageS3ObjectID returns the ID of the object, bucket/key.
+func storageS3ObjectID(bucket, key string) string {
+	return strings.Join([]string{bucket, key}, "/")
+}
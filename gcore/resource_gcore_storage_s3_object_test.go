@@ -0,0 +1,147 @@
+//go:build !cloud
+// +build !cloud
+
+package gcore
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestUnitStorageS3Object(t *testing.T) {
+	api := newMockAPI(t)
+	storageID := api.storage.SeedStorage("s3", "s-ed1", "unit-s3")
+	api.storage.SeedBucket(storageID, "bucket", nil)
+	keys := mockMap(mockMap(api.storage.Storage(storageID)["credentials"])["s3"])
+	source := filepath.Join(t.TempDir(), "app.js")
+	const key = "static/app v1.js"
+	fullName := "gcore_storage_s3_object.test"
+
+	template := func(secretKey, extra string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_storage_s3_object" "test" {
+  s3_endpoint = "%s"
+  access_key  = "%s"
+  secret_key  = "%s"
+  bucket      = "bucket"
+  key         = "%s"
+  %s
+}
+`, api.S3Endpoint(), keys["access_key"], secretKey, key, extra)
+	}
+	secretKey := mockString(keys["secret_key"])
+	fromSource := template(secretKey, fmt.Sprintf(`
+  source       = "%s"
+  content_type = "text/plain"
+`, source))
+	etag := func(content string) string {
+		sum := md5.Sum([]byte(content))
+		return hex.EncodeToString(sum[:])
+	}
+	checkObject := func(content, contentType, cacheControl string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			o := api.storage.Object(storageID, "bucket", key)
+			if o == nil || string(o.Body) != content || o.ContentType != contentType || o.CacheControl != cacheControl {
+				return fmt.Errorf("unexpected object: %+v", o)
+			}
+			return nil
+		}
+	}
+	writeSource := func(content string) func() {
+		return func() {
+			if err := ioutil.WriteFile(source, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	jsType := mime.TypeByExtension(".js")
+
+	// The keys aren't known to the API of the object, so there is no import.
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(*terraform.State) error {
+			if o := api.storage.Object(storageID, "bucket", key); o != nil {
+				return fmt.Errorf("object still exists: %+v", o)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: template(secretKey, `
+  content       = "v1"
+  cache_control = "max-age=60"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "id", "bucket/"+key),
+					resource.TestCheckResourceAttr(fullName, "etag", etag("v1")),
+					resource.TestCheckResourceAttr(fullName, "content_type", jsType),
+					resource.TestCheckResourceAttr(fullName, "cache_control", "max-age=60"),
+					checkObject("v1", jsType, "max-age=60"),
+				),
+			},
+			{
+				Config: template(secretKey, `
+  content      = "v2"
+  content_type = "text/plain"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "etag", etag("v2")),
+					resource.TestCheckResourceAttr(fullName, "content_type", "text/plain"),
+					resource.TestCheckResourceAttr(fullName, "cache_control", ""),
+					checkObject("v2", "text/plain", ""),
+				),
+			},
+			{
+				// A new file is uploaded with the same config.
+				PreConfig: writeSource("file v1"),
+				Config:    fromSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "etag", etag("file v1")),
+					checkObject("file v1", "text/plain", ""),
+				),
+			},
+			{
+				PreConfig: writeSource("file v2"),
+				Config:    fromSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "etag", etag("file v2")),
+					checkObject("file v2", "text/plain", ""),
+				),
+			},
+			{
+				// An object changed in the bucket is uploaded again.
+				PreConfig: func() {
+					api.storage.SeedObject(storageID, "bucket", key, mockS3Object{Body: []byte("changed"), ContentType: "text/plain"})
+				},
+				Config: fromSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "etag", etag("file v2")),
+					checkObject("file v2", "text/plain", ""),
+				),
+			},
+			{
+				Config: template("wrong", fmt.Sprintf(`
+  source       = "%s"
+  content_type = "text/plain"
+`, source)),
+				ExpectError: regexp.MustCompile("403 Forbidden"),
+			},
+			{
+				Config:      template(secretKey, fmt.Sprintf(`source = "%s"`, filepath.Join(t.TempDir(), "missing.js"))),
+				ExpectError: regexp.MustCompile("open source"),
+			},
+			{
+				Config: fromSource,
+			},
+		},
+	})
+}
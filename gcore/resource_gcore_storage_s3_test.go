@@ -6,6 +6,9 @@ package gcore
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
 	"testing"
 	"time"
 
@@ -70,3 +73,184 @@ resource "gcore_storage_s3" "terraform_test_%d_s3" {
 		},
 	})
 }
+
+func TestUnitStorageS3(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_storage_s3.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitStorageCheckDestroy(api, "gcore_storage_s3"),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + `
+resource "gcore_storage_s3" "test" {
+  name     = "unit-s3"
+  location = "s-ed1"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "unit-s3"),
+					resource.TestCheckResourceAttr(fullName, "location", "s-ed1"),
+					resource.TestCheckResourceAttr(fullName, "client_id", strconv.Itoa(mockStorageClientID)),
+					resource.TestCheckResourceAttrSet(fullName, StorageSchemaId),
+					resource.TestCheckResourceAttrSet(fullName, StorageS3SchemaGenerateAccessKey),
+					resource.TestCheckResourceAttrSet(fullName, StorageS3SchemaGenerateSecretKey),
+					resource.TestCheckResourceAttrSet(fullName, StorageSchemaGenerateHTTPEndpoint),
+					resource.TestCheckResourceAttrSet(fullName, StorageSchemaGenerateS3Endpoint),
+				),
+			},
+			{
+				ResourceName:      fullName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The API returns the generated keys on create only.
+				ImportStateVerifyIgnore: []string{StorageS3SchemaGenerateAccessKey, StorageS3SchemaGenerateSecretKey},
+			},
+		},
+	})
+}
+
+func TestUnitStorageS3KeyRotation(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_storage_s3.test"
+
+	template := func(trigger string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_storage_s3" "test" {
+  name                = "unit-s3"
+  location            = "s-ed1"
+  rotate_keys_trigger = "%s"
+}
+`, trigger)
+	}
+
+	var storageID int
+	var accessKey, secretKey string
+	// checkKeys checks the keys of the state are the new keys of the same
+	// storage, and only they can read its objects.
+	checkKeys := func(rotated bool) resource.TestCheckFunc {
+		return unitCheckState(fullName, func(s *terraform.InstanceState) error {
+			id, _ := strconv.Atoi(s.Attributes[StorageSchemaId])
+			if !rotated {
+				storageID = id
+				api.storage.SeedBucket(storageID, "bucket", nil)
+				api.storage.SeedObject(storageID, "bucket", "index.html", mockS3Object{Body: []byte("<html/>"), ContentType: "text/html"})
+			} else if id != storageID {
+				return fmt.Errorf("storage %d was replaced by %d", storageID, id)
+			}
+
+			newAccess, newSecret := s.Attributes[StorageS3SchemaGenerateAccessKey], s.Attributes[StorageS3SchemaGenerateSecretKey]
+			keys := mockMap(mockMap(api.storage.Storage(storageID)["credentials"])["s3"])
+			if newAccess != keys["access_key"] || newSecret != keys["secret_key"] {
+				return fmt.Errorf("state keys %s don't match the storage keys %v", newAccess, keys)
+			}
+			if rotated && (newAccess == accessKey || newSecret == secretKey) {
+				return fmt.Errorf("keys weren't rotated")
+			}
+			if api.storage.Bucket(storageID, "bucket") == nil || api.storage.Object(storageID, "bucket", "index.html") == nil {
+				return fmt.Errorf("the bucket or its object is lost")
+			}
+
+			ctx := context.Background()
+			client, _ := newStorageS3Client(http.DefaultClient, api.S3Endpoint(), "s-ed1", newAccess, newSecret)
+			if _, err := client.HeadObject(ctx, "bucket", "index.html"); err != nil {
+				return fmt.Errorf("new keys: %w", err)
+			}
+			if rotated {
+				client, _ = newStorageS3Client(http.DefaultClient, api.S3Endpoint(), "s-ed1", accessKey, secretKey)
+				if _, err := client.HeadObject(ctx, "bucket", "index.html"); err == nil {
+					return fmt.Errorf("old keys still work")
+				}
+			}
+			accessKey, secretKey = newAccess, newSecret
+			return nil
+		})
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitStorageCheckDestroy(api, "gcore_storage_s3"),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + `
+resource "gcore_storage_s3" "test" {
+  name     = "unit-s3"
+  location = "s-ed1"
+}
+`,
+				Check: checkKeys(false),
+			},
+			{
+				Config: template("2026-10"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "rotate_keys_trigger", "2026-10"),
+					checkKeys(true),
+				),
+			},
+			{
+				PreConfig: func() {
+					api.FailNext(http.MethodPost, fmt.Sprintf("/storage/provisioning/v1/storage/%d/credentials", storageID), http.StatusBadRequest, "", 1)
+				},
+				Config:      template("2027-01"),
+				ExpectError: regexp.MustCompile("update creds"),
+			},
+			{
+				Config: template("2027-01"),
+				Check:  checkKeys(true),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{StorageS3SchemaGenerateAccessKey, StorageS3SchemaGenerateSecretKey, StorageS3SchemaRotateKeysTrigger},
+			},
+		},
+	})
+}
+
+func TestUnitStorageLocation(t *testing.T) {
+	api := newMockAPI(t)
+	api.storage.SeedLocation("s-new1", "s3", "allow")
+	api.storage.SeedLocation("s-old1", "s3", "deny")
+	fullName := "gcore_storage_s3.test"
+
+	template := func(location string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_storage_s3" "test" {
+  name     = "unit-s3"
+  location = "%s"
+}
+`, location)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitStorageCheckDestroy(api, "gcore_storage_s3"),
+		Steps: []resource.TestStep{
+			{
+				Config:      template("s-old1"),
+				ExpectError: regexp.MustCompile(`location "s-old1" is not available for new s3 storages, must be one of \[s-darz1 s-drc2 s-dt2 s-ed1 s-new1 s-ws1\]`),
+			},
+			{
+				Config:      template("ams"),
+				ExpectError: regexp.MustCompile(`location "ams" is not available for new s3 storages`),
+			},
+			{
+				// A location added to the API is available without a
+				// new release of the provider.
+				Config: template("s-new1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "location", "s-new1"),
+					resource.TestCheckResourceAttrSet(fullName, StorageSchemaGenerateS3Endpoint),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{StorageS3SchemaGenerateAccessKey, StorageS3SchemaGenerateSecretKey},
+			},
+		},
+	})
+}
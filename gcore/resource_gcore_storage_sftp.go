@@ -8,7 +8,6 @@ import (
 	"strconv"
 	"strings"
 
-	gstorage "github.com/G-Core/gcore-storage-sdk-go"
 	"github.com/G-Core/gcore-storage-sdk-go/swagger/client/key"
 
 	"github.com/G-Core/gcore-storage-sdk-go/swagger/client/storage"
@@ -82,24 +81,16 @@ func resourceStorageSFTP() *schema.Resource {
 				Description: "A expires date of storage resource.",
 			},
 			StorageSchemaLocation: {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				ValidateDiagFunc: func(v interface{}, path cty.Path) diag.Diagnostics {
-					val := v.(string)
-					allowed := []string{"ams", "sin", "fra", "mia"}
-					for _, el := range allowed {
-						if el == val {
-							return nil
-						}
-					}
-					return diag.Errorf(`must be one of %+v`, allowed)
-				},
-				Description: "A location of new storage resource. One of (ams, sin, fra, mia)",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "A location of new storage resource, e.g. ams. The locations of sftp storages are listed by the gcore_storage_locations data source.",
 			},
 			StorageSFTPSchemaSftpPassword: {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressSensitiveHashDiff,
 				ValidateDiagFunc: func(i interface{}, path cty.Path) diag.Diagnostics {
 					v := i.(string)
 					if len(v) > 63 || len(v) < 8 {
@@ -107,7 +98,7 @@ func resourceStorageSFTP() *schema.Resource {
 					}
 					return nil
 				},
-				Description: "A sftp password for new storage resource.",
+				Description: "A sftp password for new storage resource. Only its hash is kept in the state when the provider sets hash_sensitive_values, unless the password is generated.",
 			},
 			StorageSFTPSchemaGenerateSftpPassword: {
 				Type:        schema.TypeBool,
@@ -139,6 +130,7 @@ func resourceStorageSFTP() *schema.Resource {
 		ReadContext:   resourceStorageSFTPRead,
 		UpdateContext: resourceStorageSFTPUpdate,
 		DeleteContext: resourceStorageSFTPDelete,
+		CustomizeDiff: checkStorageLocation("sftp"),
 		Description:   "Represent sftp storage resource. https://storage.gcorelabs.com/storage/list",
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -146,7 +138,7 @@ func resourceStorageSFTP() *schema.Resource {
 	}
 }
 
-func resourceStorageValidateKeys(ctx context.Context, sdk *gstorage.SDK, d *schema.ResourceData) error {
+func resourceStorageValidateKeys(ctx context.Context, sdk *StorageClient, d *schema.ResourceData) error {
 	keyIds := d.Get(StorageSFTPSchemaKeyId).([]interface{})
 	if len(keyIds) == 0 {
 		return nil
@@ -172,7 +164,7 @@ func resourceStorageValidateKeys(ctx context.Context, sdk *gstorage.SDK, d *sche
 	return nil
 }
 
-func resourceStorageLinkKeys(ctx context.Context, sdk *gstorage.SDK, d *schema.ResourceData, storageID int64) error {
+func resourceStorageLinkKeys(ctx context.Context, sdk *StorageClient, d *schema.ResourceData, storageID int64) error {
 	keyIds := d.Get(StorageSFTPSchemaKeyId).([]interface{})
 	if len(keyIds) == 0 {
 		return nil
@@ -195,7 +187,7 @@ func resourceStorageLinkKeys(ctx context.Context, sdk *gstorage.SDK, d *schema.R
 	return nil
 }
 
-func resourceStorageRelinkKeys(ctx context.Context, sdk *gstorage.SDK, d *schema.ResourceData, storageID int64) error {
+func resourceStorageRelinkKeys(ctx context.Context, sdk *StorageClient, d *schema.ResourceData, storageID int64) error {
 	if !d.HasChange(StorageSFTPSchemaKeyId) {
 		return nil
 	}
@@ -356,6 +348,10 @@ func resourceStorageSFTPRead(ctx context.Context, d *schema.ResourceData, m inte
 		fmt.Sprintf("http://%s.%s.origin.gcdn.co", st.Name, st.Location))
 	_ = d.Set(StorageSchemaGenerateSFTPEndpoint,
 		fmt.Sprintf("ssh://%s@%s.origin.gcdn.co:2200", st.Name, st.Location))
+	// A generated password is known from the state only.
+	if !d.Get(StorageSFTPSchemaGenerateSftpPassword).(bool) {
+		hashSensitiveFields(d, config, StorageSFTPSchemaSftpPassword)
+	}
 
 	return nil
 }
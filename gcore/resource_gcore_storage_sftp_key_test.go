@@ -5,10 +5,12 @@ package gcore
 
 import (
 	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccStorageKey(t *testing.T) {
@@ -43,3 +45,45 @@ resource "gcore_storage_sftp_key" "%s" {
 		},
 	})
 }
+
+func TestUnitStorageSFTPKey(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_storage_sftp_key.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			for _, rs := range s.RootModule().Resources {
+				if rs.Type != "gcore_storage_sftp_key" {
+					continue
+				}
+				id, _ := strconv.Atoi(rs.Primary.ID)
+				if api.storage.Key(id) != nil {
+					return fmt.Errorf("key %s still exists", rs.Primary.ID)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + `
+resource "gcore_storage_sftp_key" "test" {
+  name = "unit-key"
+  key  = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDunit unit@example.com"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "unit-key"),
+					resource.TestCheckResourceAttrSet(fullName, "key_id"),
+				),
+			},
+			{
+				ResourceName:      fullName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The API never returns the public key.
+				ImportStateVerifyIgnore: []string{"key"},
+			},
+		},
+	})
+}
@@ -6,6 +6,9 @@ package gcore
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -88,3 +91,140 @@ resource "gcore_storage_sftp" "terraformtest%d_sftp" {
 		},
 	})
 }
+
+func TestUnitStorageSFTP(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_storage_sftp.test"
+
+	template := func(alias string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_storage_sftp" "test" {
+  name                  = "unit-sftp"
+  location              = "mia"
+  http_servername_alias = "%s"
+}
+`, alias)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitStorageCheckDestroy(api, "gcore_storage_sftp"),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + `
+resource "gcore_storage_sftp" "test" {
+  name     = "unit-sftp"
+  location = "s-ed1"
+}
+`,
+				ExpectError: regexp.MustCompile(`location "s-ed1" is not available for new sftp storages, must be one of \[ams fra mia sin\]`),
+			},
+			{
+				Config: template("first.example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "unit-sftp"),
+					resource.TestCheckResourceAttr(fullName, "location", "mia"),
+					resource.TestCheckResourceAttr(fullName, "http_servername_alias", "first.example.com"),
+				),
+			},
+			{
+				Config: template("second.example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "http_servername_alias", "second.example.com"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						id, _ := strconv.Atoi(s.Attributes[StorageSchemaId])
+						if alias := api.storage.Storage(id)["server_alias"]; alias != "second.example.com" {
+							return fmt.Errorf("server alias was not updated: %v", alias)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{StorageSFTPSchemaUpdateAfterCreate},
+			},
+		},
+	})
+}
+
+func TestUnitStorageSFTPPasswordHash(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_storage_sftp.test"
+
+	template := func(password string) string {
+		return api.providerHCL("hash_sensitive_values = true") + fmt.Sprintf(`
+resource "gcore_storage_sftp" "test" {
+  name     = "unit-sftp"
+  location = "mia"
+  password = "%s"
+}
+`, password)
+	}
+	checkPassword := func(password string) resource.TestCheckFunc {
+		return unitCheckState(fullName, func(s *terraform.InstanceState) error {
+			id, _ := strconv.Atoi(s.Attributes[StorageSchemaId])
+			if got := mockMap(api.storage.Storage(id)["credentials"])["sftp_password"]; got != password {
+				return fmt.Errorf("expected password %q sent to the API, got %v", password, got)
+			}
+			return nil
+		})
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitStorageCheckDestroy(api, "gcore_storage_sftp"),
+		Steps: []resource.TestStep{
+			{
+				Config: template("first-password"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, StorageSFTPSchemaSftpPassword, hashSensitiveValue("first-password")),
+					checkPassword("first-password"),
+				),
+			},
+			{
+				Config: template("second-password"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, StorageSFTPSchemaSftpPassword, hashSensitiveValue("second-password")),
+					checkPassword("second-password"),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{StorageSFTPSchemaUpdateAfterCreate, StorageSFTPSchemaSftpPassword},
+			},
+		},
+	})
+}
+
+func TestUnitSensitiveHashDiff(t *testing.T) {
+	hash := hashSensitiveValue("secret")
+	if !strings.HasPrefix(hash, sensitiveHashPrefix) || strings.Contains(hash, "secret") {
+		t.Fatalf("unexpected hash %q", hash)
+	}
+	if !suppressSensitiveHashDiff("password", hash, "secret", nil) {
+		t.Error("expected the diff between a secret and its hash suppressed")
+	}
+	if suppressSensitiveHashDiff("password", hash, "another", nil) {
+		t.Error("expected the diff of another secret kept")
+	}
+	if suppressSensitiveHashDiff("password", "secret", "another", nil) {
+		t.Error("expected the diff of a plain secret kept")
+	}
+
+	config := &Config{}
+	if got := sensitiveStateValue(config, "secret"); got != "secret" {
+		t.Errorf("expected the secret kept as is by default, got %q", got)
+	}
+	config.HashSensitiveValues = true
+	if got := sensitiveStateValue(config, "secret"); got != hash {
+		t.Errorf("expected the hash, got %q", got)
+	}
+	if got := sensitiveStateValue(config, hash); got != hash {
+		t.Errorf("expected a hash not hashed twice, got %q", got)
+	}
+}
@@ -28,7 +28,13 @@ func resourceSubnet() *schema.Resource {
 		ReadContext:   resourceSubnetRead,
 		UpdateContext: resourceSubnetUpdate,
 		DeleteContext: resourceSubnetDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent subnets. Subnetwork is a range of IP addresses in a cloud network. Addresses from this range will be assigned to machines in the cloud",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Duration(SubnetCreatingTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(SubnetCreatingTimeout) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(SubnetDeleting) * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, subnetID, err := ImportStringParser(d.Id())
@@ -46,36 +52,26 @@ func resourceSubnet() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -178,9 +174,8 @@ func resourceSubnetCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Println("[DEBUG] Start Subnet creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, subnetPoint, versionPointV1)
+	client, err := CreateClient(config, d, subnetPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -247,7 +242,7 @@ func resourceSubnetCreate(ctx context.Context, d *schema.ResourceData, m interfa
 
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	subnetID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, SubnetCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
+	subnetID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -276,11 +271,10 @@ func resourceSubnetRead(ctx context.Context, d *schema.ResourceData, m interface
 	log.Printf("[DEBUG] Start subnet reading%s", d.State())
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	subnetID := d.Id()
 	log.Printf("[DEBUG] Subnet id = %s", subnetID)
 
-	client, err := CreateClient(provider, d, subnetPoint, versionPointV1)
+	client, err := CreateClient(config, d, subnetPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -352,8 +346,7 @@ func resourceSubnetUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 	subnetID := d.Id()
 	log.Printf("[DEBUG] Subnet id = %s", subnetID)
 	config := m.(*Config)
-	provider := config.Provider
-	client, err := CreateClient(provider, d, subnetPoint, versionPointV1)
+	client, err := CreateClient(config, d, subnetPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -421,11 +414,10 @@ func resourceSubnetDelete(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Println("[DEBUG] Start subnet deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	subnetID := d.Id()
 	log.Printf("[DEBUG] Subnet id = %s", subnetID)
 
-	client, err := CreateClient(provider, d, subnetPoint, versionPointV1)
+	client, err := CreateClient(config, d, subnetPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -436,7 +428,7 @@ func resourceSubnetDelete(ctx context.Context, d *schema.ResourceData, m interfa
 	}
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, SubnetDeleting, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := subnets.Get(client, subnetID).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete subnet with ID: %s", subnetID)
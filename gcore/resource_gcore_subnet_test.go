@@ -245,3 +245,60 @@ func testAccSubnetDestroy(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitSubnet(t *testing.T) {
+	api := newMockAPI(t)
+	networkID := api.cloud.Seed("networks", map[string]interface{}{"name": "network"})
+	fullName := "gcore_subnet.test"
+
+	template := func(name, dns string) string {
+		return api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_subnet" "test" {
+  %s
+  name                      = "%s"
+  cidr                      = "192.168.10.0/24"
+  network_id                = "%s"
+  connect_to_network_router = true
+  dns_nameservers           = %s
+
+  host_routes {
+    destination = "10.0.3.0/24"
+    nexthop     = "10.0.0.13"
+  }
+}
+`, mockCloudScope, name, networkID, dns)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_subnet", "subnets"),
+		Steps: []resource.TestStep{
+			{
+				Config: template("subnet", `["8.8.4.4"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "subnet"),
+					resource.TestCheckResourceAttr(fullName, "cidr", "192.168.10.0/24"),
+					resource.TestCheckResourceAttr(fullName, "network_id", networkID),
+					resource.TestCheckResourceAttr(fullName, "gateway_ip", "192.168.10.1"),
+					resource.TestCheckResourceAttr(fullName, "dns_nameservers.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "host_routes.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "connect_to_network_router", "true"),
+				),
+			},
+			{
+				Config: template("subnet-renamed", `["8.8.4.4", "1.1.1.1"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "subnet-renamed"),
+					resource.TestCheckResourceAttr(fullName, "dns_nameservers.#", "2"),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "connect_to_network_router", "project_name", "region_name"},
+			},
+		},
+	})
+}
@@ -27,7 +27,13 @@ func resourceVolume() *schema.Resource {
 		ReadContext:   resourceVolumeRead,
 		UpdateContext: resourceVolumeUpdate,
 		DeleteContext: resourceVolumeDelete,
+		CustomizeDiff: cloudScopeDiff,
 		Description:   "Represent volume. A volume is a file storage which is similar to SSD and HDD hard disks but located in the cloud",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Duration(volumeCreatingTimeout) * time.Second),
+			Update: schema.DefaultTimeout(time.Duration(volumeExtending) * time.Second),
+			Delete: schema.DefaultTimeout(time.Duration(volumeDeleting) * time.Second),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				projectID, regionID, volumeID, err := ImportStringParser(d.Id())
@@ -40,9 +46,8 @@ func resourceVolume() *schema.Resource {
 				d.SetId(volumeID)
 
 				config := meta.(*Config)
-				provider := config.Provider
 
-				client, err := CreateClient(provider, d, volumesPoint, versionPointV1)
+				client, err := CreateClient(config, d, volumesPoint, versionPointV1)
 				if err != nil {
 					return nil, err
 				}
@@ -59,36 +64,26 @@ func resourceVolume() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"project_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"project_name"},
 			},
 			"region_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"region_name"},
 			},
 			"project_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"project_id",
-					"project_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
 			},
 			"region_name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ExactlyOneOf: []string{
-					"region_id",
-					"region_name",
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"region_id"},
 			},
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -157,9 +152,8 @@ func resourceVolumeCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Println("[DEBUG] Start volume creating")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 
-	client, err := CreateClient(provider, d, volumesPoint, versionPointV1)
+	client, err := CreateClient(config, d, volumesPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -175,7 +169,7 @@ func resourceVolumeCreate(ctx context.Context, d *schema.ResourceData, m interfa
 
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	VolumeID, err := tasks.WaitTaskAndReturnResult(client, taskID, true, volumeCreatingTimeout, func(task tasks.TaskID) (interface{}, error) {
+	VolumeID, err := waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutCreate), func(task tasks.TaskID) (interface{}, error) {
 		taskInfo, err := tasks.Get(client, string(task)).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", task, err)
@@ -224,11 +218,10 @@ func resourceVolumeRead(ctx context.Context, d *schema.ResourceData, m interface
 	log.Printf("[DEBUG] Start volume reading%s", d.State())
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	volumeID := d.Id()
 	log.Printf("[DEBUG] Volume id = %s", volumeID)
 
-	client, err := CreateClient(provider, d, volumesPoint, versionPointV1)
+	client, err := CreateClient(config, d, volumesPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -266,8 +259,7 @@ func resourceVolumeUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 	volumeID := d.Id()
 	log.Printf("[DEBUG] Volume id = %s", volumeID)
 	config := m.(*Config)
-	provider := config.Provider
-	client, err := CreateClient(provider, d, volumesPoint, versionPointV1)
+	client, err := CreateClient(config, d, volumesPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -290,7 +282,7 @@ func resourceVolumeUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 		newSize := newValue.(int)
 		if newSize != 0 {
 			if volume.Size < newSize {
-				err = ExtendVolume(client, volumeID, newSize)
+				err = ExtendVolume(ctx, client, volumeID, newSize, d.Timeout(schema.TimeoutUpdate))
 				if err != nil {
 					return diag.FromErr(err)
 				}
@@ -339,11 +331,10 @@ func resourceVolumeDelete(ctx context.Context, d *schema.ResourceData, m interfa
 	log.Println("[DEBUG] Start volume deleting")
 	var diags diag.Diagnostics
 	config := m.(*Config)
-	provider := config.Provider
 	volumeID := d.Id()
 	log.Printf("[DEBUG] Volume id = %s", volumeID)
 
-	client, err := CreateClient(provider, d, volumesPoint, versionPointV1)
+	client, err := CreateClient(config, d, volumesPoint, versionPointV1)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -357,7 +348,7 @@ func resourceVolumeDelete(ctx context.Context, d *schema.ResourceData, m interfa
 	}
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, volumeDeleting, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, d.Timeout(schema.TimeoutDelete), func(task tasks.TaskID) (interface{}, error) {
 		_, err := volumes.Get(client, volumeID).Extract()
 		if err == nil {
 			return nil, fmt.Errorf("cannot delete volume with ID: %s", volumeID)
@@ -420,7 +411,7 @@ func getVolumeData(d *schema.ResourceData) (*volumes.CreateOpts, error) {
 	return &volumeData, nil
 }
 
-func ExtendVolume(client *gcorecloud.ServiceClient, volumeID string, newSize int) error {
+func ExtendVolume(ctx context.Context, client *gcorecloud.ServiceClient, volumeID string, newSize int, timeout time.Duration) error {
 	opts := volumes.SizePropertyOperationOpts{
 		Size: newSize,
 	}
@@ -431,7 +422,7 @@ func ExtendVolume(client *gcorecloud.ServiceClient, volumeID string, newSize int
 
 	taskID := results.Tasks[0]
 	log.Printf("[DEBUG] Task id (%s)", taskID)
-	_, err = tasks.WaitTaskAndReturnResult(client, taskID, true, volumeExtending, func(task tasks.TaskID) (interface{}, error) {
+	_, err = waitTask(ctx, client, taskID, timeout, func(task tasks.TaskID) (interface{}, error) {
 		_, err := volumes.Get(client, volumeID).Extract()
 		if err != nil {
 			return nil, fmt.Errorf("cannot get volume with ID: %s. Error: %w", volumeID, err)
@@ -5,11 +5,12 @@ package gcore
 
 import (
 	"fmt"
-	"github.com/G-Core/gcorelabscloud-go/gcore/utils/metadata"
 	"os"
 	"strconv"
 	"testing"
 
+	"github.com/G-Core/gcorelabscloud-go/gcore/utils/metadata"
+
 	"github.com/G-Core/gcorelabscloud-go/gcore/network/v1/networks"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -155,3 +156,68 @@ func setNonStateMeta(s *terraform.State) error {
 
 	return nil
 }
+
+func TestUnitVolume(t *testing.T) {
+	api := newMockAPI(t)
+	fullName := "gcore_volume.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      unitCloudCheckDestroy(api, "gcore_volume", "volumes"),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_volume" "test" {
+  %s
+  name         = "volume"
+  size         = 2
+  type_name    = "standard"
+  metadata_map = {
+    env = "test"
+  }
+}
+`, mockCloudScope),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "volume"),
+					resource.TestCheckResourceAttr(fullName, "size", "2"),
+					resource.TestCheckResourceAttr(fullName, "type_name", "standard"),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.env", "test"),
+					resource.TestCheckResourceAttr(fullName, "metadata_read_only.#", "1"),
+					resource.TestCheckResourceAttr(fullName, "metadata_read_only.0.key", "env"),
+				),
+			},
+			{
+				Config: api.providerHCL("") + fmt.Sprintf(`
+resource "gcore_volume" "test" {
+  %s
+  name         = "volume-renamed"
+  size         = 3
+  type_name    = "ssd_hiiops"
+  metadata_map = {
+    env = "prod"
+  }
+}
+`, mockCloudScope),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullName, "name", "volume-renamed"),
+					resource.TestCheckResourceAttr(fullName, "size", "3"),
+					resource.TestCheckResourceAttr(fullName, "type_name", "ssd_hiiops"),
+					resource.TestCheckResourceAttr(fullName, "metadata_map.env", "prod"),
+					unitCheckState(fullName, func(s *terraform.InstanceState) error {
+						if v := api.cloud.Get("volumes", s.ID); mockInt(v["size"]) != 3 {
+							return fmt.Errorf("volume was not extended: %v", v["size"])
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:            fullName,
+				ImportState:             true,
+				ImportStateIdFunc:       unitCloudImportID(fullName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated", "image_id", "project_name", "region_name"},
+			},
+		},
+	})
+}
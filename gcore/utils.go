@@ -1,21 +1,25 @@
 package gcore
 
 import (
+	"context"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net"
+	"net/http"
 	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	dnssdk "github.com/G-Core/gcore-dns-sdk-go"
-	storageSDK "github.com/G-Core/gcore-storage-sdk-go"
-	gcdn "github.com/G-Core/gcorelabscdn-go"
 	gcorecloud "github.com/G-Core/gcorelabscloud-go"
 	gc "github.com/G-Core/gcorelabscloud-go/gcore"
 	"github.com/G-Core/gcorelabscloud-go/gcore/ddos/v1/ddos"
@@ -32,6 +36,7 @@ import (
 	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/securitygroups"
 	typesSG "github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/types"
 	"github.com/G-Core/gcorelabscloud-go/gcore/subnet/v1/subnets"
+	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -48,9 +53,262 @@ const (
 
 type Config struct {
 	Provider      *gcorecloud.ProviderClient
-	CDNClient     gcdn.ClientService
-	StorageClient *storageSDK.SDK
+	CDNClient     *CDNClient
+	StorageClient *StorageClient
 	DNSClient     *dnssdk.Client
+
+	// Default project and region of the cloud resources that set neither
+	// the ID nor the name.
+	DefaultProjectID   int
+	DefaultProjectName string
+	DefaultRegionID    int
+	DefaultRegionName  string
+
+	// HashSensitiveValues keeps only hashes of the secrets the API never
+	// returns in the state, see hashSensitiveFields.
+	HashSensitiveValues bool
+
+	// httpClient sends the requests of the clients built by the resources,
+	// e.g. the S3 one, with the retry policy and the User-Agent of the
+	// provider.
+	httpClient *http.Client
+
+	// cache is shared by all resources and data sources of the provider.
+	cache *clientCache
+}
+
+// clientCache keeps the project and region IDs resolved by name and the
+// service clients built by CreateClient, so a run lists projects and regions
+// once instead of on every CRUD call.
+//
+// projects and regions are listed under their own locks: only the callers
+// which need the list wait for the request, the service clients are built
+// under mu, which is never held across a network call.
+type clientCache struct {
+	projectsMu sync.Mutex
+	projects   map[string]int
+
+	regionsMu sync.Mutex
+	regions   []regions.Region
+
+	mu      sync.Mutex
+	clients map[gcorecloud.EndpointOpts]*gcorecloud.ServiceClient
+}
+
+func newClientCache() *clientCache {
+	return &clientCache{
+		clients: make(map[gcorecloud.EndpointOpts]*gcorecloud.ServiceClient),
+	}
+}
+
+// projectID returns projectID when it's set, otherwise the ID of the project
+// named projectName.
+func (c *Config) projectID(projectID int, projectName string) (int, error) {
+	if projectID != 0 || c.cache == nil {
+		return GetProject(c.Provider, projectID, projectName)
+	}
+
+	c.cache.projectsMu.Lock()
+	defer c.cache.projectsMu.Unlock()
+	if c.cache.projects == nil {
+		client, err := gc.ClientServiceFromProvider(c.Provider, gcorecloud.EndpointOpts{
+			Name:    projectPoint,
+			Version: versionPointV1,
+		})
+		if err != nil {
+			return 0, err
+		}
+		ps, err := projects.ListAll(client)
+		if err != nil {
+			return 0, err
+		}
+		c.cache.projects = make(map[string]int, len(ps))
+		for _, p := range ps {
+			if _, ok := c.cache.projects[p.Name]; !ok {
+				c.cache.projects[p.Name] = p.ID
+			}
+		}
+	}
+
+	id, ok := c.cache.projects[projectName]
+	if !ok {
+		return 0, fmt.Errorf("project with name %s not found", projectName)
+	}
+	return id, nil
+}
+
+// regionID returns regionID when it's set, otherwise the ID of the region
+// with the regionName display name.
+func (c *Config) regionID(regionID int, regionName string) (int, error) {
+	if regionID != 0 || c.cache == nil {
+		return GetRegion(c.Provider, regionID, regionName)
+	}
+
+	rs, err := c.listRegions()
+	if err != nil {
+		return 0, err
+	}
+	return findRegionByName(rs, regionName)
+}
+
+// listRegions returns the regions available to the provider, they're listed
+// once and reused afterwards.
+func (c *Config) listRegions() ([]regions.Region, error) {
+	if c.cache == nil {
+		return listAllRegions(c.Provider)
+	}
+
+	c.cache.regionsMu.Lock()
+	defer c.cache.regionsMu.Unlock()
+	if c.cache.regions == nil {
+		rs, err := listAllRegions(c.Provider)
+		if err != nil {
+			return nil, err
+		}
+		c.cache.regions = rs
+	}
+	return c.cache.regions, nil
+}
+
+func listAllRegions(provider *gcorecloud.ProviderClient) ([]regions.Region, error) {
+	client, err := gc.ClientServiceFromProvider(provider, gcorecloud.EndpointOpts{
+		Name:    regionPoint,
+		Version: versionPointV1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return regions.ListAll(client)
+}
+
+// cloudScopeDiff is the CustomizeDiff of the cloud resources: it checks the
+// region at plan time and replaces the resources which follow a provider
+// default project or region once the default changes.
+func cloudScopeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := validateRegionDiff(ctx, d, m); err != nil {
+		return err
+	}
+	return defaultScopeDiff(ctx, d, m)
+}
+
+// defaultScopeDiff plans the replacement of an existing resource which sets
+// neither the ID nor the name of its project or region when the provider
+// default resolves to another one than the resource lives in. project_id and
+// region_id are computed, so the plan would be empty otherwise and the
+// resource would be kept, and read, in the old project or region.
+func defaultScopeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	raw := d.GetRawConfig()
+	if !raw.IsKnown() || raw.IsNull() {
+		return nil
+	}
+
+	config := m.(*Config)
+	scopes := []struct {
+		idKey, nameKey string
+		defaultID      int
+		defaultName    string
+		resolve        func(int, string) (int, error)
+	}{
+		{"project_id", "project_name", config.DefaultProjectID, config.DefaultProjectName, config.projectID},
+		{"region_id", "region_name", config.DefaultRegionID, config.DefaultRegionName, config.regionID},
+	}
+	for _, scope := range scopes {
+		if !raw.Type().HasAttribute(scope.idKey) || !raw.Type().HasAttribute(scope.nameKey) {
+			continue
+		}
+		if !raw.GetAttr(scope.idKey).IsNull() || !raw.GetAttr(scope.nameKey).IsNull() {
+			continue
+		}
+		if scope.defaultID == 0 && scope.defaultName == "" {
+			continue
+		}
+
+		id, err := scope.resolve(scope.defaultID, scope.defaultName)
+		if err != nil {
+			return fmt.Errorf("cannot resolve the provider default %s: %w", scope.idKey, err)
+		}
+		current := d.Get(scope.idKey).(int)
+		if current == 0 || current == id {
+			continue
+		}
+		log.Printf("[DEBUG] provider default %s changed from %d to %d, %s is replaced", scope.idKey, current, id, d.Id())
+		if err := d.SetNew(scope.idKey, id); err != nil {
+			return err
+		}
+		if err := d.ForceNew(scope.idKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRegionDiff checks at plan time that the region the resource is
+// going to use exists, the provider default region is checked when the
+// resource sets neither region_id nor region_name. region_id is computed, so
+// it's unknown on create when it isn't set.
+func validateRegionDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("region_name") {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChange("region_id") && !d.HasChange("region_name") {
+		return nil
+	}
+
+	config := m.(*Config)
+	regionName := d.Get("region_name").(string)
+	var regionID int
+	if regionName == "" {
+		if d.NewValueKnown("region_id") {
+			regionID = d.Get("region_id").(int)
+		}
+		if regionID == 0 {
+			regionID, regionName = config.DefaultRegionID, config.DefaultRegionName
+		}
+		if regionID == 0 && regionName == "" {
+			// CreateClient reports the missing region when it's not set at
+			// all, an unknown region_id is checked on apply.
+			return nil
+		}
+	}
+
+	rs, err := config.listRegions()
+	if err != nil {
+		return fmt.Errorf("cannot list regions: %w", err)
+	}
+	available := make([]string, 0, len(rs))
+	for _, r := range rs {
+		if (regionName != "" && r.DisplayName == regionName) || (regionName == "" && r.ID == regionID) {
+			return nil
+		}
+		available = append(available, fmt.Sprintf("%s (%d)", r.DisplayName, r.ID))
+	}
+	if regionName != "" {
+		return fmt.Errorf("region with name %s not found, available regions: %s", regionName, strings.Join(available, ", "))
+	}
+	return fmt.Errorf("region with ID %d not found, available regions: %s", regionID, strings.Join(available, ", "))
+}
+
+// serviceClient returns the service client for opts, it's built once and
+// reused afterwards.
+func (c *Config) serviceClient(opts gcorecloud.EndpointOpts) (*gcorecloud.ServiceClient, error) {
+	if c.cache == nil {
+		return gc.ClientServiceFromProvider(c.Provider, opts)
+	}
+
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+	if client, ok := c.cache.clients[opts]; ok {
+		return client, nil
+	}
+	client, err := gc.ClientServiceFromProvider(c.Provider, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.clients[opts] = client
+	return client, nil
 }
 
 type Project struct {
@@ -378,17 +636,8 @@ func GetRegion(provider *gcorecloud.ProviderClient, regionID int, regionName str
 	if regionID != 0 {
 		return regionID, nil
 	}
-	client, err := gc.ClientServiceFromProvider(provider, gcorecloud.EndpointOpts{
-		Name:    regionPoint,
-		Region:  0,
-		Project: 0,
-		Version: "v1",
-	})
-	if err != nil {
-		return 0, err
-	}
 
-	rs, err := regions.ListAll(client)
+	rs, err := listAllRegions(provider)
 	if err != nil {
 		return 0, err
 	}
@@ -440,33 +689,105 @@ func ImportStringParserExtended(infoStr string) (int, int, string, string, error
 	return projectID, regionID, infoStrings[2], infoStrings[3], nil
 }
 
-func CreateClient(provider *gcorecloud.ProviderClient, d *schema.ResourceData, endpoint string, version string) (*gcorecloud.ServiceClient, error) {
-	projectID, err := GetProject(provider, d.Get("project_id").(int), d.Get("project_name").(string))
+// taskPollInterval is the delay between checks of a task state.
+var taskPollInterval = time.Second
+
+// waitTask waits for the task to be finished and returns the result of
+// retrieveResult, a nil retrieveResult only waits. It stops waiting with an
+// error when the task fails, the timeout elapses or ctx is canceled.
+func waitTask(ctx context.Context, client *gcorecloud.ServiceClient, taskID tasks.TaskID, timeout time.Duration, retrieveResult tasks.RetrieveTaskResult) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(taskPollInterval)
+	defer ticker.Stop()
+	for {
+		task, err := tasks.Get(client, string(taskID)).Extract()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get task with ID: %s. Error: %w", taskID, err)
+		}
+
+		switch {
+		case task.State == tasks.TaskStateFinished:
+			if retrieveResult == nil {
+				return nil, nil
+			}
+			return retrieveResult(taskID)
+		case task.State == tasks.TaskStateError:
+			errorText := ""
+			if task.Error != nil {
+				errorText = *task.Error
+			}
+			return nil, fmt.Errorf("task %s is in error state: %s. Error: %s", taskID, task.State, errorText)
+		case task.Error != nil:
+			return nil, fmt.Errorf("task %s is in error state: %s", taskID, *task.Error)
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, fmt.Errorf("timeout while waiting for task %s after %s", taskID, timeout)
+			}
+			return nil, fmt.Errorf("waiting for task %s: %w", taskID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// cloudScope returns the project and region IDs of the resource. A name set
+// on the resource wins over an ID, which may come from the state, and the
+// provider defaults are used when the resource sets neither. regionID is 0
+// for resources without region fields.
+func cloudScope(config *Config, d *schema.ResourceData) (projectID int, regionID int, err error) {
+	projectName := d.Get("project_name").(string)
+	projectID = d.Get("project_id").(int)
+	switch {
+	case projectName != "":
+		projectID, err = config.projectID(0, projectName)
+	case projectID != 0:
+	case config.DefaultProjectID != 0 || config.DefaultProjectName != "":
+		projectID, err = config.projectID(config.DefaultProjectID, config.DefaultProjectName)
+	default:
+		err = fmt.Errorf("project_id or project_name must be set on the resource or the provider")
+	}
 	if err != nil {
-		return nil, err
+		return 0, 0, err
 	}
 
-	var regionID int
 	rawRegionID := d.Get("region_id")
 	rawRegionName := d.Get("region_name")
-	if rawRegionID != nil && rawRegionName != nil {
-		regionID, err = GetRegion(provider, rawRegionID.(int), rawRegionName.(string))
-		if err != nil {
-			return nil, err
-		}
+	if rawRegionID == nil || rawRegionName == nil {
+		return projectID, 0, nil
+	}
+	regionName := rawRegionName.(string)
+	regionID = rawRegionID.(int)
+	switch {
+	case regionName != "":
+		regionID, err = config.regionID(0, regionName)
+	case regionID != 0:
+	case config.DefaultRegionID != 0 || config.DefaultRegionName != "":
+		regionID, err = config.regionID(config.DefaultRegionID, config.DefaultRegionName)
+	default:
+		err = fmt.Errorf("region_id or region_name must be set on the resource or the provider")
+	}
+	if err != nil {
+		return 0, 0, err
 	}
+	return projectID, regionID, nil
+}
 
-	client, err := gc.ClientServiceFromProvider(provider, gcorecloud.EndpointOpts{
+func CreateClient(config *Config, d *schema.ResourceData, endpoint string, version string) (*gcorecloud.ServiceClient, error) {
+	projectID, regionID, err := cloudScope(config, d)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.serviceClient(gcorecloud.EndpointOpts{
 		Name:    endpoint,
 		Region:  regionID,
 		Project: projectID,
 		Version: version,
 	})
-
-	if err != nil {
-		return nil, err
-	}
-	return client, nil
 }
 
 func revertState(d *schema.ResourceData, fields *[]string) {
@@ -592,6 +913,52 @@ func validatePortRange(v interface{}, path cty.Path) diag.Diagnostics {
 	return diag.Errorf("available range %d-%d", minPort, maxPort)
 }
 
+func validateDuration(v interface{}, path cty.Path) diag.Diagnostics {
+	d, err := time.ParseDuration(v.(string))
+	if err != nil {
+		return diag.Errorf("invalid duration %q: %s", v, err)
+	}
+	if d < 0 {
+		return diag.Errorf("duration %q is negative", v)
+	}
+	return nil
+}
+
+// sensitiveHashPrefix marks a secret kept in the state as its hash.
+const sensitiveHashPrefix = "sha256:"
+
+func hashSensitiveValue(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return sensitiveHashPrefix + hex.EncodeToString(sum[:])
+}
+
+// suppressSensitiveHashDiff hides the diff between a secret in the config and
+// its hash in the state. The diff of another secret is kept, so a changed
+// secret is still sent to the API.
+func suppressSensitiveHashDiff(k, old, new string, d *schema.ResourceData) bool {
+	return new != "" && old == hashSensitiveValue(new)
+}
+
+// sensitiveStateValue returns the value of a secret to keep in the state,
+// its hash when the provider sets hash_sensitive_values.
+func sensitiveStateValue(config *Config, v string) string {
+	if !config.HashSensitiveValues || v == "" || strings.HasPrefix(v, sensitiveHashPrefix) {
+		return v
+	}
+	return hashSensitiveValue(v)
+}
+
+// hashSensitiveFields replaces the secrets in the state with their hashes
+// when the provider sets hash_sensitive_values. It's called by Read, the
+// secrets of existing resources are hashed on the next refresh.
+func hashSensitiveFields(d *schema.ResourceData, config *Config, fields ...string) {
+	for _, field := range fields {
+		if v := d.Get(field).(string); sensitiveStateValue(config, v) != v {
+			d.Set(field, sensitiveStateValue(config, v))
+		}
+	}
+}
+
 func extractSecurityGroupRuleMap(r interface{}, gid string) securitygroups.CreateRuleOptsBuilder {
 	rule := r.(map[string]interface{})
 	opts := securitygroups.CreateSecurityGroupRuleOpts{
@@ -613,6 +980,11 @@ func extractSecurityGroupRuleMap(r interface{}, gid string) securitygroups.Creat
 	if remoteIPPrefix != "" {
 		opts.RemoteIPPrefix = &remoteIPPrefix
 	}
+
+	// Only rules of gcore_securitygroup_rule have remote_group_id.
+	if remoteGroupID, _ := rule["remote_group_id"].(string); remoteGroupID != "" {
+		opts.RemoteGroupID = &remoteGroupID
+	}
 	return opts
 }
 
@@ -677,50 +1049,84 @@ func parseCIDRFromString(cidr string) (gcorecloud.CIDR, error) {
 	return gccidr, nil
 }
 
-func isInterfaceAttached(ifs []instances.Interface, ifs2 map[string]interface{}) bool {
-	subnetID, _ := ifs2["subnet_id"].(string)
-	iType := types.InterfaceType(ifs2["type"].(string))
-	for _, i := range ifs {
-		if iType == types.ExternalInterfaceType && i.NetworkDetails.External {
-			return true
-		}
-		for _, assignement := range i.IPAssignments {
-			if assignement.SubnetID == subnetID {
-				return true
+// instanceInterfaceKey returns the attributes identifying an interface of an
+// instance: its type and the subnet, network or port it is attached to.
+// The floating IP and the ip_address, which is assigned by the API, are not
+// part of it, the floating IP of a kept interface is updated in place.
+func instanceInterfaceKey(iface map[string]interface{}) string {
+	iType, _ := iface["type"].(string)
+	var id string
+	switch types.InterfaceType(iType) {
+	case types.SubnetInterfaceType:
+		id, _ = iface["subnet_id"].(string)
+	case types.AnySubnetInterfaceType:
+		id, _ = iface["network_id"].(string)
+	case types.ReservedFixedIpType:
+		id, _ = iface["port_id"].(string)
+	}
+	return iType + ":" + id
+}
+
+// instanceInterfacePair is an interface kept by an update with its old and
+// new configuration.
+type instanceInterfacePair struct {
+	Old map[string]interface{}
+	New map[string]interface{}
+}
+
+// diffInstanceInterfaces matches old and new interfaces by
+// instanceInterfaceKey. It returns the old interfaces which have to be
+// detached, the new ones which have to be attached and the kept ones.
+func diffInstanceInterfaces(ifsOld, ifsNew []interface{}) (detach, attach []interface{}, kept []instanceInterfacePair) {
+	matched := make([]bool, len(ifsNew))
+	for _, o := range ifsOld {
+		oldIface := o.(map[string]interface{})
+		key := instanceInterfaceKey(oldIface)
+		found := false
+		for i, n := range ifsNew {
+			newIface := n.(map[string]interface{})
+			if matched[i] || instanceInterfaceKey(newIface) != key {
+				continue
 			}
+			matched[i] = true
+			found = true
+			kept = append(kept, instanceInterfacePair{Old: oldIface, New: newIface})
+			break
 		}
-		for _, subPort := range i.SubPorts {
-			if iType == types.ExternalInterfaceType && subPort.NetworkDetails.External {
-				return true
-			}
-			for _, assignement := range subPort.IPAssignments {
-				if assignement.SubnetID == subnetID {
-					return true
-				}
-			}
+		if !found {
+			detach = append(detach, oldIface)
+		}
+	}
+	for i, n := range ifsNew {
+		if !matched[i] {
+			attach = append(attach, n)
 		}
 	}
-	return false
+	return detach, attach, kept
 }
 
-func isInterfaceContains(verifiable map[string]interface{}, ifsSet []interface{}) bool {
-	verifiableType := verifiable["type"].(string)
-	verifiableSubnetID, _ := verifiable["subnet_id"].(string)
-	for _, e := range ifsSet {
-		i := e.(map[string]interface{})
-		iType := i["type"].(string)
-		subnetID, _ := i["subnet_id"].(string)
-		if iType == types.ExternalInterfaceType.String() && verifiableType == types.ExternalInterfaceType.String() {
-			return true
+// diffInterfaceSecurityGroups returns the security group IDs to remove from
+// and to add to a kept interface.
+func diffInterfaceSecurityGroups(pair instanceInterfacePair) (removed, added []string) {
+	oldSGs, _ := pair.Old["security_groups"].([]interface{})
+	newSGs, _ := pair.New["security_groups"].([]interface{})
+	oldSet := make(map[string]bool, len(oldSGs))
+	for _, sg := range oldSGs {
+		oldSet[sg.(string)] = true
+	}
+	newSet := make(map[string]bool, len(newSGs))
+	for _, sg := range newSGs {
+		newSet[sg.(string)] = true
+		if !oldSet[sg.(string)] {
+			added = append(added, sg.(string))
 		}
-
-		if iType == verifiableType {
-			if subnetID == verifiableSubnetID {
-				return true
-			}
+	}
+	for _, sg := range oldSGs {
+		if !newSet[sg.(string)] {
+			removed = append(removed, sg.(string))
 		}
 	}
-	return false
+	return removed, added
 }
 
 func extractListenerIntoMap(listener *listeners.Listener) map[string]interface{} {
@@ -1,6 +1,19 @@
 package gcore
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	gc "github.com/G-Core/gcorelabscloud-go/gcore"
+	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
 
 func TestExtractHosAndPath(t *testing.T) {
 	type args struct {
@@ -57,3 +70,105 @@ func TestExtractHosAndPath(t *testing.T) {
 		})
 	}
 }
+
+func TestUnitWaitTask(t *testing.T) {
+	api := newMockAPI(t)
+
+	client, err := gc.ClientServiceFromProvider(unitProviderConfig(t, api, nil).Provider, gcorecloud.EndpointOpts{
+		Name:    "tasks",
+		Region:  mockRegionID,
+		Project: mockProjectID,
+		Version: versionPointV1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := func(tasks.TaskID) (interface{}, error) { return "done", nil }
+
+	t.Run("finished", func(t *testing.T) {
+		taskID := tasks.TaskID(api.cloud.SeedTask("FINISHED", ""))
+		res, err := waitTask(context.Background(), client, taskID, time.Minute, result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != "done" {
+			t.Fatalf("expected the retrieved result, got %v", res)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		taskID := tasks.TaskID(api.cloud.SeedTask("ERROR", "quota exceeded"))
+		_, err := waitTask(context.Background(), client, taskID, time.Minute, result)
+		if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+			t.Fatalf("expected the task error, got %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		taskID := tasks.TaskID(api.cloud.SeedTask("RUNNING", ""))
+		_, err := waitTask(context.Background(), client, taskID, 10*time.Millisecond, result)
+		if err == nil || !strings.Contains(err.Error(), "timeout") {
+			t.Fatalf("expected a timeout error, got %v", err)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		taskID := tasks.TaskID(api.cloud.SeedTask("RUNNING", ""))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := waitTask(ctx, client, taskID, time.Minute, result)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected a canceled error, got %v", err)
+		}
+	})
+}
+
+func TestUnitCreateClientCache(t *testing.T) {
+	api := newMockAPI(t)
+	config := unitProviderConfig(t, api, nil)
+
+	resourceData := func() *schema.ResourceData {
+		return schema.TestResourceDataRaw(t, Provider().ResourcesMap["gcore_network"].Schema, map[string]interface{}{
+			"name":         "network",
+			"project_name": mockProjectName,
+			"region_name":  mockRegionName,
+		})
+	}
+
+	var wg sync.WaitGroup
+	clients := make([]*gcorecloud.ServiceClient, 10)
+	errs := make([]error, len(clients))
+	for i := range clients {
+		// ResourceData isn't safe for concurrent use, every call gets its own
+		d := resourceData()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clients[i], errs[i] = CreateClient(config, d, networksPoint, versionPointV1)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if clients[i] != clients[0] {
+			t.Fatalf("expected the cached service client to be reused")
+		}
+	}
+	if clients[0].ResourceBase != fmt.Sprintf("%s/cloud/v1/%s/%d/%d/", api.URL(), networksPoint, mockProjectID, mockRegionID) {
+		t.Fatalf("unexpected resource base %s", clients[0].ResourceBase)
+	}
+
+	if _, err := CreateClient(config, resourceData(), subnetPoint, versionPointV1); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(api.Requests("GET /cloud/v1/projects")); n != 1 {
+		t.Fatalf("expected projects to be listed once, got %d", n)
+	}
+	if n := len(api.Requests("GET /cloud/v1/regions")); n != 1 {
+		t.Fatalf("expected regions to be listed once, got %d", n)
+	}
+}